@@ -0,0 +1,68 @@
+// sapMock sirve el SAP simulado para desarrollo y CI. La API se apunta a él con SAPDB_URL:
+//
+//	go run ./cmd/sapMock -addr :8090 -fixtures ./internal/sapMock/fixtures
+//	SAPDB_URL=http://localhost:8090/http/SAP_RFC_IOT_DHM
+//
+// Los mensajes recibidos y los fallos se consultan y configuran en /_mock/ (ver sapMock).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/sapMock"
+	"go.uber.org/zap"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "dirección de escucha")
+	fixturesDir := flag.String("fixtures", "", "directorio con los ficheros de datos; vacío usa los incluidos")
+	auth := flag.String("auth", os.Getenv("SAPDB_AUTH"), "Basic auth esperada en base64; vacía acepta cualquiera")
+	latency := flag.Duration("latency", 0, "espera aplicada a todas las llamadas")
+	flag.Parse()
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		panic(err)
+	}
+	defer logger.Sync()
+
+	var fixtures *sapMock.Fixtures
+	if *fixturesDir == "" {
+		fixtures, err = sapMock.DefaultFixtures()
+	} else {
+		fixtures, err = sapMock.LoadFixtures(os.DirFS(*fixturesDir))
+	}
+	if err != nil {
+		logger.Fatal("Error cargando los datos del SAP simulado", zap.Error(err))
+	}
+
+	mock := sapMock.New(fixtures, *auth, logger)
+	mock.SetLatency(*latency)
+
+	server := &http.Server{Addr: *addr, Handler: mock}
+	go func() {
+		logger.Info("SAP simulado escuchando", zap.String("addr", *addr),
+			zap.Int("factories", len(fixtures.Lines)), zap.Int("lines_with_orders", len(fixtures.Orders)),
+			zap.Bool("auth", *auth != ""), zap.Duration("latency", *latency))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Error del SAP simulado", zap.Error(err))
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("Error deteniendo el SAP simulado", zap.Error(err))
+	}
+}
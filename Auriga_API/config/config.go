@@ -3,6 +3,7 @@ package config
 import (
 	_ "embed"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -30,6 +31,13 @@ type DatabaseConfig struct {
 	Name     string `yaml:"name"`
 	Timezone string `yaml:"timezone"`
 }
+
+// DSN cadena de conexión a PostgreSQL
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.Timezone)
+}
+
 type GrafanaConfig struct {
 	Host string `yaml:"host"`
 	Port int    `yaml:"port"`
@@ -44,8 +52,11 @@ type InfluxDBConfig struct {
 }
 
 type SapDBConfig struct {
-	Url  string `yaml:"url"`
-	Auth string `yaml:"auth"`
+	Url       string        `yaml:"url"`
+	Auth      string        `yaml:"auth"`       // Basic auth en base64 (usuario:contraseña)
+	Timeout   time.Duration `yaml:"timeout"`    // Tiempo máximo de cada llamada RFC
+	Retries   int           `yaml:"retries"`    // Reintentos de las consultas ante errores transitorios
+	RetryWait time.Duration `yaml:"retry_wait"` // Espera antes del primer reintento; se duplica en cada uno
 }
 
 type AuthConfig struct {
@@ -63,15 +74,71 @@ type WorkeraConfig struct {
 	API_Key  string `yaml:"API_KEY"`
 }
 
+type MaintenanceConfig struct {
+	SchedulerEnabled   bool          `yaml:"scheduler_enabled"`
+	SchedulerInterval  time.Duration `yaml:"scheduler_interval"`
+	SystemEmployeeID   uint          `yaml:"system_employee_id"` // Empleado que figura como creador de las OT automáticas
+	ConditionEnabled   bool          `yaml:"condition_enabled"`
+	ConditionInterval  time.Duration `yaml:"condition_interval"`
+	EventRulesEnabled  bool          `yaml:"event_rules_enabled"` // Aplica las reglas de mantenimiento a los eventos confirmados
+	EventRulesInterval time.Duration `yaml:"event_rules_interval"`
+	DefaultLaborRate   float64       `yaml:"default_labor_rate"` // Tarifa horaria si no hay una por empleado o rol
+	PublicURL          string        `yaml:"public_url"`         // URL base del frontend para los enlaces QR de los dossieres
+	SupervisorRoles    []string      `yaml:"supervisor_roles"`   // Roles que pueden fichar en nombre de otro técnico
+}
+
+type InventoryConfig struct {
+	ReorderEnabled  bool          `yaml:"reorder_enabled"`
+	ReorderInterval time.Duration `yaml:"reorder_interval"`
+	ReorderLeadTime time.Duration `yaml:"reorder_lead_time"` // Plazo de entrega previsto de las propuestas
+	ExpiryHorizon   time.Duration `yaml:"expiry_horizon"`    // Horizonte por defecto del informe de caducidades
+}
+
+type EventsConfig struct {
+	IngestEnabled         bool          `yaml:"ingest_enabled"`
+	IngestInterval        time.Duration `yaml:"ingest_interval"`
+	IngestLookback        time.Duration `yaml:"ingest_lookback"`  // Ventana leída en cada ciclo; se solapa con la anterior
+	IngestFactories       []string      `yaml:"ingest_factories"` // Vacío: todas las fábricas con InfluxDB configurado
+	StatusFields          []string      `yaml:"status_fields"`    // Campos con la palabra de estado de máquina/línea
+	StopCodes             []int         `yaml:"stop_codes"`       // Estados de parada/fallo; vacío: cualquier valor distinto de 0
+	CloseEnabled          bool          `yaml:"close_enabled"`    // Cierre automático de paradas al volver la línea a producir
+	CloseInterval         time.Duration `yaml:"close_interval"`
+	CloseLookback         time.Duration `yaml:"close_lookback"`   // Solo se cierran las paradas que empezaron dentro de esta ventana
+	ClassifyEnabled       bool          `yaml:"classify_enabled"` // Confirmación automática de eventos brutos según reglas
+	ClassifyInterval      time.Duration `yaml:"classify_interval"`
+	ClassifyMinConfidence int           `yaml:"classify_min_confidence"` // Confianza mínima de la regla para confirmar sin operador
+}
+
+type SapOutboxConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	Interval    time.Duration `yaml:"interval"`
+	BatchSize   int           `yaml:"batch_size"`   // Mensajes entregados por ciclo
+	MaxAttempts int           `yaml:"max_attempts"` // Agotados pasa a dead letter
+	BackoffBase time.Duration `yaml:"backoff_base"` // Espera tras el primer fallo; se duplica en cada intento
+	BackoffMax  time.Duration `yaml:"backoff_max"`
+	Timeout     time.Duration `yaml:"timeout"` // Tiempo máximo de cada llamada a SAP
+}
+
+type LiveConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"` // Consulta del estado y throughput de las líneas con suscriptores
+	Heartbeat    time.Duration `yaml:"heartbeat"`     // Comentario periódico que mantiene abierta la conexión en proxies
+	BufferSize   int           `yaml:"buffer_size"`   // Mensajes en cola por suscriptor; si se llena se descartan
+}
+
 type Settings struct {
-	App        App                       `yaml:"app"`
-	DB         DatabaseConfig            `yaml:"database"`
-	Grafana1   GrafanaConfig             `yaml:"grafana1"`
-	Grafana2   GrafanaConfig             `yaml:"grafana2"`
-	InfluxDBs  map[string]InfluxDBConfig `yaml:"influxdbs"` // Múltiples conexiones
-	Sap        SapDBConfig               `yaml:"sapdb"`
-	AuthConfig AuthConfig                `yaml:"auth"`
-	Workera    WorkeraConfig             `yaml:"workera"`
+	App         App                       `yaml:"app"`
+	DB          DatabaseConfig            `yaml:"database"`
+	Grafana1    GrafanaConfig             `yaml:"grafana1"`
+	Grafana2    GrafanaConfig             `yaml:"grafana2"`
+	InfluxDBs   map[string]InfluxDBConfig `yaml:"influxdbs"` // Múltiples conexiones
+	Sap         SapDBConfig               `yaml:"sapdb"`
+	AuthConfig  AuthConfig                `yaml:"auth"`
+	Workera     WorkeraConfig             `yaml:"workera"`
+	Maintenance MaintenanceConfig         `yaml:"maintenance"`
+	Inventory   InventoryConfig           `yaml:"inventory"`
+	Events      EventsConfig              `yaml:"events"`
+	Live        LiveConfig                `yaml:"live"`
+	SapOutbox   SapOutboxConfig           `yaml:"sap_outbox"`
 }
 
 func New(logger *zap.Logger) (*Settings, error) {
@@ -82,11 +149,15 @@ func New(logger *zap.Logger) (*Settings, error) {
 		logger.Error("Error al deserializar el archivo de configuración", zap.Error(err))
 		return nil, err
 	}
+	if err := s.validate(); err != nil {
+		logger.Error("Configuración no válida", zap.Error(err))
+		return nil, err
+	}
 
 	// Log de configuración cargada
 	logger.Info("Configuración cargada correctamente",
 		zap.String("SAP URL", s.Sap.Url),
-		zap.String("SAP Auth", s.Sap.Auth),
+		zap.Bool("SAP Auth", s.Sap.Auth != ""),
 		zap.String("App Name", s.App.Name),
 		zap.String("App Port", s.App.EchoPort),
 	)
@@ -153,7 +224,10 @@ func NewEnv() (*Settings, error) {
 	// Configuración de SAP
 	s_env.Sap.Url = os.Getenv("SAPDB_URL")
 	s_env.Sap.Auth = os.Getenv("SAPDB_AUTH")
-	fmt.Printf("El Token SAP es: %s\n", s_env.Sap.Auth)
+	s_env.Sap.Timeout, _ = time.ParseDuration(os.Getenv("SAPDB_TIMEOUT"))
+	s_env.Sap.Retries, _ = strconv.Atoi(os.Getenv("SAPDB_RETRIES"))
+	s_env.Sap.RetryWait, _ = time.ParseDuration(os.Getenv("SAPDB_RETRY_WAIT"))
+	fmt.Printf("El Token SAP está configurado: %t\n", s_env.Sap.Auth != "")
 
 	// Cargar múltiples configuraciones de InfluxDB
 	s_env.InfluxDBs = loadMultipleInfluxDBConfigs()
@@ -183,9 +257,81 @@ func NewEnv() (*Settings, error) {
 	fmt.Printf("Workera API_USER: %s\n", s_env.Workera.API_User)
 	// No imprimir API_KEY por seguridad
 
+	// Configuración de mantenimiento
+	s_env.Maintenance.SchedulerEnabled, _ = strconv.ParseBool(os.Getenv("MAINTENANCE_SCHEDULER_ENABLED"))
+	s_env.Maintenance.SchedulerInterval, _ = time.ParseDuration(os.Getenv("MAINTENANCE_SCHEDULER_INTERVAL"))
+	systemEmployeeID, _ := strconv.ParseUint(os.Getenv("MAINTENANCE_SYSTEM_EMPLOYEE_ID"), 10, 32)
+	s_env.Maintenance.SystemEmployeeID = uint(systemEmployeeID)
+	s_env.Maintenance.ConditionEnabled, _ = strconv.ParseBool(os.Getenv("MAINTENANCE_CONDITION_ENABLED"))
+	s_env.Maintenance.ConditionInterval, _ = time.ParseDuration(os.Getenv("MAINTENANCE_CONDITION_INTERVAL"))
+	s_env.Maintenance.EventRulesEnabled, _ = strconv.ParseBool(os.Getenv("MAINTENANCE_EVENT_RULES_ENABLED"))
+	s_env.Maintenance.EventRulesInterval, _ = time.ParseDuration(os.Getenv("MAINTENANCE_EVENT_RULES_INTERVAL"))
+	s_env.Maintenance.DefaultLaborRate, _ = strconv.ParseFloat(os.Getenv("MAINTENANCE_DEFAULT_LABOR_RATE"), 64)
+	s_env.Maintenance.PublicURL = os.Getenv("MAINTENANCE_PUBLIC_URL")
+	s_env.Maintenance.SupervisorRoles = splitList(os.Getenv("MAINTENANCE_SUPERVISOR_ROLES"))
+	fmt.Printf("Maintenance scheduler enabled: %t\n", s_env.Maintenance.SchedulerEnabled)
+	fmt.Printf("Maintenance condition monitor enabled: %t\n", s_env.Maintenance.ConditionEnabled)
+	fmt.Printf("Maintenance event rules enabled: %t\n", s_env.Maintenance.EventRulesEnabled)
+
+	// Configuración de inventario
+	s_env.Inventory.ReorderEnabled, _ = strconv.ParseBool(os.Getenv("INVENTORY_REORDER_ENABLED"))
+	s_env.Inventory.ReorderInterval, _ = time.ParseDuration(os.Getenv("INVENTORY_REORDER_INTERVAL"))
+	s_env.Inventory.ReorderLeadTime, _ = time.ParseDuration(os.Getenv("INVENTORY_REORDER_LEAD_TIME"))
+	s_env.Inventory.ExpiryHorizon, _ = time.ParseDuration(os.Getenv("INVENTORY_EXPIRY_HORIZON"))
+	fmt.Printf("Inventory reorder job enabled: %t\n", s_env.Inventory.ReorderEnabled)
+
+	// Configuración de la ingesta de eventos
+	s_env.Events.IngestEnabled, _ = strconv.ParseBool(os.Getenv("EVENTS_INGEST_ENABLED"))
+	s_env.Events.IngestInterval, _ = time.ParseDuration(os.Getenv("EVENTS_INGEST_INTERVAL"))
+	s_env.Events.IngestLookback, _ = time.ParseDuration(os.Getenv("EVENTS_INGEST_LOOKBACK"))
+	s_env.Events.IngestFactories = splitList(os.Getenv("EVENTS_INGEST_FACTORIES"))
+	s_env.Events.StatusFields = splitList(os.Getenv("EVENTS_STATUS_FIELDS"))
+	for _, code := range splitList(os.Getenv("EVENTS_STOP_CODES")) {
+		if value, err := strconv.Atoi(code); err == nil {
+			s_env.Events.StopCodes = append(s_env.Events.StopCodes, value)
+		}
+	}
+	s_env.Events.CloseEnabled, _ = strconv.ParseBool(os.Getenv("EVENTS_CLOSE_ENABLED"))
+	s_env.Events.CloseInterval, _ = time.ParseDuration(os.Getenv("EVENTS_CLOSE_INTERVAL"))
+	s_env.Events.CloseLookback, _ = time.ParseDuration(os.Getenv("EVENTS_CLOSE_LOOKBACK"))
+	s_env.Events.ClassifyEnabled, _ = strconv.ParseBool(os.Getenv("EVENTS_CLASSIFY_ENABLED"))
+	s_env.Events.ClassifyInterval, _ = time.ParseDuration(os.Getenv("EVENTS_CLASSIFY_INTERVAL"))
+	s_env.Events.ClassifyMinConfidence, _ = strconv.Atoi(os.Getenv("EVENTS_CLASSIFY_MIN_CONFIDENCE"))
+	fmt.Printf("Raw event ingestion enabled: %t\n", s_env.Events.IngestEnabled)
+	fmt.Printf("Stop event closer enabled: %t\n", s_env.Events.CloseEnabled)
+	fmt.Printf("Raw event classifier enabled: %t\n", s_env.Events.ClassifyEnabled)
+
+	// Configuración de la cola de envío de paradas a SAP
+	s_env.SapOutbox.Enabled, _ = strconv.ParseBool(os.Getenv("SAP_OUTBOX_ENABLED"))
+	s_env.SapOutbox.Interval, _ = time.ParseDuration(os.Getenv("SAP_OUTBOX_INTERVAL"))
+	s_env.SapOutbox.BatchSize, _ = strconv.Atoi(os.Getenv("SAP_OUTBOX_BATCH_SIZE"))
+	s_env.SapOutbox.MaxAttempts, _ = strconv.Atoi(os.Getenv("SAP_OUTBOX_MAX_ATTEMPTS"))
+	s_env.SapOutbox.BackoffBase, _ = time.ParseDuration(os.Getenv("SAP_OUTBOX_BACKOFF_BASE"))
+	s_env.SapOutbox.BackoffMax, _ = time.ParseDuration(os.Getenv("SAP_OUTBOX_BACKOFF_MAX"))
+	s_env.SapOutbox.Timeout, _ = time.ParseDuration(os.Getenv("SAP_OUTBOX_TIMEOUT"))
+	fmt.Printf("SAP outbox delivery enabled: %t\n", s_env.SapOutbox.Enabled)
+
+	// Configuración de la difusión en tiempo real
+	s_env.Live.PollInterval, _ = time.ParseDuration(os.Getenv("LIVE_POLL_INTERVAL"))
+	s_env.Live.Heartbeat, _ = time.ParseDuration(os.Getenv("LIVE_HEARTBEAT"))
+	s_env.Live.BufferSize, _ = strconv.Atoi(os.Getenv("LIVE_BUFFER_SIZE"))
+
+	if err := s_env.validate(); err != nil {
+		return nil, err
+	}
 	return &s_env, nil
 }
 
+// validate comprueba los valores sin los que la aplicación no puede funcionar correctamente
+func (s *Settings) validate() error {
+	// Los dossieres impresos llevan un QR con el enlace a la OT: debe ser una URL absoluta
+	publicURL, err := url.Parse(s.Maintenance.PublicURL)
+	if err != nil || publicURL.Scheme == "" || publicURL.Host == "" {
+		return fmt.Errorf("maintenance.public_url must be an absolute URL, got %q", s.Maintenance.PublicURL)
+	}
+	return nil
+}
+
 // loadMultipleInfluxDBConfigs carga todas las configuraciones de InfluxDB desde variables de entorno
 func loadMultipleInfluxDBConfigs() map[string]InfluxDBConfig {
 	configs := make(map[string]InfluxDBConfig)
@@ -223,6 +369,17 @@ func loadMultipleInfluxDBConfigs() map[string]InfluxDBConfig {
 	return configs
 }
 
+// splitList separa una lista de valores separados por comas descartando los vacíos
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // Métodos de utilidad (mantener igual)
 func (c *Settings) IsProduction() bool {
 	return strings.ToLower(c.App.Zap) == "production"
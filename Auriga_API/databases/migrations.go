@@ -0,0 +1,259 @@
+package databases
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// Cerrojo para que varias instancias no apliquen la misma migración a la vez
+const migrationLockKey = 7300
+
+// migration paso de esquema o de datos que se aplica una sola vez; el ID queda en mr_schema_migrations.
+// Los pasos nunca se editan una vez publicados: los cambios posteriores van en un paso nuevo.
+type migration struct {
+	ID      string
+	Migrate func(tx *gorm.DB) error
+}
+
+type schemaMigration struct {
+	ID        string    `gorm:"primaryKey;size:100"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "mr_schema_migrations"
+}
+
+// migrations pasos en orden de aplicación
+var migrations = []migration{
+	{
+		ID: "002_work_order_status_history",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrMaintWorkOrderStatusHistory{})
+		},
+	},
+	{
+		// Estados anteriores al flujo requested → planned → in_progress → ... → closed
+		ID: "002_work_order_status_rename",
+		Migrate: func(tx *gorm.DB) error {
+			return renameValues(tx, &rModels.MrMaintWorkOrder{}, "status", map[string]string{
+				"pending":   string(rModels.WorkOrderRequested),
+				"scheduled": string(rModels.WorkOrderPlanned),
+			})
+		},
+	},
+	{
+		// Índice único por almacén, producto y lote, y enlace entre los dos movimientos de una transferencia
+		ID: "006_spare_part_stock_ledger",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrSparePartStock{}, &rModels.MrAssetRegisterMovement{})
+		},
+	},
+	{
+		// Las reservas pendientes se asignan al stock sin caducar con más existencias del producto
+		ID: "007_reservation_stock",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&rModels.MrMaintSparePartReservation{}); err != nil {
+				return err
+			}
+			return tx.Exec(`
+				UPDATE mr_maint_spare_part_reservations r
+				SET stock_id = (
+					SELECT s.id FROM mr_spare_part_stocks s
+					WHERE s.product_id = r.product_id
+					ORDER BY (s.expiry_date IS NULL OR s.expiry_date > NOW()) DESC, s.quantity DESC, s.id
+					LIMIT 1
+				)
+				WHERE r.stock_id IS NULL AND r.status = ?`, rModels.ReservationReserved).Error
+		},
+	},
+	{
+		// Columnas de aprobación, envío y anulación, y el estado de recepción parcial con su nombre nuevo
+		ID: "008_purchase_order_lifecycle",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&rModels.MrPurchaseOrder{}, &rModels.MrPurchaseOrderItem{}); err != nil {
+				return err
+			}
+			return renameValues(tx, &rModels.MrPurchaseOrder{}, "status", map[string]string{
+				"partial": string(rModels.PurchaseOrderPartial),
+			})
+		},
+	},
+	{
+		// Tramos de fichaje con el índice único parcial que impide dos tramos abiertos del mismo empleado,
+		// e inicio y fin de la asignación
+		ID: "012_time_segments",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrMaintWorkOrderAssignment{}, &rModels.MrMaintTimeSegment{})
+		},
+	},
+	{
+		// Las asignaciones de turno sin fin se guardaban con la fecha cero; ahora el fin es nulo
+		ID: "012_shift_assignment_open_end",
+		Migrate: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasTable(&rModels.MrShiftAssignment{}) {
+				return nil
+			}
+			return tx.Model(&rModels.MrShiftAssignment{}).
+				Where("end_date < start_date").
+				UpdateColumn("end_date", gorm.Expr("NULL")).Error
+		},
+	},
+	{
+		// Cola de reglas de mantenimiento en los eventos confirmados. Los eventos anteriores ya pasaron por
+		// las reglas al confirmarse y se marcan como procesados.
+		ID: "014_commit_event_maintenance_queue",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&rModels.MrCommitEvents{}); err != nil {
+				return err
+			}
+			return tx.Model(&rModels.MrCommitEvents{}).
+				Where("maint_processed_at IS NULL").
+				UpdateColumn("maint_processed_at", gorm.Expr("NOW()")).Error
+		},
+	},
+	{
+		// Clave natural de los eventos brutos (instante y ruta), incluidos los ya confirmados. Los
+		// duplicados existentes se eliminan conservando el confirmado o, si no hay, el más antiguo.
+		ID: "015_raw_event_natural_key",
+		Migrate: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasTable(&rModels.MrRawEvents{}) {
+				return nil
+			}
+			if err := tx.Exec(`
+				DELETE FROM mr_raw_events WHERE id IN (
+					SELECT id FROM (
+						SELECT id, ROW_NUMBER() OVER (
+							PARTITION BY _time, ` + rawEventKey + `
+							ORDER BY deleted_at IS NULL, id
+						) AS n
+						FROM mr_raw_events
+					) d WHERE d.n > 1
+				)`).Error; err != nil {
+				return err
+			}
+			return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_mr_raw_events_natural_key
+				ON mr_raw_events (_time, ` + rawEventKey + `)`).Error
+		},
+	},
+	{
+		// Los eventos anteriores a la columna end_time no tienen fin registrado: se cierran en su inicio
+		// para que no cuenten como paradas abiertas hasta hoy ni los cierre el proceso automático
+		ID: "016_commit_event_legacy_end_time",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.Model(&rModels.MrCommitEvents{}).
+				Where("end_time IS NULL").
+				UpdateColumn("end_time", gorm.Expr("event_time")).Error
+		},
+	},
+	{
+		// Reglas de clasificación de eventos brutos. Confianza y activa ya no tienen valor por defecto
+		// en la base de datos: con él, GORM guardaba 0 y false como 100 y true.
+		ID: "018_event_classification_rules",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&rModels.MrEventClassificationRule{}); err != nil {
+				return err
+			}
+			return tx.Exec(`ALTER TABLE mr_event_classification_rules
+				ALTER COLUMN confidence DROP DEFAULT,
+				ALTER COLUMN active DROP DEFAULT`).Error
+		},
+	},
+	{
+		// Registro de cambios de los eventos brutos y confirmados
+		ID: "021_event_audit",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrEventAudit{})
+		},
+	},
+	{
+		// Cola de envíos de paradas a SAP con el resultado de cada intento
+		ID: "022_sap_outbox",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrSapOutbox{}, &rModels.MrSapOutboxAttempt{})
+		},
+	},
+	{
+		// Contador de uso de los planes: ruta y campo del contador y lectura de la última ejecución
+		ID: "004_plan_usage",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrMaintenancePlan{})
+		},
+	},
+	{
+		// Mantenimiento por condición: señal, comparador, histéresis, duración mínima y estado de disparo
+		ID: "005_plan_condition",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrMaintenancePlan{})
+		},
+	},
+	{
+		// Tarifas horarias por empleado o por rol para el coste de mano de obra de las OT
+		ID: "011_maint_labor_rates",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrMaintLaborRate{})
+		},
+	},
+	{
+		// Reglas que abren OT correctivas a partir de los eventos de parada de las líneas
+		ID: "014_maint_event_rules",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&rModels.MrMaintEventRule{})
+		},
+	},
+}
+
+// rawEventKey ruta del evento bruto en su clave natural; los niveles nulos cuentan como vacíos
+const rawEventKey = `COALESCE("EL_Lv0", ''), COALESCE(name, ''), COALESCE("EL_Lv1", ''), COALESCE("EL_Lv2", ''), COALESCE("EL_Lv3", '')`
+
+// migrate aplica los pasos pendientes, cada uno en su transacción
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		applied := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error; err != nil {
+				return err
+			}
+			var count int64
+			if err := tx.Model(&schemaMigration{}).Where("id = ?", m.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+			if err := m.Migrate(tx); err != nil {
+				return err
+			}
+			applied = true
+			return tx.Create(&schemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s: %w", m.ID, err)
+		}
+		if applied {
+			log.Printf("Migración aplicada: %s", m.ID)
+		}
+	}
+	return nil
+}
+
+// renameValues sustituye valores antiguos de una columna sin tocar updated_at; si la tabla aún no existe
+// no hay nada que renombrar
+func renameValues(tx *gorm.DB, model interface{}, column string, values map[string]string) error {
+	if !tx.Migrator().HasTable(model) {
+		return nil
+	}
+	for from, to := range values {
+		if err := tx.Model(model).Where(column+" = ?", from).UpdateColumn(column, to).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
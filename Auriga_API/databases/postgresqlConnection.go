@@ -22,15 +22,7 @@ import (
 
 func New(s *config.Settings, ctx context.Context) (*gorm.DB, error) {
 
-	connectionString := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=%s",
-		s.DB.Host,
-		s.DB.User,
-		s.DB.Password,
-		s.DB.Name,
-		s.DB.Port,
-		s.DB.Timezone,
-	)
+	connectionString := s.DB.DSN()
 	log.Println(connectionString)
 
 	//db, err := gorm.Open(mysql.Open(connectionString), &gorm.Config{})
@@ -54,6 +46,12 @@ func New(s *config.Settings, ctx context.Context) (*gorm.DB, error) {
 
 	log.Println("Running Migrations")
 
+	// Migraciones versionadas de las tablas y columnas nuevas; el bloque comentado queda para la carga inicial
+	if err := migrate(db); err != nil {
+		log.Println("Migration Failure:", err)
+		return db, err
+	}
+
 	/*
 	db.AutoMigrate(&rModels.MrProductionOrder{})
 	db.AutoMigrate(&rModels.MrRecipe{}, &rModels.MrComponent{}, &rModels.MrRecipeComponent{}, &rModels.MrConsumption{})
@@ -62,6 +60,9 @@ func New(s *config.Settings, ctx context.Context) (*gorm.DB, error) {
 	rEvents.DB_InitEventsCategory(db)
 	//db.AutoMigrate(&rModels.MrRawEvents{})
 	db.AutoMigrate(&rModels.MrCommitEvents{})
+	db.AutoMigrate(&rModels.MrEventClassificationRule{})
+	db.AutoMigrate(&rModels.MrEventAudit{})
+	db.AutoMigrate(&rModels.MrSapOutbox{}, &rModels.MrSapOutboxAttempt{})
 
 	db.AutoMigrate(&rOthers.MrGrafanaDashboards{})
 	rOthers.DB_InitGrafanaDashboards(db)
@@ -94,8 +95,13 @@ func New(s *config.Settings, ctx context.Context) (*gorm.DB, error) {
 	   		&rModels.MrMaintenanceSparePart{},
 	   		&rModels.MrMaintWorkOrder{},
 	   		&rModels.MrMaintWorkOrderAssignment{},
+	   		&rModels.MrMaintTimeSegment{},
 	   		&rModels.MrMaintWorkOrderTask{},
 	   		&rModels.MrMaintWorkOrderSparePart{},
+	   		&rModels.MrMaintWorkOrderStatusHistory{},
+	   		&rModels.MrMaintSparePartReservation{},
+	   		&rModels.MrMaintLaborRate{},
+	   		&rModels.MrMaintEventRule{},
 	   		&rModels.MrSparePartStock{},
 	   		&rModels.MrAssetRegisterMovement{},
 	   		&rModels.MrPurchaseOrder{},
@@ -44,17 +44,44 @@ func (h *handler) RegisterRoutes(e *echo.Echo, s *config.Settings) {
 	r := e.Group("/events")
 	/*middlewares*/
 	//r.Use(middleware.JWTWithConfig(jwtconfig))
+	// Solo las rutas que consultan o deshacen el registro de cambios exigen usuario; el resto sigue
+	// abierto como antes para los clientes existentes
+	auth := h.authMiddleware.CombinedMiddleware()
 
 	r.GET("/raw", h.EventsRawByLineList)
 	r.GET("/raw/tocommit", h.EventsRawToCommitLine)
 	r.GET("/raw/del", h.EventsRawByLineDel)
+	r.POST("/raw/bulk/commit", h.EventsRawBulkCommit)
+	r.POST("/raw/bulk/classify", h.EventsRawBulkClassify)
+	r.POST("/raw/bulk/discard", h.EventsRawBulkDiscard)
+	r.GET("/raw/:id/history", h.GetRawEventHistory, auth)
+	r.POST("/raw/:id/restore", h.RestoreRawEvent, auth)
 
 	r.GET("/commit", h.EventsCommitByLineList)
 	r.GET("/commit/add", h.EventsCommitByLineAdd)
 	r.GET("/commit/update", h.EventsCommitByLineUpdate)
 	r.GET("/commit/del", h.EventsCommitByLineDel)
+	r.GET("/commit/close", h.EventsCommitByLineClose)
+	r.POST("/commit/bulk/merge", h.EventsCommitBulkMerge)
+	r.POST("/commit/bulk/split", h.EventsCommitBulkSplit)
+	r.POST("/commit/bulk/discard", h.EventsCommitBulkDiscard)
+	r.GET("/commit/:id/history", h.GetCommitEventHistory, auth)
+	r.POST("/commit/:id/restore", h.RestoreCommitEvent, auth)
+
+	r.GET("/downtime", h.GetDowntimeTotals)
+	r.GET("/analytics", h.GetEventAnalytics)
+
+	r.GET("/classification-rules", h.GetClassificationRules)
+	r.POST("/classification-rules", h.CreateClassificationRule)
+	r.PUT("/classification-rules/:id", h.UpdateClassificationRule)
+	r.DELETE("/classification-rules/:id", h.DeleteClassificationRule)
 
 	r.GET("/sapCommit", h.EventsSapCommit)
+	r.GET("/sap/outbox", h.ListSapOutbox)
+	r.GET("/sap/outbox/dead", h.ListSapOutboxDead)
+	r.GET("/sap/outbox/:id", h.GetSapOutboxMessage)
+	r.POST("/sap/outbox/:id/retry", h.RetrySapOutboxMessage)
+	r.POST("/sap/outbox/:id/discard", h.DiscardSapOutboxMessage)
 
 	r.GET("/categories", h.GetAllCategoriesWithEventTypes)
 
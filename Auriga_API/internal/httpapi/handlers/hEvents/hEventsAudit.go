@@ -0,0 +1,89 @@
+package hEvents
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
+)
+
+func (h *handler) GetRawEventHistory(c echo.Context) error {
+	return h.eventHistory(c, rModels.EventAuditRaw)
+}
+
+func (h *handler) GetCommitEventHistory(c echo.Context) error {
+	return h.eventHistory(c, rModels.EventAuditCommit)
+}
+
+func (h *handler) eventHistory(c echo.Context, kind string) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid event ID"})
+	}
+
+	history, err := h.service.GetEventHistory(kind, uint(id))
+	if err != nil {
+		return auditError(c, err, "Failed to get event history")
+	}
+	return c.JSON(http.StatusOK, history)
+}
+
+func (h *handler) RestoreRawEvent(c echo.Context) error {
+	id, req, err := bindRestoreRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	event, err := h.service.RestoreRawEvent(c.Request().Context(), id, req.Reason)
+	if err != nil {
+		return auditError(c, err, "Failed to restore raw event")
+	}
+	return c.JSON(http.StatusOK, event)
+}
+
+func (h *handler) RestoreCommitEvent(c echo.Context) error {
+	id, req, err := bindRestoreRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	event, err := h.service.RestoreCommitEvent(c.Request().Context(), id, req.Reason)
+	if err != nil {
+		return auditError(c, err, "Failed to restore event")
+	}
+	return c.JSON(http.StatusOK, event)
+}
+
+func bindRestoreRequest(c echo.Context) (uint, sEvents.RestoreEventRequest, error) {
+	var req sEvents.RestoreEventRequest
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, req, errors.New("Invalid event ID")
+	}
+	if err := c.Bind(&req); err != nil {
+		return 0, req, errors.New("Invalid request body")
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return 0, req, err
+	}
+	return uint(id), req, nil
+}
+
+// auditError traduce los errores del servicio a códigos HTTP
+func auditError(c echo.Context, err error, failMsg string) error {
+	switch {
+	case errors.Is(err, sEvents.ErrNotFound):
+		return c.JSON(http.StatusNotFound, responseMessage{Message: "Event not found"})
+	case errors.Is(err, sEvents.ErrInvalidRequest):
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	case errors.Is(err, sEvents.ErrConflict):
+		return c.JSON(http.StatusConflict, responseMessage{Message: err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, responseMessage{Message: failMsg})
+	}
+}
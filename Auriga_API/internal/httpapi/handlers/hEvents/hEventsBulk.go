@@ -0,0 +1,116 @@
+package hEvents
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
+)
+
+func (h *handler) EventsRawBulkCommit(c echo.Context) error {
+	var req sEvents.BulkRawCommitRequest
+	if err := bindBulkRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	report, err := h.service.EventsRawBulkCommit(c.Request().Context(), req)
+	if err != nil {
+		return bulkError(c, err)
+	}
+	return c.JSON(bulkStatus(report.Applied), report)
+}
+
+func (h *handler) EventsRawBulkClassify(c echo.Context) error {
+	var req sEvents.BulkIDsRequest
+	if err := bindBulkRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	report, err := h.service.EventsRawBulkClassify(c.Request().Context(), req)
+	if err != nil {
+		return bulkError(c, err)
+	}
+	return c.JSON(bulkStatus(report.Applied), report)
+}
+
+func (h *handler) EventsRawBulkDiscard(c echo.Context) error {
+	var req sEvents.BulkIDsRequest
+	if err := bindBulkRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	report, err := h.service.EventsRawBulkDiscard(c.Request().Context(), req)
+	if err != nil {
+		return bulkError(c, err)
+	}
+	return c.JSON(bulkStatus(report.Applied), report)
+}
+
+func (h *handler) EventsCommitBulkMerge(c echo.Context) error {
+	var req sEvents.BulkIDsRequest
+	if err := bindBulkRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	report, err := h.service.EventsCommitBulkMerge(c.Request().Context(), req)
+	if err != nil {
+		return bulkError(c, err)
+	}
+	return c.JSON(bulkStatus(report.Applied), report)
+}
+
+func (h *handler) EventsCommitBulkSplit(c echo.Context) error {
+	var req sEvents.BulkSplitRequest
+	if err := bindBulkRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	report, err := h.service.EventsCommitBulkSplit(c.Request().Context(), req)
+	if err != nil {
+		return bulkError(c, err)
+	}
+	return c.JSON(bulkStatus(report.Applied), report)
+}
+
+func (h *handler) EventsCommitBulkDiscard(c echo.Context) error {
+	var req sEvents.BulkIDsRequest
+	if err := bindBulkRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	report, err := h.service.EventsCommitBulkDiscard(c.Request().Context(), req)
+	if err != nil {
+		return bulkError(c, err)
+	}
+	return c.JSON(bulkStatus(report.Applied), report)
+}
+
+func bindBulkRequest(c echo.Context, req interface{}) error {
+	if err := c.Bind(req); err != nil {
+		return errors.New("Invalid request body")
+	}
+
+	// Validación usando Echo con CustomValidator
+	return c.Validate(req)
+}
+
+// bulkStatus el informe se devuelve siempre; si algún elemento no es válido no se ha aplicado nada
+func bulkStatus(applied bool) int {
+	if applied {
+		return http.StatusOK
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// bulkError traduce los errores del servicio a códigos HTTP
+func bulkError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, sEvents.ErrInvalidRequest):
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	case errors.Is(err, sEvents.ErrConflict):
+		return c.JSON(http.StatusConflict, responseMessage{Message: err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, responseMessage{Message: "Failed to apply bulk operation"})
+	}
+}
@@ -0,0 +1,83 @@
+package hEvents
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
+)
+
+func (h *handler) GetClassificationRules(c echo.Context) error {
+	rules, err := h.service.GetClassificationRules()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, responseMessage{Message: "Failed to get classification rules"})
+	}
+	return c.JSON(http.StatusOK, rules)
+}
+
+func (h *handler) CreateClassificationRule(c echo.Context) error {
+	var req sEvents.ClassificationRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	rule, err := h.service.CreateClassificationRule(req)
+	if err != nil {
+		return classificationError(c, err, "Failed to create classification rule")
+	}
+	return c.JSON(http.StatusCreated, rule)
+}
+
+func (h *handler) UpdateClassificationRule(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid classification rule ID"})
+	}
+
+	var req sEvents.ClassificationRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	rule, err := h.service.UpdateClassificationRule(uint(id), req)
+	if err != nil {
+		return classificationError(c, err, "Failed to update classification rule")
+	}
+	return c.JSON(http.StatusOK, rule)
+}
+
+func (h *handler) DeleteClassificationRule(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid classification rule ID"})
+	}
+
+	if err := h.service.DeleteClassificationRule(uint(id)); err != nil {
+		return classificationError(c, err, "Failed to delete classification rule")
+	}
+	return c.JSON(http.StatusOK, responseMessage{Message: "Classification rule deleted successfully"})
+}
+
+// classificationError traduce los errores del servicio a códigos HTTP
+func classificationError(c echo.Context, err error, failMsg string) error {
+	switch {
+	case errors.Is(err, sEvents.ErrNotFound):
+		return c.JSON(http.StatusNotFound, responseMessage{Message: "Classification rule not found"})
+	case errors.Is(err, sEvents.ErrInvalidRequest):
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, responseMessage{Message: failMsg})
+	}
+}
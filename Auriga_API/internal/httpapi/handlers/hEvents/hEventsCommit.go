@@ -1,6 +1,7 @@
 package hEvents
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -8,10 +9,12 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
 )
 
 type mhLineEvents struct {
 	EventTime string `json:"eventtime" 	form:"eventtime" 	query:"eventtime"`
+	EndTime   string `json:"endtime" form:"endtime" query:"endtime"`
 	Factory   string `json:"factory" 	form:"factory" 		query:"factory"`
 	ProdLine  string `json:"prodline" 	form:"prodline" 	query:"prodline"`
 	System    string `json:"system" 	form:"system" 		query:"system"`
@@ -20,6 +23,7 @@ type mhLineEvents struct {
 	Type      string `json:"type" 		form:"type" 		query:"type"`
 	Category  string `json:"category" 	form:"category" 	query:"category"`
 	ID        string `json:"id" 		form:"id" 		query:"id"`
+	Reason    string `json:"reason" form:"reason" query:"reason"` // Motivo que queda en la historia del evento
 }
 
 func (h *handler) EventsCommitByLineList(c echo.Context) error {
@@ -41,6 +45,7 @@ func (h *handler) EventsCommitByLineList(c echo.Context) error {
 func (h *handler) EventsCommitByLineAdd(c echo.Context) error {
 	u := new(mhLineEvents)
 	u.EventTime = c.Request().Header.Get("EventTime")
+	u.EndTime = c.Request().Header.Get("EndTime")
 	u.Factory = c.Request().Header.Get("Factory")
 	u.ProdLine = c.Request().Header.Get("ProdLine")
 	u.System = c.Request().Header.Get("System")
@@ -48,6 +53,7 @@ func (h *handler) EventsCommitByLineAdd(c echo.Context) error {
 	u.Part = c.Request().Header.Get("Part")
 	u.Type = c.Request().Header.Get("Type")
 	u.Category = c.Request().Header.Get("Category")
+	u.Reason = c.Request().Header.Get("Reason")
 
 	log.Println("EventTime", u.EventTime)
 	log.Println("Factory", u.Factory)
@@ -59,8 +65,12 @@ func (h *handler) EventsCommitByLineAdd(c echo.Context) error {
 	log.Println("Category", u.Category)
 
 	eventTime, _ := time.Parse(time.RFC3339, u.EventTime)
+	endTime, err := parseEndTime(u.EndTime)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "EndTime no válido, formato RFC3339"})
+	}
 
-	use, err := h.service.EventsCommitByLineAdd(eventTime, u.Factory, u.ProdLine, u.System, u.Machine, u.Part, u.Type, u.Category)
+	use, err := h.service.EventsCommitByLineAdd(c.Request().Context(), eventTime, endTime, u.Factory, u.ProdLine, u.System, u.Machine, u.Part, u.Type, u.Category, u.Reason)
 	if err != nil {
 		fmt.Println("registo no: %w", err)
 		return c.JSON(http.StatusInternalServerError, responseMessage{Message: "Registro no Añadido Handler  EventsCommitByLineAdd"})
@@ -74,6 +84,7 @@ func (h *handler) EventsCommitByLineUpdate(c echo.Context) error {
 	u := new(mhLineEvents)
 	u.ID = c.Request().Header.Get("ID")
 	u.EventTime = c.Request().Header.Get("EventTime")
+	u.EndTime = c.Request().Header.Get("EndTime")
 	u.Factory = c.Request().Header.Get("Factory")
 	u.ProdLine = c.Request().Header.Get("ProdLine")
 	u.System = c.Request().Header.Get("System")
@@ -81,6 +92,7 @@ func (h *handler) EventsCommitByLineUpdate(c echo.Context) error {
 	u.Part = c.Request().Header.Get("Part")
 	u.Type = c.Request().Header.Get("Type")
 	u.Category = c.Request().Header.Get("Category")
+	u.Reason = c.Request().Header.Get("Reason")
 
 	log.Println("ID", u.ID)
 	log.Println("EventTime", u.EventTime)
@@ -96,10 +108,14 @@ func (h *handler) EventsCommitByLineUpdate(c echo.Context) error {
 	uint64_ID, _ := strconv.ParseUint(u.ID, 10, 32)
 	uint_ID := uint(uint64_ID)
 	eventTime, _ := time.Parse(time.RFC3339, u.EventTime)
+	endTime, err := parseEndTime(u.EndTime)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "EndTime no válido, formato RFC3339"})
+	}
 	// log.Println(StarteddAtTime)
 	// log.Println(FinishedAtTime)
 
-	use, err := h.service.EventsCommitByLineUpdate(uint_ID, eventTime, u.Factory, u.ProdLine, u.System, u.Machine, u.Part, u.Type, u.Category)
+	use, err := h.service.EventsCommitByLineUpdate(c.Request().Context(), uint_ID, eventTime, endTime, u.Factory, u.ProdLine, u.System, u.Machine, u.Part, u.Type, u.Category, u.Reason)
 	if err != nil {
 		fmt.Println("registo no: %w", err)
 		return c.JSON(http.StatusForbidden, responseMessage{Message: "Registro no Actualizado Handler EventsCommitByLineUpdate"})
@@ -110,16 +126,58 @@ func (h *handler) EventsCommitByLineUpdate(c echo.Context) error {
 func (h *handler) EventsCommitByLineDel(c echo.Context) error {
 	u := new(mhLineEvents)
 	u.ID = c.Request().Header.Get("ID")
+	u.Reason = c.Request().Header.Get("Reason")
 
 	log.Println("ID", u.ID)
 
 	uint64_ID, _ := strconv.ParseUint(u.ID, 10, 32)
 	uint_ID := uint(uint64_ID)
 
-	use, err := h.service.EventsCommitByLineDel(uint_ID)
+	use, err := h.service.EventsCommitByLineDel(c.Request().Context(), uint_ID, u.Reason)
 	if err != nil {
 		fmt.Println("registo no: %w", err)
 		return c.JSON(http.StatusForbidden, responseMessage{Message: "Registro no Borrado Handler EventsCommitByLineDel"})
 	}
 	return c.JSON(http.StatusOK, use)
 }
+
+// EventsCommitByLineClose cierra un evento abierto; sin EndTime se cierra en el instante actual
+func (h *handler) EventsCommitByLineClose(c echo.Context) error {
+	u := new(mhLineEvents)
+	u.ID = c.Request().Header.Get("ID")
+	u.EndTime = c.Request().Header.Get("EndTime")
+	u.Reason = c.Request().Header.Get("Reason")
+
+	uint64_ID, err := strconv.ParseUint(u.ID, 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "ID no válido"})
+	}
+	endTime, err := parseEndTime(u.EndTime)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "EndTime no válido, formato RFC3339"})
+	}
+	if endTime == nil {
+		now := time.Now()
+		endTime = &now
+	}
+
+	if err := h.service.EventsCommitByLineClose(c.Request().Context(), uint(uint64_ID), *endTime, u.Reason); err != nil {
+		if errors.Is(err, sEvents.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, responseMessage{Message: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, responseMessage{Message: "Registro no Cerrado Handler EventsCommitByLineClose"})
+	}
+	return c.JSON(http.StatusOK, responseMessage{Message: "Event closed successfully"})
+}
+
+// parseEndTime interpreta la cabecera EndTime; vacía significa evento abierto al crearlo y fin sin cambios al actualizarlo
+func parseEndTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	endTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &endTime, nil
+}
@@ -0,0 +1,69 @@
+package hEvents
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
+)
+
+// GetDowntimeTotals devuelve el tiempo de parada de la fábrica agrupado por línea, turno, categoría u
+// orden de fabricación. Cabeceras: Factory, ProdLine (opcional), From y To (RFC3339) y GroupBy.
+func (h *handler) GetDowntimeTotals(c echo.Context) error {
+	factory := c.Request().Header.Get("Factory")
+	prodLine := c.Request().Header.Get("ProdLine")
+	groupBy := c.Request().Header.Get("GroupBy")
+	if groupBy == "" {
+		groupBy = sEvents.DowntimeByLine
+	}
+
+	from, err := time.Parse(time.RFC3339, c.Request().Header.Get("From"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "From no válido, formato RFC3339"})
+	}
+	to, err := time.Parse(time.RFC3339, c.Request().Header.Get("To"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "To no válido, formato RFC3339"})
+	}
+
+	report, err := h.service.GetDowntimeTotals(factory, prodLine, from, to, groupBy)
+	if err != nil {
+		if errors.Is(err, sEvents.ErrInvalidRequest) {
+			return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, responseMessage{Message: "Informe de paradas no generado Handler GetDowntimeTotals"})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// GetEventAnalytics devuelve el Pareto de paradas por categoría, tipo, sistema, máquina y pieza, los
+// totales por turno y orden de fabricación y la tendencia. Cabeceras: Factory, ProdLine (opcional),
+// From y To (RFC3339) e Interval (day o week).
+func (h *handler) GetEventAnalytics(c echo.Context) error {
+	factory := c.Request().Header.Get("Factory")
+	prodLine := c.Request().Header.Get("ProdLine")
+	interval := c.Request().Header.Get("Interval")
+	if interval == "" {
+		interval = sEvents.TrendByDay
+	}
+
+	from, err := time.Parse(time.RFC3339, c.Request().Header.Get("From"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "From no válido, formato RFC3339"})
+	}
+	to, err := time.Parse(time.RFC3339, c.Request().Header.Get("To"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "To no válido, formato RFC3339"})
+	}
+
+	analytics, err := h.service.GetEventAnalytics(factory, prodLine, from, to, interval)
+	if err != nil {
+		if errors.Is(err, sEvents.ErrInvalidRequest) {
+			return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, responseMessage{Message: "Análisis de eventos no generado Handler GetEventAnalytics"})
+	}
+	return c.JSON(http.StatusOK, analytics)
+}
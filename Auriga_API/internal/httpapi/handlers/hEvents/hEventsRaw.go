@@ -18,11 +18,37 @@ type Handler interface {
 	EventsRawByLineList(c echo.Context) error
 	EventsRawToCommitLine(c echo.Context) error
 	EventsRawByLineDel(c echo.Context) error
+	EventsRawBulkCommit(c echo.Context) error
+	EventsRawBulkClassify(c echo.Context) error
+	EventsRawBulkDiscard(c echo.Context) error
 
 	EventsCommitByLineList(c echo.Context) error
 	EventsCommitByLineAdd(c echo.Context) error
 	EventsCommitByLineUpdate(c echo.Context) error
 	EventsCommitByLineDel(c echo.Context) error
+	EventsCommitByLineClose(c echo.Context) error
+	EventsCommitBulkMerge(c echo.Context) error
+	EventsCommitBulkSplit(c echo.Context) error
+	EventsCommitBulkDiscard(c echo.Context) error
+
+	GetRawEventHistory(c echo.Context) error
+	GetCommitEventHistory(c echo.Context) error
+	RestoreRawEvent(c echo.Context) error
+	RestoreCommitEvent(c echo.Context) error
+
+	ListSapOutbox(c echo.Context) error
+	ListSapOutboxDead(c echo.Context) error
+	GetSapOutboxMessage(c echo.Context) error
+	RetrySapOutboxMessage(c echo.Context) error
+	DiscardSapOutboxMessage(c echo.Context) error
+
+	GetDowntimeTotals(c echo.Context) error
+	GetEventAnalytics(c echo.Context) error
+
+	GetClassificationRules(c echo.Context) error
+	CreateClassificationRule(c echo.Context) error
+	UpdateClassificationRule(c echo.Context) error
+	DeleteClassificationRule(c echo.Context) error
 
 	GetAllCategoriesWithEventTypes(c echo.Context) error
 	GetCategoryWithEventTypesByName(c echo.Context) error
@@ -54,6 +80,7 @@ func (h *handler) EventsRawToCommitLine(c echo.Context) error {
 	u.Machine = c.Request().Header.Get("Machine")
 	u.Part = c.Request().Header.Get("Part")
 	u.Type = c.Request().Header.Get("Type")
+	u.Reason = c.Request().Header.Get("Reason")
 
 	log.Println("ID", u.ID)
 	log.Println("EventTime", u.EventTime)
@@ -71,7 +98,7 @@ func (h *handler) EventsRawToCommitLine(c echo.Context) error {
 	// log.Println(StarteddAtTime)
 	// log.Println(FinishedAtTime)
 
-	use, err := h.service.EventsRawToCommitLine(uint_ID, eventTime, u.Factory, u.ProdLine, u.System, u.Machine, u.Part, u.Type)
+	use, err := h.service.EventsRawToCommitLine(c.Request().Context(), uint_ID, eventTime, u.Factory, u.ProdLine, u.System, u.Machine, u.Part, u.Type, u.Reason)
 	if err != nil {
 		fmt.Println("registo no: %w", err)
 		return c.JSON(http.StatusForbidden, responseMessage{Message: "Registro no Actualizado Handler EventsCommitByLineUpdate"})
@@ -82,13 +109,14 @@ func (h *handler) EventsRawToCommitLine(c echo.Context) error {
 func (h *handler) EventsRawByLineDel(c echo.Context) error {
 	u := new(mhLineEvents)
 	u.ID = c.Request().Header.Get("ID")
+	u.Reason = c.Request().Header.Get("Reason")
 
 	log.Println("ID", u.ID)
 
 	uint64_ID, _ := strconv.ParseUint(u.ID, 10, 32)
 	uint_ID := uint(uint64_ID)
 
-	use, err := h.service.EventsRawByLineDel(uint_ID)
+	use, err := h.service.EventsRawByLineDel(c.Request().Context(), uint_ID, u.Reason)
 	if err != nil {
 		fmt.Println("registo no: %w", err)
 		return c.JSON(http.StatusForbidden, responseMessage{Message: "Registro no Borrado Handler EventsCommitByLineDel"})
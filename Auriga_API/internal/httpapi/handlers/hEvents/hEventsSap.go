@@ -1,12 +1,15 @@
 package hEvents
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
+	"github.com/remrafvil/Auriga_API/internal/services/sLabor"
 )
 
 func (h *handler) EventsSapCommit(c echo.Context) error {
@@ -20,9 +23,15 @@ func (h *handler) EventsSapCommit(c echo.Context) error {
 	uint64_ID, _ := strconv.ParseUint(u.ID, 10, 32)
 	uint_ID := uint(uint64_ID)
 
-	use, err := h.service.EventsSapByLineDel(uint_ID)
+	use, err := h.service.EventsSapByLineDel(c.Request().Context(), uint_ID)
 	if err != nil {
 		fmt.Println("registo no: %w", err)
+		if errors.Is(err, sEvents.ErrConflict) {
+			return c.JSON(http.StatusConflict, responseMessage{Message: err.Error()})
+		}
+		if errors.Is(err, sLabor.ErrNoOperator) || errors.Is(err, sLabor.ErrNoWorkdayID) {
+			return c.JSON(http.StatusForbidden, responseMessage{Message: "SAP submissions require an authenticated user with a WorkdayID"})
+		}
 		return c.JSON(http.StatusForbidden, responseMessage{Message: "Registro no Borrado Handler EventsCommitByLineDel"})
 	}
 	return c.JSON(http.StatusOK, use)
@@ -0,0 +1,96 @@
+package hEvents
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
+)
+
+// ListSapOutbox cola de envíos a SAP; status (pending por defecto, delivered, dead, discarded) y factory opcionales
+func (h *handler) ListSapOutbox(c echo.Context) error {
+	status := c.QueryParam("status")
+	if status == "" {
+		status = string(rModels.SapOutboxPending)
+	}
+	return h.listSapOutbox(c, status)
+}
+
+// ListSapOutboxDead mensajes que agotaron los reintentos y esperan reintento o descarte manual
+func (h *handler) ListSapOutboxDead(c echo.Context) error {
+	return h.listSapOutbox(c, string(rModels.SapOutboxDead))
+}
+
+func (h *handler) listSapOutbox(c echo.Context, status string) error {
+	messages, err := h.service.ListSapOutbox(status, c.QueryParam("factory"))
+	if err != nil {
+		return outboxError(c, err, "Failed to get SAP outbox")
+	}
+	return c.JSON(http.StatusOK, messages)
+}
+
+func (h *handler) GetSapOutboxMessage(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid outbox message ID"})
+	}
+
+	message, err := h.service.GetSapOutboxMessage(uint(id))
+	if err != nil {
+		return outboxError(c, err, "Failed to get SAP outbox message")
+	}
+	return c.JSON(http.StatusOK, message)
+}
+
+func (h *handler) RetrySapOutboxMessage(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid outbox message ID"})
+	}
+
+	message, err := h.service.RetrySapOutboxMessage(uint(id))
+	if err != nil {
+		return outboxError(c, err, "Failed to retry SAP outbox message")
+	}
+	return c.JSON(http.StatusOK, message)
+}
+
+func (h *handler) DiscardSapOutboxMessage(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid outbox message ID"})
+	}
+
+	var req sEvents.DiscardSapOutboxRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	}
+
+	message, err := h.service.DiscardSapOutboxMessage(c.Request().Context(), uint(id), req.Reason)
+	if err != nil {
+		return outboxError(c, err, "Failed to discard SAP outbox message")
+	}
+	return c.JSON(http.StatusOK, message)
+}
+
+// outboxError traduce los errores del servicio a códigos HTTP
+func outboxError(c echo.Context, err error, failMsg string) error {
+	switch {
+	case errors.Is(err, sEvents.ErrNotFound):
+		return c.JSON(http.StatusNotFound, responseMessage{Message: "SAP outbox message not found"})
+	case errors.Is(err, sEvents.ErrInvalidRequest):
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: err.Error()})
+	case errors.Is(err, sEvents.ErrConflict):
+		return c.JSON(http.StatusConflict, responseMessage{Message: err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, responseMessage{Message: failMsg})
+	}
+}
@@ -0,0 +1,99 @@
+package hInventory
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/config"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/middlewares"
+	"github.com/remrafvil/Auriga_API/internal/services/sAuth"
+	"github.com/remrafvil/Auriga_API/internal/services/sInventory"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service        sInventory.Service
+	authService    sAuth.Service
+	authMiddleware *middlewares.AuthMiddleware
+	logger         *zap.Logger
+}
+
+type Result struct {
+	fx.Out
+
+	Handler handlers.Handler `group:"handlers"`
+}
+
+type Params struct {
+	fx.In
+
+	Service        sInventory.Service
+	AuthService    sAuth.Service
+	AuthMiddleware *middlewares.AuthMiddleware
+	Logger         *zap.Logger
+}
+
+func New(p Params) Result {
+	return Result{
+		Handler: &handler{
+			service:        p.Service,
+			authService:    p.AuthService,
+			authMiddleware: p.AuthMiddleware,
+			logger:         p.Logger,
+		},
+	}
+}
+
+func (h *handler) RegisterRoutes(e *echo.Echo, s *config.Settings) {
+	r := e.Group("/inventory")
+	/*middlewares*/
+	r.Use(h.authMiddleware.CombinedMiddleware())
+
+	// Stock routes
+	r.GET("/stocks", h.GetStocks)
+	r.GET("/stocks/expiring", h.GetExpiringStocks)
+	r.GET("/stocks/:id", h.GetStock)
+
+	// Movement routes
+	r.GET("/movements", h.GetMovements)
+	r.GET("/products/:id/movements", h.GetProductMovements)
+	r.GET("/warehouses/:id/movements", h.GetWarehouseMovements)
+	r.GET("/work-orders/:id/movements", h.GetWorkOrderMovements)
+	r.POST("/movements/receipt", h.ReceiveStock)
+	r.POST("/movements/issue", h.IssueStock)
+	r.POST("/movements/return", h.ReturnStock)
+	r.POST("/movements/transfer", h.TransferStock)
+	r.POST("/movements/adjustment", h.AdjustStock)
+
+	// Reorder routes
+	r.GET("/reorder/proposals", h.GetReorderProposals)
+	r.POST("/reorder/proposals", h.GenerateReorderPurchaseOrders)
+
+	// Purchase order routes
+	r.GET("/purchase-orders", h.GetPurchaseOrders)
+	r.GET("/purchase-orders/:id", h.GetPurchaseOrder)
+	r.POST("/purchase-orders", h.CreatePurchaseOrder)
+	r.POST("/purchase-orders/:id/approve", h.ApprovePurchaseOrder)
+	r.POST("/purchase-orders/:id/send", h.SendPurchaseOrder)
+	r.POST("/purchase-orders/:id/receipts", h.ReceivePurchaseOrder)
+	r.POST("/purchase-orders/:id/cancel", h.CancelPurchaseOrder)
+}
+
+// domainErrors códigos HTTP de los errores de dominio de sInventory
+var domainErrors = handlers.DomainErrors{
+	sInventory.ErrNotFound:          http.StatusNotFound,
+	sInventory.ErrInvalidRequest:    http.StatusBadRequest,
+	sInventory.ErrInsufficientStock: http.StatusConflict,
+	sInventory.ErrInvalidStatus:     http.StatusConflict,
+	sInventory.ErrExpiredBatch:      http.StatusConflict,
+}
+
+func (h *handler) currentEmployeeID(c echo.Context) (uint, error) {
+	return handlers.CurrentEmployeeID(c, h.authService)
+}
+
+func (h *handler) serviceError(c echo.Context, err error, notFoundMsg string, failMsg string) error {
+	return handlers.ServiceError(c, h.logger, domainErrors, err, notFoundMsg, failMsg)
+}
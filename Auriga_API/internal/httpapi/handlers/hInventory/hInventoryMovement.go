@@ -0,0 +1,167 @@
+package hInventory
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sInventory"
+	"go.uber.org/zap"
+)
+
+func (h *handler) GetMovements(c echo.Context) error {
+	var req sInventory.MovementFilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+	return h.movements(c, req)
+}
+
+func (h *handler) GetProductMovements(c echo.Context) error {
+	var req sInventory.MovementFilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid product ID"})
+	}
+	req.ProductID = id
+
+	return h.movements(c, req)
+}
+
+func (h *handler) GetWarehouseMovements(c echo.Context) error {
+	var req sInventory.MovementFilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid warehouse ID"})
+	}
+	req.WarehouseAssetID = id
+
+	return h.movements(c, req)
+}
+
+func (h *handler) GetWorkOrderMovements(c echo.Context) error {
+	var req sInventory.MovementFilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+	req.WorkOrderID = id
+
+	return h.movements(c, req)
+}
+
+func (h *handler) movements(c echo.Context, req sInventory.MovementFilterRequest) error {
+	movements, err := h.service.GetMovements(req)
+	if err != nil {
+		return h.serviceError(c, err, "Movements not found", "Failed to get stock movements")
+	}
+	return c.JSON(http.StatusOK, movements)
+}
+
+func (h *handler) ReceiveStock(c echo.Context) error {
+	var req sInventory.ReceiptRequest
+	employeeID, ok, err := h.bindMovement(c, &req)
+	if !ok {
+		return err
+	}
+
+	movement, err := h.service.ReceiveStock(req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Stock not found", "Failed to receive stock")
+	}
+
+	return c.JSON(http.StatusCreated, movement)
+}
+
+func (h *handler) IssueStock(c echo.Context) error {
+	var req sInventory.IssueRequest
+	employeeID, ok, err := h.bindMovement(c, &req)
+	if !ok {
+		return err
+	}
+
+	movement, err := h.service.IssueStock(req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Stock or work order not found", "Failed to issue stock")
+	}
+
+	return c.JSON(http.StatusCreated, movement)
+}
+
+func (h *handler) ReturnStock(c echo.Context) error {
+	var req sInventory.ReturnRequest
+	employeeID, ok, err := h.bindMovement(c, &req)
+	if !ok {
+		return err
+	}
+
+	movement, err := h.service.ReturnStock(req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Stock or work order not found", "Failed to return stock")
+	}
+
+	return c.JSON(http.StatusCreated, movement)
+}
+
+func (h *handler) TransferStock(c echo.Context) error {
+	var req sInventory.TransferRequest
+	employeeID, ok, err := h.bindMovement(c, &req)
+	if !ok {
+		return err
+	}
+
+	movements, err := h.service.TransferStock(req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Stock not found", "Failed to transfer stock")
+	}
+
+	return c.JSON(http.StatusCreated, movements)
+}
+
+func (h *handler) AdjustStock(c echo.Context) error {
+	var req sInventory.AdjustmentRequest
+	employeeID, ok, err := h.bindMovement(c, &req)
+	if !ok {
+		return err
+	}
+
+	movement, err := h.service.AdjustStock(req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Stock not found", "Failed to adjust stock")
+	}
+
+	return c.JSON(http.StatusCreated, movement)
+}
+
+// bindMovement lee y valida el cuerpo del movimiento y resuelve el empleado que lo registra.
+// Si ok es false la respuesta de error ya se ha escrito y se devuelve en err.
+func (h *handler) bindMovement(c echo.Context, req interface{}) (uint, bool, error) {
+	if err := c.Bind(req); err != nil {
+		return 0, false, c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return 0, false, c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	employeeID, err := h.currentEmployeeID(c)
+	if err != nil {
+		h.logger.Warn("Cannot resolve current employee", zap.Error(err))
+		return 0, false, c.JSON(http.StatusUnauthorized, map[string]string{"error": "Employee not identified"})
+	}
+
+	return employeeID, true, nil
+}
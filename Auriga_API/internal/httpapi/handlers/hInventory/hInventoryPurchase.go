@@ -0,0 +1,169 @@
+package hInventory
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sInventory"
+	"go.uber.org/zap"
+)
+
+func (h *handler) GetReorderProposals(c echo.Context) error {
+	proposals, err := h.service.GetReorderProposals()
+	if err != nil {
+		return h.serviceError(c, err, "Reorder proposals not found", "Failed to compute reorder proposals")
+	}
+	return c.JSON(http.StatusOK, proposals)
+}
+
+func (h *handler) GenerateReorderPurchaseOrders(c echo.Context) error {
+	employeeID, err := h.currentEmployeeID(c)
+	if err != nil {
+		h.logger.Warn("Cannot resolve current employee", zap.Error(err))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Employee not identified"})
+	}
+
+	orders, err := h.service.GenerateReorderPurchaseOrders(employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Reorder proposals not found", "Failed to generate reorder purchase orders")
+	}
+
+	return c.JSON(http.StatusCreated, orders)
+}
+
+func (h *handler) GetPurchaseOrders(c echo.Context) error {
+	var req sInventory.PurchaseOrderFilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	orders, err := h.service.GetPurchaseOrders(req)
+	if err != nil {
+		return h.serviceError(c, err, "Purchase orders not found", "Failed to get purchase orders")
+	}
+
+	return c.JSON(http.StatusOK, orders)
+}
+
+func (h *handler) GetPurchaseOrder(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid purchase order ID"})
+	}
+
+	order, err := h.service.GetPurchaseOrderByID(id)
+	if err != nil {
+		return h.serviceError(c, err, "Purchase order not found", "Failed to get purchase order")
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+func (h *handler) ApprovePurchaseOrder(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid purchase order ID"})
+	}
+
+	employeeID, err := h.currentEmployeeID(c)
+	if err != nil {
+		h.logger.Warn("Cannot resolve current employee", zap.Error(err))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Employee not identified"})
+	}
+
+	order, err := h.service.ApprovePurchaseOrder(id, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Purchase order not found", "Failed to approve purchase order")
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+func (h *handler) CreatePurchaseOrder(c echo.Context) error {
+	var req sInventory.CreatePurchaseOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	employeeID, err := h.currentEmployeeID(c)
+	if err != nil {
+		h.logger.Warn("Cannot resolve current employee", zap.Error(err))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Employee not identified"})
+	}
+
+	order, err := h.service.CreatePurchaseOrder(req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Purchase order not found", "Failed to create purchase order")
+	}
+
+	return c.JSON(http.StatusCreated, order)
+}
+
+func (h *handler) SendPurchaseOrder(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid purchase order ID"})
+	}
+
+	var req sInventory.SendPurchaseOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	order, err := h.service.SendPurchaseOrder(id, req)
+	if err != nil {
+		return h.serviceError(c, err, "Purchase order not found", "Failed to send purchase order")
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+func (h *handler) ReceivePurchaseOrder(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid purchase order ID"})
+	}
+
+	var req sInventory.ReceivePurchaseOrderRequest
+	employeeID, ok, err := h.bindMovement(c, &req)
+	if !ok {
+		return err
+	}
+
+	order, err := h.service.ReceivePurchaseOrder(id, req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Purchase order not found", "Failed to receive purchase order")
+	}
+
+	return c.JSON(http.StatusCreated, order)
+}
+
+func (h *handler) CancelPurchaseOrder(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid purchase order ID"})
+	}
+
+	var req sInventory.CancelPurchaseOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	order, err := h.service.CancelPurchaseOrder(id, req)
+	if err != nil {
+		return h.serviceError(c, err, "Purchase order not found", "Failed to cancel purchase order")
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
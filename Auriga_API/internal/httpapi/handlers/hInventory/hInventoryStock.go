@@ -0,0 +1,56 @@
+package hInventory
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sInventory"
+)
+
+func (h *handler) GetStocks(c echo.Context) error {
+	var req sInventory.StockFilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	stocks, err := h.service.GetStocks(req)
+	if err != nil {
+		return h.serviceError(c, err, "Stock not found", "Failed to get stocks")
+	}
+
+	return c.JSON(http.StatusOK, stocks)
+}
+
+func (h *handler) GetStock(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid stock ID"})
+	}
+
+	stock, err := h.service.GetStockByID(id)
+	if err != nil {
+		return h.serviceError(c, err, "Stock not found", "Failed to get stock")
+	}
+
+	return c.JSON(http.StatusOK, stock)
+}
+
+func (h *handler) GetExpiringStocks(c echo.Context) error {
+	var req sInventory.ExpiringStockRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	reports, err := h.service.GetExpiringStocks(req)
+	if err != nil {
+		return h.serviceError(c, err, "Stock not found", "Failed to get expiring stock")
+	}
+
+	return c.JSON(http.StatusOK, reports)
+}
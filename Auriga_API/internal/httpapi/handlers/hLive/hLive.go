@@ -0,0 +1,52 @@
+package hLive
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/config"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/middlewares"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service        sLive.Service
+	authMiddleware *middlewares.AuthMiddleware
+	config         *config.Settings
+	logger         *zap.Logger
+}
+
+type Result struct {
+	fx.Out
+
+	Handler handlers.Handler `group:"handlers"`
+}
+
+type Params struct {
+	fx.In
+
+	Service        sLive.Service
+	AuthMiddleware *middlewares.AuthMiddleware
+	Config         *config.Settings
+	Logger         *zap.Logger
+}
+
+func New(p Params) Result {
+	return Result{
+		Handler: &handler{
+			service:        p.Service,
+			authMiddleware: p.AuthMiddleware,
+			config:         p.Config,
+			logger:         p.Logger,
+		},
+	}
+}
+
+func (h *handler) RegisterRoutes(e *echo.Echo, s *config.Settings) {
+	r := e.Group("/live")
+	/*middlewares*/
+	r.Use(h.authMiddleware.CombinedMiddleware())
+
+	r.GET("/stream", h.Stream)
+}
@@ -0,0 +1,116 @@
+package hLive
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/middlewares"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"go.uber.org/zap"
+)
+
+const defaultHeartbeat = 25 * time.Second
+
+type responseMessage struct {
+	Message string `json:"message"`
+}
+
+type Handler interface {
+	Stream(c echo.Context) error
+}
+
+// Stream abre un flujo Server-Sent Events con los cambios de la fábrica. Los parámetros van en la query
+// porque EventSource no admite cabeceras; la autenticación usa la cookie de sesión o el Bearer token.
+//
+//	factory: fábrica (obligatoria)
+//	lines:   líneas separadas por comas; vacío recibe los eventos de todas, pero el estado y el
+//	         throughput solo se difunden para las líneas indicadas
+//	types:   raw_event, event_commit, line_status, throughput separados por comas; vacío recibe todos
+func (h *handler) Stream(c echo.Context) error {
+	factory := c.QueryParam("factory")
+	if factory == "" {
+		return c.JSON(http.StatusBadRequest, responseMessage{Message: "factory parameter is required"})
+	}
+	if !middlewares.HasFactoryAccess(c, factory) {
+		return c.JSON(http.StatusForbidden, responseMessage{Message: "Insufficient permissions. Required factory access: " + factory})
+	}
+
+	types := splitParam(c.QueryParam("types"))
+	for _, t := range types {
+		switch t {
+		case sLive.TypeRawEvent, sLive.TypeEventCommit, sLive.TypeLineStatus, sLive.TypeThroughput:
+		default:
+			return c.JSON(http.StatusBadRequest, responseMessage{Message: "unknown message type: " + t})
+		}
+	}
+	lines := splitParam(c.QueryParam("lines"))
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // Sin buffer en nginx
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	sub := h.service.Subscribe(factory, lines, types)
+	defer h.service.Unsubscribe(sub)
+
+	heartbeat := h.config.Live.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case msg, ok := <-sub.Messages:
+			if !ok {
+				return nil
+			}
+			if err := writeMessage(res, msg); err != nil {
+				h.logger.Debug("Live stream closed", zap.String("factory", factory), zap.Error(err))
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+// writeMessage escribe el mensaje en formato SSE; el estado inicial de las líneas no lleva id porque no
+// forma parte de la secuencia
+func writeMessage(res *echo.Response, msg sLive.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if msg.ID > 0 {
+		if _, err := fmt.Fprintf(res, "id: %d\n", msg.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", msg.Type, data)
+	return err
+}
+
+func splitParam(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,68 @@
+package hMaintenance
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+)
+
+func (h *handler) GetCostReport(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid asset ID"})
+	}
+
+	var req sMaintenance.CostReportRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	report, err := h.service.GetCostReport(id, req)
+	if err != nil {
+		return h.serviceError(c, err, "Asset not found", "Failed to compute maintenance cost report")
+	}
+
+	if req.Format == "csv" {
+		return writeCostReportCSV(c, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// writeCostReportCSV envía el informe como fichero CSV descargable
+func writeCostReportCSV(c echo.Context, report *sMaintenance.CostReport) error {
+	filename := fmt.Sprintf("maintenance_costs_%d_%s_%s.csv", report.AssetID, report.From.Format("20060102"), report.To.Format("20060102"))
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(c.Response())
+	w.Write([]string{"asset_id", "code", "path", "month", "work_order_type", "priority", "work_orders", "labor_hours", "labor_cost", "parts_cost", "total_cost"})
+	for _, row := range report.Rows {
+		w.Write([]string{
+			strconv.FormatUint(uint64(row.AssetID), 10),
+			row.Code,
+			strings.Join(row.Path, "/"),
+			row.Month,
+			row.WorkOrderType,
+			row.Priority,
+			strconv.Itoa(row.WorkOrders),
+			strconv.FormatFloat(row.LaborHours, 'f', 2, 64),
+			strconv.FormatFloat(row.LaborCost, 'f', 2, 64),
+			strconv.FormatFloat(row.PartsCost, 'f', 2, 64),
+			strconv.FormatFloat(row.TotalCost, 'f', 2, 64),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
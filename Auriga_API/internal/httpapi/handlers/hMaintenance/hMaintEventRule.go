@@ -0,0 +1,92 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+)
+
+func (h *handler) GetEventRules(c echo.Context) error {
+	rules, err := h.service.GetEventRules()
+	if err != nil {
+		return h.serviceError(c, err, "Event rules not found", "Failed to get event rules")
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+func (h *handler) CreateEventRule(c echo.Context) error {
+	var req sMaintenance.EventRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	rule, err := h.service.CreateEventRule(req)
+	if err != nil {
+		return h.serviceError(c, err, "Event rule not found", "Failed to create event rule")
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+func (h *handler) UpdateEventRule(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid event rule ID"})
+	}
+
+	var req sMaintenance.EventRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	rule, err := h.service.UpdateEventRule(id, req)
+	if err != nil {
+		return h.serviceError(c, err, "Event rule not found", "Failed to update event rule")
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+func (h *handler) DeleteEventRule(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid event rule ID"})
+	}
+
+	if err := h.service.DeleteEventRule(id); err != nil {
+		return h.serviceError(c, err, "Event rule not found", "Failed to delete event rule")
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Event rule deleted successfully"})
+}
+
+// ProcessStopEvent aplica de nuevo las reglas a un evento ya confirmado, p. ej. creado antes que la regla
+func (h *handler) ProcessStopEvent(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid event ID"})
+	}
+
+	workOrder, err := h.service.ProcessStopEvent(id)
+	if err != nil {
+		return h.serviceError(c, err, "Event not found", "Failed to process stop event")
+	}
+	if workOrder == nil {
+		return c.JSON(http.StatusOK, map[string]string{"message": "No event rule applies or the event is already linked"})
+	}
+
+	return c.JSON(http.StatusOK, workOrder)
+}
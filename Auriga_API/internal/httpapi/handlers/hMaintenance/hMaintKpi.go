@@ -0,0 +1,57 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+)
+
+func (h *handler) GetAssetKpis(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid asset ID"})
+	}
+
+	var req sMaintenance.AssetKpiRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	report, err := h.service.GetAssetKpis(id, req)
+	if err != nil {
+		return h.serviceError(c, err, "Asset not found", "Failed to compute maintenance KPIs")
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+func (h *handler) GetAssetKpiRanking(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid asset ID"})
+	}
+
+	var req sMaintenance.AssetKpiRankingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ranking, err := h.service.GetAssetKpiRanking(id, req)
+	if err != nil {
+		return h.serviceError(c, err, "Asset not found", "Failed to rank assets by maintenance KPIs")
+	}
+
+	return c.JSON(http.StatusOK, ranking)
+}
@@ -0,0 +1,74 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+)
+
+func (h *handler) GetLaborRates(c echo.Context) error {
+	rates, err := h.service.GetLaborRates()
+	if err != nil {
+		return h.serviceError(c, err, "Labor rates not found", "Failed to get labor rates")
+	}
+
+	return c.JSON(http.StatusOK, rates)
+}
+
+func (h *handler) CreateLaborRate(c echo.Context) error {
+	var req sMaintenance.LaborRateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	rate, err := h.service.CreateLaborRate(req)
+	if err != nil {
+		return h.serviceError(c, err, "Labor rate not found", "Failed to create labor rate")
+	}
+
+	return c.JSON(http.StatusCreated, rate)
+}
+
+func (h *handler) UpdateLaborRate(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid labor rate ID"})
+	}
+
+	var req sMaintenance.LaborRateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	rate, err := h.service.UpdateLaborRate(id, req)
+	if err != nil {
+		return h.serviceError(c, err, "Labor rate not found", "Failed to update labor rate")
+	}
+
+	return c.JSON(http.StatusOK, rate)
+}
+
+func (h *handler) DeleteLaborRate(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid labor rate ID"})
+	}
+
+	if err := h.service.DeleteLaborRate(id); err != nil {
+		return h.serviceError(c, err, "Labor rate not found", "Failed to delete labor rate")
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Labor rate deleted successfully"})
+}
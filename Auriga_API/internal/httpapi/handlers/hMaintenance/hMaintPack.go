@@ -0,0 +1,220 @@
+package hMaintenance
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+	"github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+)
+
+func (h *handler) GetWorkOrderPack(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	var req sMaintenance.WorkOrderPackRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	pack, err := h.service.GetWorkOrderPack(id)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to get work order pack")
+	}
+
+	// Matriz del QR con su zona de silencio de 4 módulos
+	var qr [][]bool
+	if code, err := qrcode.New(pack.URL, qrcode.Medium); err != nil {
+		// El dossier sigue siendo útil sin el QR
+		h.logger.Warn("Cannot encode work order QR code", zap.String("url", pack.URL), zap.Error(err))
+	} else {
+		qr = code.Bitmap()
+	}
+
+	if req.Format == "html" {
+		return writeWorkOrderPackHTML(c, pack, qr)
+	}
+
+	document, err := renderWorkOrderPackPDF(pack, qr)
+	if err != nil {
+		h.logger.Error("Cannot render work order pack PDF", zap.Uint("work_order_id", pack.WorkOrderID), zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to render work order pack"})
+	}
+
+	filename := fmt.Sprintf("work_order_%d.pdf", pack.WorkOrderID)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", filename))
+	return c.Blob(http.StatusOK, "application/pdf", document)
+}
+
+// writeWorkOrderPackHTML envía la variante HTML del dossier, preparada para imprimir desde el navegador
+func writeWorkOrderPackHTML(c echo.Context, pack *sMaintenance.WorkOrderPack, qr [][]bool) error {
+	data := struct {
+		*sMaintenance.WorkOrderPack
+		QR template.HTML
+	}{WorkOrderPack: pack}
+	if len(qr) > 0 {
+		// El SVG se genera a partir de la matriz de módulos, sin texto del usuario
+		data.QR = template.HTML(qrSVG(qr, 4))
+	}
+
+	var buf bytes.Buffer
+	if err := workOrderPackTemplate.Execute(&buf, data); err != nil {
+		return err
+	}
+	return c.HTMLBlob(http.StatusOK, buf.Bytes())
+}
+
+// qrSVG dibuja la matriz del QR como un único path, con moduleSize píxeles por módulo
+func qrSVG(qr [][]bool, moduleSize int) string {
+	size := len(qr)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size*moduleSize, size*moduleSize, size, size)
+	b.WriteString(`<rect width="100%" height="100%" fill="#fff"/><path fill="#000" d="`)
+	for y, modules := range qr {
+		for x, dark := range modules {
+			if dark {
+				fmt.Fprintf(&b, "M%d,%dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.String()
+}
+
+func formatPackDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func formatPackQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', -1, 64)
+}
+
+var workOrderPackTemplate = template.Must(template.New("pack").Funcs(template.FuncMap{
+	"date": formatPackDate,
+	"day": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	},
+	"qty": formatPackQuantity,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Work order #{{.WorkOrderID}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 13px; margin: 24px; color: #000; }
+header { display: flex; justify-content: space-between; align-items: flex-start; }
+h1 { font-size: 20px; margin: 0 0 4px; }
+h2 { font-size: 15px; border-bottom: 1px solid #000; margin: 20px 0 8px; padding-bottom: 2px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #999; padding: 4px 6px; text-align: left; vertical-align: top; }
+.meta td:first-child { width: 160px; font-weight: bold; }
+.box { display: inline-block; width: 12px; height: 12px; border: 1px solid #000; text-align: center; line-height: 12px; }
+.safety { border: 2px solid #000; padding: 8px; }
+.small { font-size: 11px; color: #333; }
+.signature td { height: 36px; }
+@media print { body { margin: 0; } a { color: #000; text-decoration: none; } tr { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<header>
+<div>
+<h1>Work order #{{.WorkOrderID}}</h1>
+<div><strong>{{.Title}}</strong></div>
+<div class="small">{{.WorkOrderType}} · {{.Priority}} · {{.Status}}</div>
+</div>
+{{if .QR}}<div><a href="{{.URL}}">{{.QR}}</a></div>{{end}}
+</header>
+
+<h2>Order</h2>
+<table class="meta">
+<tr><td>Scheduled date</td><td>{{date .ScheduledDate}}</td></tr>
+<tr><td>Estimated hours</td><td>{{qty .EstimatedHours}}</td></tr>
+{{if .PlanName}}<tr><td>Maintenance plan</td><td>{{.PlanName}}</td></tr>{{end}}
+{{if .Team}}<tr><td>Team</td><td>{{.Team}}</td></tr>{{end}}
+{{if .Technicians}}<tr><td>Technicians</td><td>{{range $i, $t := .Technicians}}{{if $i}}, {{end}}{{$t}}{{end}}</td></tr>{{end}}
+{{if .Description}}<tr><td>Description</td><td>{{.Description}}</td></tr>{{end}}
+</table>
+
+<h2>Asset</h2>
+<table class="meta">
+<tr><td>Code</td><td>{{.Asset.Code}}</td></tr>
+<tr><td>Tech code</td><td>{{.Asset.TechCode}}</td></tr>
+<tr><td>Serial number</td><td>{{.Asset.Sn}}</td></tr>
+<tr><td>Location</td><td>{{.Asset.Location}}</td></tr>
+<tr><td>Hierarchy</td><td>{{.Asset.Path}}</td></tr>
+</table>
+
+{{if .SafetyNotes}}
+<h2>Safety notes</h2>
+<div class="safety">{{range .SafetyNotes}}<div>{{.}}</div>{{end}}</div>
+{{end}}
+
+<h2>Checklist</h2>
+{{if .Steps}}
+<table>
+<tr><th></th><th>Step</th><th>Instructions</th><th>Min</th></tr>
+{{range .Steps}}
+<tr>
+<td><span class="box">{{if .Done}}&#10003;{{end}}</span></td>
+<td>{{.Number}}</td>
+<td><strong>{{.Title}}</strong>{{if .Description}}<div>{{.Description}}</div>{{end}}{{if .SafetyNotes}}<div class="small">Safety: {{.SafetyNotes}}</div>{{end}}</td>
+<td>{{if .ExpectedDuration}}{{.ExpectedDuration}}{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}<p>No steps defined.</p>{{end}}
+
+<h2>Spare parts</h2>
+{{if .SpareParts}}
+<table>
+<tr><th></th><th>Product</th><th>Quantity</th><th>Pick from</th></tr>
+{{range .SpareParts}}
+<tr>
+<td><span class="box">{{if .Issued}}&#10003;{{end}}</span></td>
+<td>{{.Product}}{{if .Issued}} <span class="small">(issued)</span>{{end}}</td>
+<td>{{qty .Quantity}} {{.Unit}}</td>
+<td>{{range .Locations}}<div>{{.Warehouse}}{{if .Location}} / {{.Location}}{{end}}{{if .BatchNumber}} · batch {{.BatchNumber}}{{end}}{{with day .ExpiryDate}} · exp. {{.}}{{end}} · {{qty .Quantity}}</div>{{else}}<span class="small">No stock available</span>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}<p>No spare parts required.</p>{{end}}
+
+{{if .Documents}}
+<h2>Documents</h2>
+<ul>
+{{range .Documents}}<li>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{if .Description}} <span class="small">{{.Description}}</span>{{end}}</li>
+{{end}}
+</ul>
+{{end}}
+
+<h2>Sign-off</h2>
+<table class="signature">
+<tr><th>Completed by</th><th>Date</th><th>Signature</th></tr>
+<tr><td></td><td></td><td></td></tr>
+</table>
+<p class="small">Generated {{date .GeneratedAt}}{{if .URL}} · {{.URL}}{{end}}</p>
+</body>
+</html>
+`))
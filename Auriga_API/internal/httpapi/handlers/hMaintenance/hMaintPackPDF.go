@@ -0,0 +1,245 @@
+package hMaintenance
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+)
+
+const (
+	packMargin = 40.0
+	packQRSize = 90.0
+	packFont   = "Helvetica"
+)
+
+// packWriter maqueta el dossier de arriba abajo y abre páginas nuevas cuando no cabe el siguiente bloque
+type packWriter struct {
+	doc         *fpdf.Fpdf
+	translate   func(string) string // Las fuentes estándar de PDF sólo cubren cp1252
+	pageWidth   float64
+	pageHeight  float64
+	y           float64
+	workOrderID uint
+}
+
+// renderWorkOrderPackPDF maqueta el dossier en A4 con unidades en puntos; qr es la matriz del código con su zona de silencio
+func renderWorkOrderPackPDF(pack *sMaintenance.WorkOrderPack, qr [][]bool) ([]byte, error) {
+	doc := fpdf.New("P", "pt", "A4", "")
+	doc.SetAutoPageBreak(false, 0)
+	doc.SetCellMargin(0)
+	doc.SetTitle(fmt.Sprintf("Work order #%d", pack.WorkOrderID), true)
+
+	w := &packWriter{doc: doc, translate: doc.UnicodeTranslatorFromDescriptor(""), workOrderID: pack.WorkOrderID}
+	w.pageWidth, w.pageHeight = doc.GetPageSize()
+	w.newPage()
+
+	// Cabecera: el título deja sitio al QR en la esquina superior derecha
+	titleWidth := w.pageWidth - 2*packMargin
+	if len(qr) > 0 {
+		titleWidth -= packQRSize + 10
+		w.drawQR(qr, w.pageWidth-packMargin-packQRSize, packMargin, pack.URL)
+	}
+	w.doc.SetFont(packFont, "B", 18)
+	w.doc.Text(packMargin, w.y+16, fmt.Sprintf("Work order #%d", pack.WorkOrderID))
+	w.y += 26
+	w.text(pack.Title, packMargin, titleWidth, true, 12)
+	w.text(fmt.Sprintf("%s · %s · %s", pack.WorkOrderType, pack.Priority, pack.Status), packMargin, titleWidth, false, 10)
+	if len(qr) > 0 && w.y < packMargin+packQRSize {
+		w.y = packMargin + packQRSize
+	}
+
+	w.heading("Order")
+	w.field("Scheduled date", formatPackDate(pack.ScheduledDate))
+	w.field("Estimated hours", formatPackQuantity(pack.EstimatedHours))
+	w.field("Maintenance plan", pack.PlanName)
+	w.field("Team", pack.Team)
+	w.field("Technicians", strings.Join(pack.Technicians, ", "))
+	w.field("Description", pack.Description)
+
+	w.heading("Asset")
+	w.field("Code", pack.Asset.Code)
+	w.field("Tech code", fmt.Sprint(pack.Asset.TechCode))
+	w.field("Serial number", pack.Asset.Sn)
+	w.field("Location", pack.Asset.Location)
+	w.field("Hierarchy", pack.Asset.Path)
+
+	if len(pack.SafetyNotes) > 0 {
+		w.heading("Safety notes")
+		for _, note := range pack.SafetyNotes {
+			w.text(note, packMargin+10, w.pageWidth-2*packMargin-10, false, 10)
+		}
+	}
+
+	w.heading("Checklist")
+	if len(pack.Steps) == 0 {
+		w.text("No steps defined.", packMargin, w.pageWidth-2*packMargin, false, 10)
+	}
+	for _, step := range pack.Steps {
+		title := fmt.Sprintf("%d. %s", step.Number, step.Title)
+		if step.ExpectedDuration > 0 {
+			title += fmt.Sprintf(" (%d min)", step.ExpectedDuration)
+		}
+		w.checkItem(step.Done, title, step.Description, step.SafetyNotes)
+	}
+
+	w.heading("Spare parts")
+	if len(pack.SpareParts) == 0 {
+		w.text("No spare parts required.", packMargin, w.pageWidth-2*packMargin, false, 10)
+	}
+	for _, sparePart := range pack.SpareParts {
+		title := fmt.Sprintf("%s - %s %s", sparePart.Product, formatPackQuantity(sparePart.Quantity), sparePart.Unit)
+		if sparePart.Issued {
+			title += " (issued)"
+		}
+		var locations []string
+		for _, location := range sparePart.Locations {
+			locations = append(locations, formatPackLocation(location))
+		}
+		if len(locations) == 0 {
+			locations = append(locations, "No stock available")
+		}
+		w.checkItem(sparePart.Issued, strings.TrimSpace(title), strings.Join(locations, "\n"), "")
+	}
+
+	if len(pack.Documents) > 0 {
+		w.heading("Documents")
+		for _, document := range pack.Documents {
+			w.ensure(14)
+			start := w.y
+			w.text(document.Name, packMargin, w.pageWidth-2*packMargin, true, 10)
+			if document.URL != "" {
+				w.doc.LinkString(packMargin, start, w.pageWidth-2*packMargin, w.y-start, document.URL)
+				w.text(document.URL, packMargin+10, w.pageWidth-2*packMargin-10, false, 8)
+			}
+		}
+	}
+
+	w.heading("Sign-off")
+	w.ensure(40)
+	columns := []string{"Completed by", "Date", "Signature"}
+	columnWidth := (w.pageWidth - 2*packMargin) / float64(len(columns))
+	w.doc.SetFont(packFont, "", 9)
+	for i, label := range columns {
+		x := packMargin + float64(i)*columnWidth
+		w.doc.Rect(x, w.y, columnWidth, 40, "D")
+		w.doc.Text(x+4, w.y+11, label)
+	}
+	w.y += 50
+
+	var buf bytes.Buffer
+	if err := w.doc.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *packWriter) newPage() {
+	w.doc.AddPage()
+	w.doc.SetFont(packFont, "", 8)
+	w.doc.Text(packMargin, w.pageHeight-packMargin/2, fmt.Sprintf("Work order #%d - page %d", w.workOrderID, w.doc.PageNo()))
+	w.y = packMargin
+}
+
+// ensure abre una página nueva si el bloque de la altura indicada no cabe en la actual
+func (w *packWriter) ensure(height float64) {
+	if w.y+height > w.pageHeight-packMargin {
+		w.newPage()
+	}
+}
+
+func (w *packWriter) heading(title string) {
+	w.ensure(40)
+	w.y += 14
+	w.doc.SetFont(packFont, "B", 12)
+	w.doc.Text(packMargin, w.y+10, title)
+	w.y += 14
+	w.doc.Line(packMargin, w.y, w.pageWidth-packMargin, w.y)
+	w.y += 6
+}
+
+// text escribe un párrafo ajustado al ancho indicado
+func (w *packWriter) text(text string, x, width float64, bold bool, size float64) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	style := ""
+	if bold {
+		style = "B"
+	}
+	lineHeight := size * 1.3
+	w.doc.SetFont(packFont, style, size)
+	for _, line := range w.doc.SplitLines([]byte(w.translate(text)), width) {
+		w.ensure(lineHeight)
+		w.doc.SetFont(packFont, style, size)
+		w.doc.Text(x, w.y+size, string(line))
+		w.y += lineHeight
+	}
+}
+
+// field escribe una fila etiqueta-valor; los valores vacíos se omiten
+func (w *packWriter) field(label, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	const labelWidth = 110.0
+	w.ensure(13)
+	w.doc.SetFont(packFont, "B", 10)
+	w.doc.Text(packMargin, w.y+10, label)
+	w.text(value, packMargin+labelWidth, w.pageWidth-2*packMargin-labelWidth, false, 10)
+}
+
+// checkItem dibuja una casilla con su título, el detalle y la nota de seguridad
+func (w *packWriter) checkItem(done bool, title, detail, safety string) {
+	const indent = 18.0
+	width := w.pageWidth - 2*packMargin - indent
+
+	w.ensure(30)
+	w.y += 4
+	w.doc.Rect(packMargin, w.y+1, 10, 10, "D")
+	if done {
+		w.doc.Line(packMargin+2, w.y+6, packMargin+4.5, w.y+9)
+		w.doc.Line(packMargin+4.5, w.y+9, packMargin+8.5, w.y+2.5)
+	}
+	w.text(title, packMargin+indent, width, true, 10)
+	w.text(detail, packMargin+indent, width, false, 9)
+	if strings.TrimSpace(safety) != "" {
+		w.text("Safety: "+safety, packMargin+indent, width, true, 9)
+	}
+}
+
+// drawQR dibuja el código uniendo los módulos oscuros consecutivos de cada fila
+func (w *packWriter) drawQR(qr [][]bool, x, y float64, url string) {
+	module := packQRSize / float64(len(qr))
+	for row, modules := range qr {
+		for col := 0; col < len(modules); {
+			if !modules[col] {
+				col++
+				continue
+			}
+			start := col
+			for col < len(modules) && modules[col] {
+				col++
+			}
+			w.doc.Rect(x+float64(start)*module, y+float64(row)*module, float64(col-start)*module, module, "F")
+		}
+	}
+	w.doc.LinkString(x, y, packQRSize, packQRSize, url)
+}
+
+func formatPackLocation(location sMaintenance.PackStockLocation) string {
+	parts := []string{location.Warehouse}
+	if location.Location != "" {
+		parts[0] += " / " + location.Location
+	}
+	if location.BatchNumber != "" {
+		parts = append(parts, "batch "+location.BatchNumber)
+	}
+	if location.ExpiryDate != nil {
+		parts = append(parts, "exp. "+location.ExpiryDate.Format("2006-01-02"))
+	}
+	parts = append(parts, formatPackQuantity(location.Quantity))
+	return strings.Join(parts, " · ")
+}
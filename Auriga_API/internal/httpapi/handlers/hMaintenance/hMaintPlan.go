@@ -0,0 +1,31 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+)
+
+func (h *handler) GetPlansUsage(c echo.Context) error {
+	usage, err := h.service.GetPlansUsage()
+	if err != nil {
+		return h.serviceError(c, err, "Maintenance plans not found", "Failed to get maintenance plans usage")
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
+
+func (h *handler) GetPlanUsage(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid maintenance plan ID"})
+	}
+
+	usage, err := h.service.GetPlanUsage(id)
+	if err != nil {
+		return h.serviceError(c, err, "Maintenance plan not found", "Failed to get maintenance plan usage")
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
@@ -0,0 +1,116 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/middlewares"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+	"go.uber.org/zap"
+)
+
+func (h *handler) GetWorkOrderTime(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	segments, err := h.service.GetWorkOrderTime(workOrderID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to get work order time")
+	}
+
+	return c.JSON(http.StatusOK, segments)
+}
+
+func (h *handler) StartWork(c echo.Context) error {
+	workOrderID, employeeID, ok, err := h.bindTimeTracking(c)
+	if !ok {
+		return err
+	}
+
+	segment, err := h.service.StartWork(workOrderID, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to start work")
+	}
+
+	return c.JSON(http.StatusCreated, segment)
+}
+
+func (h *handler) PauseWork(c echo.Context) error {
+	workOrderID, employeeID, ok, err := h.bindTimeTracking(c)
+	if !ok {
+		return err
+	}
+
+	segment, err := h.service.PauseWork(workOrderID, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to pause work")
+	}
+
+	return c.JSON(http.StatusOK, segment)
+}
+
+func (h *handler) ResumeWork(c echo.Context) error {
+	workOrderID, employeeID, ok, err := h.bindTimeTracking(c)
+	if !ok {
+		return err
+	}
+
+	segment, err := h.service.ResumeWork(workOrderID, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to resume work")
+	}
+
+	return c.JSON(http.StatusCreated, segment)
+}
+
+func (h *handler) StopWork(c echo.Context) error {
+	workOrderID, employeeID, ok, err := h.bindTimeTracking(c)
+	if !ok {
+		return err
+	}
+
+	assignment, err := h.service.StopWork(workOrderID, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to stop work")
+	}
+
+	return c.JSON(http.StatusOK, assignment)
+}
+
+// bindTimeTracking resuelve la orden y el técnico que ficha; si el cuerpo no indica employee_id
+// se usa el empleado autenticado. Fichar por otro técnico requiere uno de los roles de supervisor.
+// Si ok es false la respuesta de error ya se ha escrito y se devuelve en err.
+func (h *handler) bindTimeTracking(c echo.Context) (uint, uint, bool, error) {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return 0, 0, false, c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	var req sMaintenance.TimeTrackingRequest
+	if err := c.Bind(&req); err != nil {
+		return 0, 0, false, c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return 0, 0, false, c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	employeeID, err := h.currentEmployeeID(c)
+	if err != nil {
+		h.logger.Warn("Cannot resolve current employee", zap.Error(err))
+		return 0, 0, false, c.JSON(http.StatusUnauthorized, map[string]string{"error": "Employee not identified"})
+	}
+
+	if req.EmployeeID != nil && *req.EmployeeID != employeeID {
+		if !middlewares.HasAnyRole(c, h.config.Maintenance.SupervisorRoles) {
+			return 0, 0, false, c.JSON(http.StatusForbidden, map[string]string{"error": "Only a supervisor can clock another employee"})
+		}
+		return workOrderID, *req.EmployeeID, true, nil
+	}
+
+	return workOrderID, employeeID, true, nil
+}
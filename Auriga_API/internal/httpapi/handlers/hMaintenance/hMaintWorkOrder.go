@@ -0,0 +1,87 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+	"go.uber.org/zap"
+)
+
+func (h *handler) GetWorkOrders(c echo.Context) error {
+	var req sMaintenance.WorkOrderFilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid query parameters"})
+	}
+
+	workOrders, err := h.service.GetWorkOrders(req)
+	if err != nil {
+		return h.serviceError(c, err, "Work orders not found", "Failed to get work orders")
+	}
+
+	return c.JSON(http.StatusOK, workOrders)
+}
+
+func (h *handler) GetWorkOrder(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	workOrder, err := h.service.GetWorkOrderByID(id)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to get work order")
+	}
+
+	return c.JSON(http.StatusOK, workOrder)
+}
+
+func (h *handler) CreateWorkOrder(c echo.Context) error {
+	var req sMaintenance.CreateWorkOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	employeeID, err := h.currentEmployeeID(c)
+	if err != nil {
+		h.logger.Warn("Cannot resolve current employee", zap.Error(err))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Employee not identified"})
+	}
+
+	workOrder, err := h.service.CreateWorkOrder(req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to create work order")
+	}
+
+	return c.JSON(http.StatusCreated, workOrder)
+}
+
+func (h *handler) UpdateWorkOrder(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	var req sMaintenance.UpdateWorkOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	workOrder, err := h.service.UpdateWorkOrder(id, req)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to update work order")
+	}
+
+	return c.JSON(http.StatusOK, workOrder)
+}
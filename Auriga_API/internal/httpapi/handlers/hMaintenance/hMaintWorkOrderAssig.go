@@ -0,0 +1,94 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+)
+
+func (h *handler) GetWorkOrderAssignments(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	assignments, err := h.service.GetWorkOrderAssignments(workOrderID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to get work order assignments")
+	}
+
+	return c.JSON(http.StatusOK, assignments)
+}
+
+func (h *handler) CreateWorkOrderAssignment(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	var req sMaintenance.CreateWorkOrderAssignmentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	assignment, err := h.service.CreateWorkOrderAssignment(workOrderID, req)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to create work order assignment")
+	}
+
+	return c.JSON(http.StatusCreated, assignment)
+}
+
+func (h *handler) UpdateWorkOrderAssignment(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	assignmentID, err := handlers.ParseID(c, "assignmentId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid assignment ID"})
+	}
+
+	var req sMaintenance.UpdateWorkOrderAssignmentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	assignment, err := h.service.UpdateWorkOrderAssignment(workOrderID, assignmentID, req)
+	if err != nil {
+		return h.serviceError(c, err, "Assignment not found", "Failed to update work order assignment")
+	}
+
+	return c.JSON(http.StatusOK, assignment)
+}
+
+func (h *handler) DeleteWorkOrderAssignment(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	assignmentID, err := handlers.ParseID(c, "assignmentId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid assignment ID"})
+	}
+
+	if err := h.service.DeleteWorkOrderAssignment(workOrderID, assignmentID); err != nil {
+		return h.serviceError(c, err, "Assignment not found", "Failed to delete work order assignment")
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Assignment deleted successfully"})
+}
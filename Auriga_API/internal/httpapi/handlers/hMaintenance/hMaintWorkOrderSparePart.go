@@ -0,0 +1,94 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+)
+
+func (h *handler) GetWorkOrderSpareParts(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	spareParts, err := h.service.GetWorkOrderSpareParts(workOrderID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to get work order spare parts")
+	}
+
+	return c.JSON(http.StatusOK, spareParts)
+}
+
+func (h *handler) CreateWorkOrderSparePart(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	var req sMaintenance.CreateWorkOrderSparePartRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	sparePart, err := h.service.CreateWorkOrderSparePart(workOrderID, req)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to create work order spare part")
+	}
+
+	return c.JSON(http.StatusCreated, sparePart)
+}
+
+func (h *handler) UpdateWorkOrderSparePart(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	sparePartID, err := handlers.ParseID(c, "sparePartId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid spare part ID"})
+	}
+
+	var req sMaintenance.UpdateWorkOrderSparePartRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	sparePart, err := h.service.UpdateWorkOrderSparePart(workOrderID, sparePartID, req)
+	if err != nil {
+		return h.serviceError(c, err, "Spare part not found", "Failed to update work order spare part")
+	}
+
+	return c.JSON(http.StatusOK, sparePart)
+}
+
+func (h *handler) DeleteWorkOrderSparePart(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	sparePartID, err := handlers.ParseID(c, "sparePartId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid spare part ID"})
+	}
+
+	if err := h.service.DeleteWorkOrderSparePart(workOrderID, sparePartID); err != nil {
+		return h.serviceError(c, err, "Spare part not found", "Failed to delete work order spare part")
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Spare part deleted successfully"})
+}
@@ -0,0 +1,54 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+	"go.uber.org/zap"
+)
+
+func (h *handler) ChangeWorkOrderStatus(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	var req sMaintenance.ChangeWorkOrderStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	employeeID, err := h.currentEmployeeID(c)
+	if err != nil {
+		h.logger.Warn("Cannot resolve current employee", zap.Error(err))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Employee not identified"})
+	}
+
+	workOrder, err := h.service.ChangeWorkOrderStatus(id, req, employeeID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to change work order status")
+	}
+
+	return c.JSON(http.StatusOK, workOrder)
+}
+
+func (h *handler) GetWorkOrderHistory(c echo.Context) error {
+	id, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	history, err := h.service.GetWorkOrderHistory(id)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to get work order history")
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,94 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+)
+
+func (h *handler) GetWorkOrderTasks(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	tasks, err := h.service.GetWorkOrderTasks(workOrderID)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to get work order tasks")
+	}
+
+	return c.JSON(http.StatusOK, tasks)
+}
+
+func (h *handler) CreateWorkOrderTask(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	var req sMaintenance.CreateWorkOrderTaskRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	task, err := h.service.CreateWorkOrderTask(workOrderID, req)
+	if err != nil {
+		return h.serviceError(c, err, "Work order not found", "Failed to create work order task")
+	}
+
+	return c.JSON(http.StatusCreated, task)
+}
+
+func (h *handler) UpdateWorkOrderTask(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	taskID, err := handlers.ParseID(c, "taskId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+
+	var req sMaintenance.UpdateWorkOrderTaskRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// Validación usando Echo con CustomValidator
+	if err := c.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	task, err := h.service.UpdateWorkOrderTask(workOrderID, taskID, req)
+	if err != nil {
+		return h.serviceError(c, err, "Task not found", "Failed to update work order task")
+	}
+
+	return c.JSON(http.StatusOK, task)
+}
+
+func (h *handler) DeleteWorkOrderTask(c echo.Context) error {
+	workOrderID, err := handlers.ParseID(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid work order ID"})
+	}
+
+	taskID, err := handlers.ParseID(c, "taskId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+
+	if err := h.service.DeleteWorkOrderTask(workOrderID, taskID); err != nil {
+		return h.serviceError(c, err, "Task not found", "Failed to delete work order task")
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Task deleted successfully"})
+}
@@ -0,0 +1,127 @@
+package hMaintenance
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/config"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/middlewares"
+	"github.com/remrafvil/Auriga_API/internal/services/sAuth"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service        sMaintenance.Service
+	authService    sAuth.Service
+	authMiddleware *middlewares.AuthMiddleware
+	config         *config.Settings
+	logger         *zap.Logger
+}
+
+type Result struct {
+	fx.Out
+
+	Handler handlers.Handler `group:"handlers"`
+}
+
+type Params struct {
+	fx.In
+
+	Service        sMaintenance.Service
+	AuthService    sAuth.Service
+	AuthMiddleware *middlewares.AuthMiddleware
+	Config         *config.Settings
+	Logger         *zap.Logger
+}
+
+func New(p Params) Result {
+	return Result{
+		Handler: &handler{
+			service:        p.Service,
+			authService:    p.AuthService,
+			authMiddleware: p.AuthMiddleware,
+			config:         p.Config,
+			logger:         p.Logger,
+		},
+	}
+}
+
+func (h *handler) RegisterRoutes(e *echo.Echo, s *config.Settings) {
+	r := e.Group("/maintenance")
+	/*middlewares*/
+	r.Use(h.authMiddleware.CombinedMiddleware())
+
+	// Work order routes
+	r.GET("/work-orders", h.GetWorkOrders)
+	r.GET("/work-orders/:id", h.GetWorkOrder)
+	r.POST("/work-orders", h.CreateWorkOrder)
+	r.PUT("/work-orders/:id", h.UpdateWorkOrder)
+	r.POST("/work-orders/:id/status", h.ChangeWorkOrderStatus)
+	r.GET("/work-orders/:id/history", h.GetWorkOrderHistory)
+	r.GET("/work-orders/:id/pack", h.GetWorkOrderPack)
+
+	// Maintenance plan routes
+	r.GET("/plans/usage", h.GetPlansUsage)
+	r.GET("/plans/:id/usage", h.GetPlanUsage)
+
+	// KPI routes
+	r.GET("/kpis/assets/:id", h.GetAssetKpis)
+	r.GET("/kpis/assets/:id/ranking", h.GetAssetKpiRanking)
+
+	// Cost routes
+	r.GET("/costs/assets/:id", h.GetCostReport)
+	r.GET("/labor-rates", h.GetLaborRates)
+	r.POST("/labor-rates", h.CreateLaborRate)
+	r.PUT("/labor-rates/:id", h.UpdateLaborRate)
+	r.DELETE("/labor-rates/:id", h.DeleteLaborRate)
+
+	// Event rule routes
+	r.GET("/event-rules", h.GetEventRules)
+	r.POST("/event-rules", h.CreateEventRule)
+	r.PUT("/event-rules/:id", h.UpdateEventRule)
+	r.DELETE("/event-rules/:id", h.DeleteEventRule)
+	r.POST("/stop-events/:id/process", h.ProcessStopEvent)
+
+	// Work order task routes
+	r.GET("/work-orders/:id/tasks", h.GetWorkOrderTasks)
+	r.POST("/work-orders/:id/tasks", h.CreateWorkOrderTask)
+	r.PUT("/work-orders/:id/tasks/:taskId", h.UpdateWorkOrderTask)
+	r.DELETE("/work-orders/:id/tasks/:taskId", h.DeleteWorkOrderTask)
+
+	// Work order assignment routes
+	r.GET("/work-orders/:id/assignments", h.GetWorkOrderAssignments)
+	r.POST("/work-orders/:id/assignments", h.CreateWorkOrderAssignment)
+	r.PUT("/work-orders/:id/assignments/:assignmentId", h.UpdateWorkOrderAssignment)
+	r.DELETE("/work-orders/:id/assignments/:assignmentId", h.DeleteWorkOrderAssignment)
+
+	// Work order time tracking routes
+	r.GET("/work-orders/:id/time", h.GetWorkOrderTime)
+	r.POST("/work-orders/:id/time/start", h.StartWork)
+	r.POST("/work-orders/:id/time/pause", h.PauseWork)
+	r.POST("/work-orders/:id/time/resume", h.ResumeWork)
+	r.POST("/work-orders/:id/time/stop", h.StopWork)
+
+	// Work order spare part routes
+	r.GET("/work-orders/:id/spare-parts", h.GetWorkOrderSpareParts)
+	r.POST("/work-orders/:id/spare-parts", h.CreateWorkOrderSparePart)
+	r.PUT("/work-orders/:id/spare-parts/:sparePartId", h.UpdateWorkOrderSparePart)
+	r.DELETE("/work-orders/:id/spare-parts/:sparePartId", h.DeleteWorkOrderSparePart)
+}
+
+// domainErrors códigos HTTP de los errores de dominio de sMaintenance
+var domainErrors = handlers.DomainErrors{
+	sMaintenance.ErrNotFound:          http.StatusNotFound,
+	sMaintenance.ErrInvalidRequest:    http.StatusBadRequest,
+	sMaintenance.ErrInvalidTransition: http.StatusConflict,
+}
+
+func (h *handler) currentEmployeeID(c echo.Context) (uint, error) {
+	return handlers.CurrentEmployeeID(c, h.authService)
+}
+
+func (h *handler) serviceError(c echo.Context, err error, notFoundMsg string, failMsg string) error {
+	return handlers.ServiceError(c, h.logger, domainErrors, err, notFoundMsg, failMsg)
+}
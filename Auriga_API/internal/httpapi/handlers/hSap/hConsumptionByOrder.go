@@ -2,12 +2,14 @@ package hSap
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/services/sLabor"
 )
 
 type mhOrderConsumption struct {
@@ -228,7 +230,8 @@ func (h *handler) OrderConsumptionSummaryToSAP(c echo.Context) error {
 	u.SapOrderCode = c.Request().Header.Get("SapOrderCode")
 	startDateStr := c.Request().Header.Get("StartDate")
 	endDateStr := c.Request().Header.Get("EndDate")
-	workdayID := c.Request().Header.Get("WorkdayID")
+	// El operario (WorkdayID) y su turno se toman del usuario autenticado; Turno solo se usa si no tiene
+	// turno asignado
 	turno := c.Request().Header.Get("Turno")
 
 	log.Println("📋 Headers recibidos:")
@@ -238,7 +241,6 @@ func (h *handler) OrderConsumptionSummaryToSAP(c echo.Context) error {
 	log.Println("  SapOrderCode:", u.SapOrderCode)
 	log.Println("  StartDate:", startDateStr)
 	log.Println("  EndDate:", endDateStr)
-	log.Println("  WorkdayID:", workdayID)
 	log.Println("  Turno:", turno)
 
 	// Parsear fechas
@@ -262,19 +264,14 @@ func (h *handler) OrderConsumptionSummaryToSAP(c echo.Context) error {
 		}
 	}
 
-	// Valores por defecto
-	if workdayID == "" {
-		workdayID = "EE1010171" // Valor por defecto si no se proporciona
-	}
-	if turno == "" {
-		turno = "T1" // Valor por defecto si no se proporciona
-	}
-
 	// Llamar al servicio para enviar a SAP
 	log.Println("🔄 Llamando a servicio DosingConsumptionSendToSAP...")
-	results, err := h.service.DosingConsumptionSendToSAP(u.Factory, u.ProdLine, u.SapOrderCode, startDate, endDate, workdayID, turno)
+	results, err := h.service.DosingConsumptionSendToSAP(c.Request().Context(), u.Factory, u.ProdLine, u.SapOrderCode, startDate, endDate, turno)
 	if err != nil {
 		log.Printf("❌ Error en servicio DosingConsumptionSendToSAP: %v", err)
+		if errors.Is(err, sLabor.ErrNoOperator) || errors.Is(err, sLabor.ErrNoWorkdayID) {
+			return c.JSON(http.StatusForbidden, responseMessage{Message: "SAP submissions require an authenticated user with a WorkdayID"})
+		}
 		// Incluso si hay errores, devolver los resultados para que el frontend pueda mostrar detalles
 		if results != nil && len(results) > 0 {
 			log.Printf("📤 Devolviendo resultados parciales: %d resultados", len(results))
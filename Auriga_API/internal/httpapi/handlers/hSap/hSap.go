@@ -58,5 +58,6 @@ func (h *handler) RegisterRoutes(e *echo.Echo, s *config.Settings) {
 	r.GET("/orderConsump/del", h.OrderConsumptionDel)
 	r.GET("/orderConsump/update", h.OrderConsumptionUpdate)
 	r.GET("/orderConsump/Calculate", h.OrderConsumptionCalculate)
-	r.GET("/orderConsump/CalcToSAP", h.OrderConsumptionSummaryToSAP)
+	// El envío a SAP se firma con el WorkdayID del usuario, así que exige autenticación
+	r.GET("/orderConsump/CalcToSAP", h.OrderConsumptionSummaryToSAP, h.authMiddleware.CombinedMiddleware())
 }
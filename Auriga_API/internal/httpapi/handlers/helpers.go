@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/remrafvil/Auriga_API/internal/services/sAuth"
+	"go.uber.org/zap"
+)
+
+// DomainErrors asocia los errores de dominio de un servicio con el código HTTP de la respuesta
+type DomainErrors map[error]int
+
+// CurrentEmployeeID obtiene el empleado autenticado a partir del user_id del middleware
+func CurrentEmployeeID(c echo.Context, authService sAuth.Service) (uint, error) {
+	userID, ok := c.Get("user_id").(string)
+	if !ok || userID == "" {
+		return 0, errors.New("user not authenticated")
+	}
+
+	employee, err := authService.FindCurrentUserInfo(userID, c.Request().Context())
+	if err != nil {
+		return 0, err
+	}
+	if employee == nil {
+		return 0, fmt.Errorf("employee not found for user %s", userID)
+	}
+
+	return employee.ID, nil
+}
+
+// ParseID lee un parámetro numérico de la ruta
+func ParseID(c echo.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// ServiceError traduce los errores de dominio a respuestas HTTP; el 404 responde con notFoundMsg y
+// cualquier otro error se registra y se responde con failMsg
+func ServiceError(c echo.Context, logger *zap.Logger, domain DomainErrors, err error, notFoundMsg string, failMsg string) error {
+	for domainErr, status := range domain {
+		if !errors.Is(err, domainErr) {
+			continue
+		}
+		if status == http.StatusNotFound {
+			return c.JSON(status, map[string]string{"error": notFoundMsg})
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+
+	logger.Error(failMsg, zap.Error(err))
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": failMsg})
+}
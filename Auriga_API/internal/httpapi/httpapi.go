@@ -5,8 +5,11 @@ import (
 	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hAuth"
 	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hEvents"
 	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hInfluxQuery"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hInventory"
 	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hLabor"
 	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hLabor_KKKK/hEmployee"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hLive"
+	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hMaintenance"
 	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hProducts"
 	"github.com/remrafvil/Auriga_API/internal/httpapi/handlers/hSap"
 	"github.com/remrafvil/Auriga_API/internal/httpapi/middlewares"
@@ -23,4 +26,7 @@ var Module = fx.Module("httpapi", fx.Provide(
 	hInfluxQuery.New,
 	hEmployee.New,
 	hLabor.New,
+	hMaintenance.New,
+	hInventory.New,
+	hLive.New,
 ))
@@ -320,8 +320,9 @@ func (m *AuthMiddleware) setUserContext(c echo.Context, claims jwt.MapClaims) {
 	// 1. Poner en Echo context (para compatibilidad)
 	c.Set("user_claims", claims)
 
-	// 2. Crear nuevo contexto estándar con toda la información
-	ctx := context.Background()
+	// 2. Crear nuevo contexto estándar con toda la información; deriva del de la petición para que
+	// se cancele al desconectarse el cliente
+	ctx := c.Request().Context()
 	ctx = context.WithValue(ctx, utils.CtxKeyUserClaims, claims)
 
 	// Información básica del usuario
@@ -450,6 +451,30 @@ func GetUserOrganization(c echo.Context) (map[string]interface{}, error) {
 	return nil, fmt.Errorf("user_organization not found in context")
 }
 
+// HasFactoryAccess indica si la organización del usuario autenticado incluye la fábrica
+func HasFactoryAccess(c echo.Context, factory string) bool {
+	organization, err := GetUserOrganization(c)
+	if err != nil {
+		return false
+	}
+	return hasFactoryAccess(organization, factory)
+}
+
+// HasAnyRole indica si el usuario autenticado tiene alguno de los roles en cualquier fábrica o departamento
+func HasAnyRole(c echo.Context, roles []string) bool {
+	organization, err := GetUserOrganization(c)
+	if err != nil {
+		return false
+	}
+	userRoles := getUserRolesFromOrganization(organization, "", "")
+	for _, role := range roles {
+		if hasRoleInList(userRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
 // Middlewares de autorización esenciales
 
 func RequireGroup(group string) echo.MiddlewareFunc {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -39,13 +40,19 @@ func MainMiddlewares(e *echo.Echo, s *config.Settings, logger *zap.Logger) {
 	//	e.Use(middleware.Gzip())
 	// Middlewares específicos según entorno
 	if !s.IsProduction() {
-		e.Use(middleware.BodyDump(func(c echo.Context, reqBody, resBody []byte) {
-			logger.Debug("HTTP Request",
-				zap.String("path", c.Path()),
-				zap.String("method", c.Request().Method),
-				zap.Int("request_size", len(reqBody)),
-				zap.Int("response_size", len(resBody)),
-			)
+		e.Use(middleware.BodyDumpWithConfig(middleware.BodyDumpConfig{
+			// Los flujos en tiempo real no terminan: no se guardan en memoria
+			Skipper: func(c echo.Context) bool {
+				return strings.HasPrefix(c.Request().URL.Path, "/live/")
+			},
+			Handler: func(c echo.Context, reqBody, resBody []byte) {
+				logger.Debug("HTTP Request",
+					zap.String("path", c.Path()),
+					zap.String("method", c.Request().Method),
+					zap.Int("request_size", len(reqBody)),
+					zap.Int("response_size", len(resBody)),
+				)
+			},
 		}))
 	}
 
@@ -12,15 +12,46 @@ import (
 
 type Repository interface {
 	EventsRawByLineList(factory string, location string) ([]rModels.MrRawEvents, error)
-	EventsRawByLineDel(id uint) ([]rModels.MrRawEvents, error)
+	EventsRawByLineDel(id uint, audit AuditInfo) ([]rModels.MrRawEvents, error)
+	EventsRawCreate(events []rModels.MrRawEvents, audit AuditInfo) ([]rModels.MrRawEvents, error)
 
-	EventsRawToCommitLine(id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string) ([]rModels.MrCommitEvents, error)
+	EventsRawToCommitLine(id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, audit AuditInfo) ([]rModels.MrCommitEvents, error)
 
 	EventsCommitByLineList(factory string, location string) ([]rModels.MrCommitEvents, error)
-	EventsCommitByLineAdd(eventTime time.Time, factory string, prodLine string, system string, machine string, part string, eventTypt string, eventCategory string) ([]rModels.MrCommitEvents, error)
-	EventsCommitByLineUpdate(id uint, eventTime time.Time, factory string, prodLine string, system string, machine string, part string, eventTypt string, eventCategory string) ([]rModels.MrCommitEvents, error)
-	EventsCommitByLineDel(id uint) ([]rModels.MrCommitEvents, error)
+	EventsCommitByLineAdd(eventTime time.Time, endTime *time.Time, factory string, prodLine string, system string, machine string, part string, eventTypt string, eventCategory string, audit AuditInfo) ([]rModels.MrCommitEvents, error)
+	EventsCommitByLineUpdate(id uint, eventTime time.Time, endTime *time.Time, factory string, prodLine string, system string, machine string, part string, eventTypt string, eventCategory string, audit AuditInfo) ([]rModels.MrCommitEvents, error)
+	EventsCommitByLineDel(id uint, audit AuditInfo) ([]rModels.MrCommitEvents, error)
 	EventsCommitByLineFind(id uint) (rModels.MrCommitEvents, string, error)
+	EventsCommitOpen(since time.Time) ([]rModels.MrCommitEvents, error)
+	EventsCommitClose(id uint, endTime time.Time, audit AuditInfo) (bool, error)
+	EventsCommitInRange(factory string, prodLine string, from time.Time, to time.Time) ([]rModels.MrCommitEvents, error)
+
+	EventsRawPending(eswCodes []int, afterID uint, limit int) ([]rModels.MrRawEvents, error)
+	EventsRawCommit(rawID uint, commitEvent *rModels.MrCommitEvents, audit AuditInfo) (bool, error)
+
+	EventsRawByIDs(ids []uint) ([]rModels.MrRawEvents, error)
+	EventsCommitByIDs(ids []uint) ([]rModels.MrCommitEvents, error)
+	EventsApplyBulk(changes *EventsBulkChanges) error
+
+	EventsAuditByEvent(kind string, id uint) ([]rModels.MrEventAudit, error)
+	EventsCommitRestore(id uint, audit AuditInfo) (rModels.MrCommitEvents, error)
+	EventsRawRestore(id uint, audit AuditInfo) (rModels.MrRawEvents, error)
+
+	EventsCommitToSapOutbox(id uint, message *rModels.MrSapOutbox, audit AuditInfo) error
+	SapOutboxClaim(now time.Time, leaseUntil time.Time, limit int) ([]rModels.MrSapOutbox, error)
+	SapOutboxRecordAttempt(message *rModels.MrSapOutbox, attempt *rModels.MrSapOutboxAttempt) error
+	SapOutboxList(status rModels.SapOutboxStatus, factory string, limit int) ([]rModels.MrSapOutbox, error)
+	SapOutboxByID(id uint) (rModels.MrSapOutbox, error)
+	SapOutboxRetry(id uint, now time.Time) (rModels.MrSapOutbox, error)
+	SapOutboxDiscard(id uint, discardedBy string, reason string) (rModels.MrSapOutbox, error)
+
+	GetClassificationRules() ([]rModels.MrEventClassificationRule, error)
+	GetClassificationRuleByID(id uint) (*rModels.MrEventClassificationRule, error)
+	CreateClassificationRule(rule *rModels.MrEventClassificationRule) error
+	UpdateClassificationRule(rule *rModels.MrEventClassificationRule) error
+	DeleteClassificationRule(id uint) error
+	GetActiveClassificationRules() ([]rModels.MrEventClassificationRule, error)
+	GetEventTypeByID(id uint) (*rModels.MrEventType, error)
 
 	FindCategoriesWithEventTypes(ctx context.Context) ([]rModels.MrEventCategory, error)
 	FindCategoryWithEventTypesByName(ctx context.Context, name string) (*rModels.MrEventCategory, error)
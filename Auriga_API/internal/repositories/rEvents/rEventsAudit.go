@@ -0,0 +1,141 @@
+package rEvents
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// Errores de la recuperación de eventos borrados
+var (
+	ErrNotDeleted   = errors.New("event is not deleted")
+	ErrRawCommitted = errors.New("raw event was committed")
+	ErrSentToSap    = errors.New("event was sent to SAP")
+)
+
+// AuditInfo autor y motivo que se registran con cada cambio
+type AuditInfo struct {
+	UserID   string
+	UserName string
+	Reason   string
+}
+
+// writeAudit añade una entrada al registro dentro de la transacción del cambio; before o after nil
+// quedan vacíos
+func writeAudit(tx *gorm.DB, audit AuditInfo, kind string, id uint, action string, before interface{}, after interface{}) error {
+	entry := rModels.MrEventAudit{
+		EventKind: kind,
+		EventID:   id,
+		Action:    action,
+		UserID:    audit.UserID,
+		UserName:  audit.UserName,
+		Reason:    audit.Reason,
+	}
+	if before != nil {
+		data, err := json.Marshal(before)
+		if err != nil {
+			return err
+		}
+		entry.Before = string(data)
+	}
+	if after != nil {
+		data, err := json.Marshal(after)
+		if err != nil {
+			return err
+		}
+		entry.After = string(data)
+	}
+	return tx.Create(&entry).Error
+}
+
+// EventsAuditByEvent devuelve la historia del evento, la más antigua primero
+func (m *repository) EventsAuditByEvent(kind string, id uint) ([]rModels.MrEventAudit, error) {
+	var data []rModels.MrEventAudit
+	err := m.db.Where("event_kind = ? AND event_id = ?", kind, id).
+		Order("created_at ASC, id ASC").
+		Find(&data).Error
+	return data, err
+}
+
+// EventsCommitRestore recupera un evento confirmado borrado lógicamente. Los que se retiraron al enviarlos
+// a SAP no se recuperan mientras su mensaje siga pendiente o entregado: se enviarían otra vez.
+func (m *repository) EventsCommitRestore(id uint, audit AuditInfo) (rModels.MrCommitEvents, error) {
+	var restored rModels.MrCommitEvents
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var before rModels.MrCommitEvents
+		if err := tx.Unscoped().First(&before, id).Error; err != nil {
+			return err
+		}
+		if !before.DeletedAt.Valid {
+			return ErrNotDeleted
+		}
+
+		var sent int64
+		if err := tx.Model(&rModels.MrSapOutbox{}).
+			Where("event_id = ? AND status IN ?", id, []rModels.SapOutboxStatus{rModels.SapOutboxPending, rModels.SapOutboxDelivered}).
+			Count(&sent).Error; err != nil {
+			return err
+		}
+		if sent > 0 {
+			return ErrSentToSap
+		}
+		if err := restore(tx, &rModels.MrCommitEvents{}, id); err != nil {
+			return err
+		}
+		if err := tx.First(&restored, id).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, rModels.EventAuditCommit, id, rModels.EventAuditRestored, before, restored)
+	})
+	return restored, err
+}
+
+// EventsRawRestore devuelve a la cola un evento bruto descartado. Solo se recuperan los que el registro
+// muestra descartados: los confirmados, o retirados antes de que existiera el registro, ya tienen o
+// pueden tener su evento confirmado.
+func (m *repository) EventsRawRestore(id uint, audit AuditInfo) (rModels.MrRawEvents, error) {
+	var restored rModels.MrRawEvents
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var before rModels.MrRawEvents
+		if err := tx.Unscoped().First(&before, id).Error; err != nil {
+			return err
+		}
+		if !before.DeletedAt.Valid {
+			return ErrNotDeleted
+		}
+
+		var removal rModels.MrEventAudit
+		err := tx.Where("event_kind = ? AND event_id = ? AND action IN ?", rModels.EventAuditRaw, id,
+			[]string{rModels.EventAuditDeleted, rModels.EventAuditCommitted}).
+			Order("created_at DESC, id DESC").
+			First(&removal).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && removal.Action == rModels.EventAuditCommitted) {
+			return ErrRawCommitted
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := restore(tx, &rModels.MrRawEvents{}, id); err != nil {
+			return err
+		}
+		if err := tx.First(&restored, id).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, rModels.EventAuditRaw, id, rModels.EventAuditRestored, before, restored)
+	})
+	return restored, err
+}
+
+func restore(tx *gorm.DB, model interface{}, id uint) error {
+	result := tx.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotDeleted
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package rEvents
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// ErrEventChanged otro proceso u operador modificó o retiró un evento del lote mientras se aplicaba
+var ErrEventChanged = errors.New("event changed concurrently")
+
+// RawCommit evento bruto que pasa a confirmado con la clasificación indicada
+type RawCommit struct {
+	RawID uint
+	Event *rModels.MrCommitEvents
+}
+
+// EventsBulkChanges cambios de una operación masiva que se aplican en una única transacción
+type EventsBulkChanges struct {
+	CommitRaw    []RawCommit
+	DiscardRaw   []uint
+	UpdateCommit []*rModels.MrCommitEvents // Se guardan inicio, fin, clasificación y OT
+	CreateCommit []*rModels.MrCommitEvents
+	DeleteCommit []uint
+	Audit        AuditInfo // Autor y motivo que se registran con cada cambio
+}
+
+func (m *repository) EventsRawByIDs(ids []uint) ([]rModels.MrRawEvents, error) {
+	var data []rModels.MrRawEvents
+	err := m.db.Where("id IN ?", ids).Find(&data).Error
+	return data, err
+}
+
+func (m *repository) EventsCommitByIDs(ids []uint) ([]rModels.MrCommitEvents, error) {
+	var data []rModels.MrCommitEvents
+	err := m.db.Where("id IN ?", ids).Order("event_time ASC").Find(&data).Error
+	return data, err
+}
+
+// EventsApplyBulk aplica todos los cambios o ninguno. Si algún evento ya no está como se leyó devuelve
+// ErrEventChanged y se deshace la transacción.
+func (m *repository) EventsApplyBulk(changes *EventsBulkChanges) error {
+	audit := changes.Audit
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range changes.CommitRaw {
+			if err := tx.Create(item.Event).Error; err != nil {
+				return err
+			}
+			if err := deleteRaw(tx, item.RawID, audit, rModels.EventAuditCommitted, item.Event); err != nil {
+				return err
+			}
+			if err := writeAudit(tx, audit, rModels.EventAuditCommit, item.Event.ID, rModels.EventAuditCreated, nil, item.Event); err != nil {
+				return err
+			}
+		}
+		for _, id := range changes.DiscardRaw {
+			if err := deleteRaw(tx, id, audit, rModels.EventAuditDeleted, nil); err != nil {
+				return err
+			}
+		}
+		for _, event := range changes.UpdateCommit {
+			var before, after rModels.MrCommitEvents
+			if err := tx.First(&before, event.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("%w: event %d", ErrEventChanged, event.ID)
+				}
+				return err
+			}
+			result := tx.Model(&rModels.MrCommitEvents{}).
+				Where("id = ?", event.ID).
+				Select("event_time", "end_time", "event_type", "event_category", "work_order_id").
+				Updates(event)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("%w: event %d", ErrEventChanged, event.ID)
+			}
+			if err := tx.First(&after, event.ID).Error; err != nil {
+				return err
+			}
+			if err := writeAudit(tx, audit, rModels.EventAuditCommit, event.ID, rModels.EventAuditUpdated, before, after); err != nil {
+				return err
+			}
+		}
+		for _, event := range changes.CreateCommit {
+			if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+			if err := writeAudit(tx, audit, rModels.EventAuditCommit, event.ID, rModels.EventAuditCreated, nil, event); err != nil {
+				return err
+			}
+		}
+		for _, id := range changes.DeleteCommit {
+			if err := deleteCommit(tx, id, audit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
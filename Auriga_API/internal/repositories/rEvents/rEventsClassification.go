@@ -0,0 +1,88 @@
+package rEvents
+
+import (
+	"errors"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+func (r *repository) GetClassificationRules() ([]rModels.MrEventClassificationRule, error) {
+	var rules []rModels.MrEventClassificationRule
+	err := r.db.Preload("EventType.Category").
+		Order("esw ASC, priority DESC, id ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *repository) GetClassificationRuleByID(id uint) (*rModels.MrEventClassificationRule, error) {
+	var rule rModels.MrEventClassificationRule
+	err := r.db.Preload("EventType.Category").First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *repository) CreateClassificationRule(rule *rModels.MrEventClassificationRule) error {
+	return r.db.Omit("EventType").Create(rule).Error
+}
+
+func (r *repository) UpdateClassificationRule(rule *rModels.MrEventClassificationRule) error {
+	return r.db.Omit("EventType").Save(rule).Error
+}
+
+func (r *repository) DeleteClassificationRule(id uint) error {
+	return r.db.Delete(&rModels.MrEventClassificationRule{}, id).Error
+}
+
+// GetActiveClassificationRules devuelve las reglas activas con el tipo y la categoría que asignan
+func (r *repository) GetActiveClassificationRules() ([]rModels.MrEventClassificationRule, error) {
+	var rules []rModels.MrEventClassificationRule
+	err := r.db.Preload("EventType.Category").
+		Where("active = ?", true).
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *repository) GetEventTypeByID(id uint) (*rModels.MrEventType, error) {
+	var eventType rModels.MrEventType
+	if err := r.db.Preload("Category").First(&eventType, id).Error; err != nil {
+		return nil, err
+	}
+	return &eventType, nil
+}
+
+// EventsRawPending devuelve los eventos brutos pendientes con alguno de los códigos ESW indicados,
+// a continuación de afterID y en orden de llegada
+func (r *repository) EventsRawPending(eswCodes []int, afterID uint, limit int) ([]rModels.MrRawEvents, error) {
+	var data []rModels.MrRawEvents
+	err := r.db.Where(`"ESW" IN ? AND id > ?`, eswCodes, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&data).Error
+	return data, err
+}
+
+// EventsRawCommit confirma un evento bruto: lo retira de la cola y crea el evento confirmado. Devuelve
+// false si otro proceso u operador ya lo había retirado.
+func (r *repository) EventsRawCommit(rawID uint, commitEvent *rModels.MrCommitEvents, audit AuditInfo) (bool, error) {
+	committed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(commitEvent).Error; err != nil {
+			return err
+		}
+		if err := deleteRaw(tx, rawID, audit, rModels.EventAuditCommitted, commitEvent); err != nil {
+			return err
+		}
+		if err := writeAudit(tx, audit, rModels.EventAuditCommit, commitEvent.ID, rModels.EventAuditCreated, nil, commitEvent); err != nil {
+			return err
+		}
+		committed = true
+		return nil
+	})
+	if errors.Is(err, ErrEventChanged) {
+		return false, nil
+	}
+	return committed, err
+}
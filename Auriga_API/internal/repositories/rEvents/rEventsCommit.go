@@ -3,6 +3,7 @@ package rEvents
 // lo llamaremso repositories
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,11 +19,12 @@ func (m *repository) EventsCommitByLineList(factory string, location string) ([]
 	return data, nil
 }
 
-func (m *repository) EventsCommitByLineAdd(eventTime time.Time, factory string, prodLine string, system string, machine string, part string, eventTypt string, eventCategory string) ([]rModels.MrCommitEvents, error) {
+func (m *repository) EventsCommitByLineAdd(eventTime time.Time, endTime *time.Time, factory string, prodLine string, system string, machine string, part string, eventTypt string, eventCategory string, audit AuditInfo) ([]rModels.MrCommitEvents, error) {
 	var eventsCommit []rModels.MrCommitEvents
 
 	newEventCommit := rModels.MrCommitEvents{
 		EventTime:     eventTime,
+		EndTime:       endTime,
 		Factory:       factory,
 		ProdLine:      prodLine,
 		System:        system,
@@ -32,9 +34,14 @@ func (m *repository) EventsCommitByLineAdd(eventTime time.Time, factory string,
 		EventCategory: eventCategory,
 	}
 	// Insertar el registro en la base de datos
-	err := m.db.Create(&newEventCommit).Error
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newEventCommit).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, rModels.EventAuditCommit, newEventCommit.ID, rModels.EventAuditCreated, nil, newEventCommit)
+	})
 	if err != nil {
-		if m.db.Error != nil && gorm.ErrDuplicatedKey == err {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			fmt.Println("Error: Duplicate record")
 		} else {
 			fmt.Println("Error inserting data:", err)
@@ -43,15 +50,21 @@ func (m *repository) EventsCommitByLineAdd(eventTime time.Time, factory string,
 	} else {
 		fmt.Println("Record inserted successfully:", newEventCommit)
 	}
+	eventsCommit = append(eventsCommit, newEventCommit)
 	return eventsCommit, nil
 }
 
-func (m *repository) EventsCommitByLineUpdate(id uint, eventTime time.Time, factory string, prodLine string, system string, machine string, part string, eventTypt string, eventCategory string) ([]rModels.MrCommitEvents, error) {
+// ErrEventInterval el fin que ya tiene el evento quedaría antes de la nueva hora de inicio
+var ErrEventInterval = errors.New("event end time is before event time")
+
+// EventsCommitByLineUpdate actualiza el evento confirmado; sin endTime se conserva el fin que ya tenía
+func (m *repository) EventsCommitByLineUpdate(id uint, eventTime time.Time, endTime *time.Time, factory string, prodLine string, system string, machine string, part string, eventTypt string, eventCategory string, audit AuditInfo) ([]rModels.MrCommitEvents, error) {
 	var eventsCommit []rModels.MrCommitEvents
 
 	updateEventCommit := rModels.MrCommitEvents{
 		// MrComponentSapCode: sapComponentCode,
 		EventTime:     eventTime,
+		EndTime:       endTime,
 		Factory:       factory,
 		ProdLine:      prodLine,
 		System:        system,
@@ -60,9 +73,12 @@ func (m *repository) EventsCommitByLineUpdate(id uint, eventTime time.Time, fact
 		EventType:     eventTypt,
 		EventCategory: eventCategory,
 	}
-	result := m.db.Model(&rModels.MrCommitEvents{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var before, after rModels.MrCommitEvents
+		if err := tx.First(&before, id).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{
 			"event_time":     updateEventCommit.EventTime,
 			"factory":        updateEventCommit.Factory,
 			"prod_line":      updateEventCommit.ProdLine,
@@ -71,34 +87,55 @@ func (m *repository) EventsCommitByLineUpdate(id uint, eventTime time.Time, fact
 			"part":           updateEventCommit.Part,
 			"event_type":     updateEventCommit.EventType,
 			"event_category": updateEventCommit.EventCategory,
-		})
+		}
+		// El cliente que no envía el fin no reabre el evento; los eventos se cierran con EventsCommitByLineClose
+		if endTime != nil {
+			updates["end_time"] = endTime
+		} else if before.EndTime != nil && before.EndTime.Before(eventTime) {
+			return ErrEventInterval
+		}
+		// Reclasificado o movido a otro activo: la OT ya no corresponde y vuelve a pasar por las reglas
+		if reclassified(before, updateEventCommit) {
+			updates["work_order_id"] = nil
+			updates["maint_processed_at"] = nil
+			updates["maint_attempts"] = 0
+			updates["maint_error"] = ""
+		}
+		result := tx.Model(&rModels.MrCommitEvents{}).
+			Where("id = ?", id).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := tx.First(&after, id).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, rModels.EventAuditCommit, id, rModels.EventAuditUpdated, before, after)
+	})
 
-	if result.Error != nil {
-		fmt.Println("Error updating Event Commit:", result.Error)
-		return eventsCommit, result.Error
-	} else if result.RowsAffected == 0 {
-		fmt.Println("No Event Commit found to Update")
-	} else {
-		fmt.Println("Event Commit Updated Successfully")
+	if err != nil {
+		fmt.Println("Error updating Event Commit:", err)
+		return eventsCommit, err
 	}
+	fmt.Println("Event Commit Updated Successfully")
 	return eventsCommit, nil
 }
 
-func (m *repository) EventsCommitByLineDel(id uint) ([]rModels.MrCommitEvents, error) {
+// reclassified indica si cambió la clasificación o la ruta del evento, que deciden su regla de mantenimiento
+func reclassified(before, after rModels.MrCommitEvents) bool {
+	return before.EventType != after.EventType || before.EventCategory != after.EventCategory ||
+		before.Factory != after.Factory || before.ProdLine != after.ProdLine || before.System != after.System ||
+		before.Machine != after.Machine || before.Part != after.Part
+}
+
+func (m *repository) EventsCommitByLineDel(id uint, audit AuditInfo) ([]rModels.MrCommitEvents, error) {
 	var eventsCommit []rModels.MrCommitEvents
 
-	err := m.db.Delete(&rModels.MrCommitEvents{}, id).Error
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		return deleteCommit(tx, id, audit)
+	})
 	if err != nil {
-		panic("failed to delete record")
-	}
-
-	// Confirmar que se eliminó el registro
-	var count int64
-	m.db.Model(&rModels.MrCommitEvents{}).Where("id = ?", id).Count(&count)
-	if count == 0 {
-		println("Registro eliminado con éxito")
-	} else {
-		println("El registro aún existe")
+		return eventsCommit, fmt.Errorf("failed to delete event %d: %w", id, err)
 	}
 	return eventsCommit, nil
 }
@@ -124,6 +161,79 @@ func (m *repository) EventsCommitByLineFind(id uint) (rModels.MrCommitEvents, st
 	return eventsCommit, order.OrderNumber, nil
 }
 
+// EventsCommitOpen devuelve los eventos de parada que aún no tienen fin y empezaron desde since
+func (m *repository) EventsCommitOpen(since time.Time) ([]rModels.MrCommitEvents, error) {
+	var data []rModels.MrCommitEvents
+	if err := m.db.Where("end_time IS NULL AND event_time >= ?", since).Order("event_time ASC").Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// EventsCommitClose fija el fin de un evento abierto. Devuelve false si el evento no existe, ya
+// estaba cerrado o empieza después del fin indicado.
+func (m *repository) EventsCommitClose(id uint, endTime time.Time, audit AuditInfo) (bool, error) {
+	closed := false
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var before, after rModels.MrCommitEvents
+		if err := tx.First(&before, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		result := tx.Model(&rModels.MrCommitEvents{}).
+			Where("id = ? AND end_time IS NULL AND event_time <= ?", id, endTime).
+			Update("end_time", endTime)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.First(&after, id).Error; err != nil {
+			return err
+		}
+		closed = true
+		return writeAudit(tx, audit, rModels.EventAuditCommit, id, rModels.EventAuditClosed, before, after)
+	})
+	return closed, err
+}
+
+// EventsCommitInRange devuelve los eventos de la fábrica que se solapan con el intervalo, incluidos los
+// abiertos. prodLine vacío incluye todas las líneas.
+func (m *repository) EventsCommitInRange(factory string, prodLine string, from time.Time, to time.Time) ([]rModels.MrCommitEvents, error) {
+	var data []rModels.MrCommitEvents
+	query := m.db.Where("factory = ? AND event_time < ? AND (end_time IS NULL OR end_time > ?)", factory, to, from)
+	if prodLine != "" {
+		query = query.Where("prod_line = ?", prodLine)
+	}
+	if err := query.Order("event_time ASC").Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// deleteCommit borra lógicamente el evento confirmado y registra el cambio. Falla con ErrEventChanged si
+// ya estaba borrado.
+func deleteCommit(tx *gorm.DB, id uint, audit AuditInfo) error {
+	var before rModels.MrCommitEvents
+	if err := tx.First(&before, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: event %d", ErrEventChanged, id)
+		}
+		return err
+	}
+	result := tx.Delete(&rModels.MrCommitEvents{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: event %d", ErrEventChanged, id)
+	}
+	return writeAudit(tx, audit, rModels.EventAuditCommit, id, rModels.EventAuditDeleted, before, nil)
+}
+
 func DB_InitEventsCommit(c *gorm.DB) { //
 	timeString := "2024-05-195 02:30:45"
 	theTime, _ := time.Parse("2006-01-02 03:04:05", timeString)
@@ -1,6 +1,7 @@
 package rEvents
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (m *repository) EventsRawByLineList(factory string, location string) ([]rModels.MrRawEvents, error) {
@@ -18,26 +20,19 @@ func (m *repository) EventsRawByLineList(factory string, location string) ([]rMo
 	return data, nil
 }
 
-func (m *repository) EventsRawByLineDel(id uint) ([]rModels.MrRawEvents, error) {
+func (m *repository) EventsRawByLineDel(id uint, audit AuditInfo) ([]rModels.MrRawEvents, error) {
 	var eventsRaw []rModels.MrRawEvents
 
-	err := m.db.Delete(&rModels.MrRawEvents{}, id).Error
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		return deleteRaw(tx, id, audit, rModels.EventAuditDeleted, nil)
+	})
 	if err != nil {
-		panic("failed to delete record")
-	}
-
-	// Confirmar que se eliminó el registro
-	var count int64
-	m.db.Model(&rModels.MrRawEvents{}).Where("id = ?", id).Count(&count)
-	if count == 0 {
-		println("Registro eliminado con éxito")
-	} else {
-		println("El registro aún existe")
+		return eventsRaw, fmt.Errorf("failed to delete raw event %d: %w", id, err)
 	}
 	return eventsRaw, nil
 }
 
-func (m *repository) EventsRawToCommitLine(id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string) ([]rModels.MrCommitEvents, error) {
+func (m *repository) EventsRawToCommitLine(id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, audit AuditInfo) ([]rModels.MrCommitEvents, error) {
 	var eventsCommit []rModels.MrCommitEvents
 
 	err := m.db.Transaction(func(tx *gorm.DB) error {
@@ -65,11 +60,15 @@ func (m *repository) EventsRawToCommitLine(id uint, eventTime time.Time, factory
 		if err := tx.Create(&commitEvent).Error; err != nil {
 			return fmt.Errorf("error creating rModels.MrCommitEvents: %w", err)
 		}
+		if err := writeAudit(tx, audit, rModels.EventAuditCommit, commitEvent.ID, rModels.EventAuditCreated, nil, commitEvent); err != nil {
+			return err
+		}
 
 		// Borrar el registro de rModels.MrRawEvents
-		if err := tx.Delete(&rawEvent).Error; err != nil {
+		if err := deleteRaw(tx, rawEvent.ID, audit, rModels.EventAuditCommitted, commitEvent); err != nil {
 			return fmt.Errorf("error deleting rModels.MrRawEvents: %w", err)
 		}
+		eventsCommit = append(eventsCommit, commitEvent)
 
 		// Si todo se ejecuta sin errores, se confirma la transacción
 		return nil
@@ -78,8 +77,56 @@ func (m *repository) EventsRawToCommitLine(id uint, eventTime time.Time, factory
 	// Manejo de errores fuera de la transacción
 	if err != nil {
 		fmt.Printf("Transaction failed: %v\n", err)
-	} else {
-		fmt.Println("Transaction succeeded")
+		return nil, err
 	}
+	fmt.Println("Transaction succeeded")
 	return eventsCommit, nil
 }
+
+// EventsRawCreate inserta los eventos descartando los que ya existen con el mismo instante y ruta,
+// incluidos los ya confirmados (borrados lógicamente), según el índice único idx_mr_raw_events_natural_key.
+// Devuelve los que se han insertado.
+func (m *repository) EventsRawCreate(events []rModels.MrRawEvents, audit AuditInfo) ([]rModels.MrRawEvents, error) {
+	var inserted []rModels.MrRawEvents
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		for i := range events {
+			event := &events[i]
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			if err := writeAudit(tx, audit, rModels.EventAuditRaw, event.ID, rModels.EventAuditCreated, nil, event); err != nil {
+				return err
+			}
+			inserted = append(inserted, *event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+// deleteRaw borra lógicamente el evento bruto y registra el cambio. Falla con ErrEventChanged si ya no
+// estaba en la cola.
+func deleteRaw(tx *gorm.DB, id uint, audit AuditInfo, action string, after interface{}) error {
+	var before rModels.MrRawEvents
+	if err := tx.First(&before, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: raw event %d", ErrEventChanged, id)
+		}
+		return err
+	}
+	result := tx.Delete(&rModels.MrRawEvents{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: raw event %d", ErrEventChanged, id)
+	}
+	return writeAudit(tx, audit, rModels.EventAuditRaw, id, action, before, after)
+}
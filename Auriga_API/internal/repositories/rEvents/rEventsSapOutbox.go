@@ -0,0 +1,148 @@
+package rEvents
+
+import (
+	"errors"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOutboxState el mensaje ya se entregó o se descartó y no admite la operación
+var ErrOutboxState = errors.New("outbox message is not pending or dead")
+
+// EventsCommitToSapOutbox retira el evento confirmado y encola su envío a SAP en la misma transacción.
+// Devuelve ErrSentToSap si el evento ya tiene un mensaje pendiente o entregado. Si el mensaje anterior
+// con la misma clave quedó muerto o descartado, se reutiliza para que SAP reciba la misma clave.
+func (m *repository) EventsCommitToSapOutbox(id uint, message *rModels.MrSapOutbox, audit AuditInfo) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		var previous rModels.MrSapOutbox
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("idempotency_key = ?", message.IdempotencyKey).
+			First(&previous).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var sent int64
+		if err := tx.Model(&rModels.MrSapOutbox{}).
+			Where("event_id = ? AND status IN ?", id, []rModels.SapOutboxStatus{rModels.SapOutboxPending, rModels.SapOutboxDelivered}).
+			Count(&sent).Error; err != nil {
+			return err
+		}
+		if sent > 0 {
+			return ErrSentToSap
+		}
+
+		if err := deleteCommit(tx, id, audit); err != nil {
+			return err
+		}
+		if previous.ID == 0 {
+			return tx.Omit("AttemptLog").Create(message).Error
+		}
+		message.ID = previous.ID
+		message.CreatedAt = previous.CreatedAt
+		return tx.Omit("AttemptLog").Save(message).Error
+	})
+}
+
+// SapOutboxClaim reserva los mensajes pendientes que toca enviar aplazando su siguiente intento hasta
+// leaseUntil. Las filas bloqueadas por otra réplica se saltan, y si el proceso cae a mitad de envío el
+// mensaje vuelve a la cola al vencer la reserva.
+func (m *repository) SapOutboxClaim(now time.Time, leaseUntil time.Time, limit int) ([]rModels.MrSapOutbox, error) {
+	var messages []rModels.MrSapOutbox
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", rModels.SapOutboxPending, now).
+			Order("next_attempt_at ASC, id ASC").
+			Limit(limit).
+			Find(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		return tx.Model(&rModels.MrSapOutbox{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+	})
+	return messages, err
+}
+
+// SapOutboxRecordAttempt guarda el intento y el nuevo estado del mensaje. Si entretanto se descartó, solo
+// queda registrado el intento.
+func (m *repository) SapOutboxRecordAttempt(message *rModels.MrSapOutbox, attempt *rModels.MrSapOutboxAttempt) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&rModels.MrSapOutbox{}).
+			Where("id = ? AND status = ?", message.ID, rModels.SapOutboxPending).
+			Updates(map[string]interface{}{
+				"status":          message.Status,
+				"attempts":        message.Attempts,
+				"next_attempt_at": message.NextAttemptAt,
+				"last_error":      message.LastError,
+				"delivered_at":    message.DeliveredAt,
+			}).Error
+	})
+}
+
+// SapOutboxList mensajes en el estado indicado, los más antiguos primero; factory vacío devuelve todas
+func (m *repository) SapOutboxList(status rModels.SapOutboxStatus, factory string, limit int) ([]rModels.MrSapOutbox, error) {
+	var messages []rModels.MrSapOutbox
+	query := m.db.Where("status = ?", status)
+	if factory != "" {
+		query = query.Where("factory = ?", factory)
+	}
+	err := query.Order("created_at ASC, id ASC").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+func (m *repository) SapOutboxByID(id uint) (rModels.MrSapOutbox, error) {
+	var message rModels.MrSapOutbox
+	err := m.db.Preload("AttemptLog", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&message, id).Error
+	return message, err
+}
+
+// SapOutboxRetry devuelve a la cola un mensaje pendiente o muerto para enviarlo ya, con los reintentos
+// a cero
+func (m *repository) SapOutboxRetry(id uint, now time.Time) (rModels.MrSapOutbox, error) {
+	return m.sapOutboxResolve(id, map[string]interface{}{
+		"status":          rModels.SapOutboxPending,
+		"attempts":        0,
+		"next_attempt_at": now,
+	})
+}
+
+// SapOutboxDiscard retira un mensaje pendiente o muerto sin enviarlo
+func (m *repository) SapOutboxDiscard(id uint, discardedBy string, reason string) (rModels.MrSapOutbox, error) {
+	return m.sapOutboxResolve(id, map[string]interface{}{
+		"status":         rModels.SapOutboxDiscarded,
+		"discarded_by":   discardedBy,
+		"discard_reason": reason,
+	})
+}
+
+func (m *repository) sapOutboxResolve(id uint, updates map[string]interface{}) (rModels.MrSapOutbox, error) {
+	var message rModels.MrSapOutbox
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&message, id).Error; err != nil {
+			return err
+		}
+		if message.Status != rModels.SapOutboxPending && message.Status != rModels.SapOutboxDead {
+			return ErrOutboxState
+		}
+		if err := tx.Model(&message).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&message, id).Error
+	})
+	return message, err
+}
@@ -0,0 +1,125 @@
+package rInventory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/config"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetStocks(filter StockFilter) ([]rModels.MrSparePartStock, error)
+	GetStockByID(id uint) (*rModels.MrSparePartStock, error)
+	GetMovements(filter MovementFilter) ([]rModels.MrAssetRegisterMovement, error)
+	GetExpiringStocks(warehouseAssetID uint, until time.Time) ([]rModels.MrSparePartStock, error)
+
+	Receive(in MovementInput) (*rModels.MrAssetRegisterMovement, error)
+	IssueToWorkOrder(in MovementInput) ([]rModels.MrAssetRegisterMovement, error)
+	ReturnFromWorkOrder(in MovementInput) (*rModels.MrAssetRegisterMovement, error)
+	Transfer(in MovementInput, toWarehouseAssetID uint, toLocation string) ([]rModels.MrAssetRegisterMovement, error)
+	Adjust(in MovementInput) (*rModels.MrAssetRegisterMovement, error)
+
+	GetReorderLines() ([]ReorderLine, error)
+	CreateReorderPurchaseOrders(orders []*rModels.MrPurchaseOrder) ([]rModels.MrPurchaseOrder, error)
+
+	GetPurchaseOrders(filter PurchaseOrderFilter) ([]rModels.MrPurchaseOrder, error)
+	GetPurchaseOrderByID(id uint) (*rModels.MrPurchaseOrder, error)
+	CreatePurchaseOrder(order *rModels.MrPurchaseOrder) error
+	TransitionPurchaseOrder(id uint, from []rModels.PurchaseOrderStatus, to rModels.PurchaseOrderStatus, fields map[string]interface{}) (bool, error)
+	CancelPurchaseOrder(id uint, from []rModels.PurchaseOrderStatus, reason string, cancelledAt time.Time) (bool, error)
+	ReceivePurchaseOrder(id uint, lines []PurchaseReceiptLine, in MovementInput) ([]rModels.MrAssetRegisterMovement, error)
+}
+
+// Errores del libro de stock
+var (
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	ErrInvalidMovement    = errors.New("invalid stock movement")
+	ErrInvalidOrderStatus = errors.New("invalid purchase order status")
+	ErrExpiredBatch       = errors.New("expired batch")
+)
+
+// StockFilter criterios de búsqueda de stock
+type StockFilter struct {
+	WarehouseAssetID uint
+	ProductID        uint
+}
+
+// MovementFilter criterios de búsqueda de movimientos
+type MovementFilter struct {
+	ProductID        uint
+	WarehouseAssetID uint
+	WorkOrderID      uint
+	StockID          uint
+	From             time.Time
+	To               time.Time
+}
+
+// MovementInput datos de un movimiento. El stock se identifica por StockID o por almacén+producto+lote;
+// en las salidas a OT sin lote se aplica FEFO.
+// Quantity es siempre positiva salvo en los ajustes, donde indica la variación con signo.
+type MovementInput struct {
+	StockID          *uint
+	WarehouseAssetID uint
+	ProductID        uint
+	BatchNumber      string
+	ExpiryDate       *time.Time
+	Location         string
+	Quantity         float64
+	UnitCost         *float64 // Nil: se usa el coste medio actual del stock
+	WorkOrderID      *uint
+	PurchaseOrderID  *uint
+	AssetID          *uint
+	ReferenceNumber  string
+	Reason           string
+	CreatedBy        uint
+}
+
+// PurchaseOrderFilter criterios de búsqueda de órdenes de compra
+type PurchaseOrderFilter struct {
+	WarehouseAssetID uint
+	Status           []rModels.PurchaseOrderStatus
+	Supplier         string
+	AutoGenerated    *bool
+}
+
+// PurchaseReceiptLine cantidad recibida de una línea de la orden de compra
+type PurchaseReceiptLine struct {
+	ItemID      uint
+	Quantity    float64
+	UnitPrice   *float64 // Precio facturado si difiere del pedido
+	BatchNumber string
+	ExpiryDate  *time.Time
+	Location    string
+}
+
+// ReorderLine situación de stock de un producto en un almacén para el cálculo de reaprovisionamiento
+type ReorderLine struct {
+	WarehouseAssetID uint
+	ProductID        uint
+	ProductName      string
+	OnHand           float64
+	MinStock         float64
+	MaxStock         float64
+	UnitCost         float64 // Coste medio del stock
+	OnOrder          float64 // Pendiente de recibir en órdenes de compra abiertas
+	Reserved         float64 // Reservado por OT abiertas
+	Supplier         string  // Proveedor de la última compra o, en su defecto, el fabricante
+	LastUnitPrice    float64
+}
+
+type repository struct {
+	db     *gorm.DB
+	config *config.Settings
+	logger *zap.Logger
+}
+
+func New(db *gorm.DB, cfg *config.Settings, logger *zap.Logger) Repository {
+	return &repository{
+		db:     db,
+		config: cfg,
+		logger: logger,
+	}
+}
@@ -0,0 +1,421 @@
+package rInventory
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stockEpsilon tolerancia para comparar cantidades decimales
+const stockEpsilon = 1e-9
+
+func (r *repository) Receive(in MovementInput) (*rModels.MrAssetRegisterMovement, error) {
+	if in.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: receipt quantity must be positive", ErrInvalidMovement)
+	}
+
+	var movement *rModels.MrAssetRegisterMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		stock, err := lockStock(tx, in, true)
+		if err != nil {
+			return err
+		}
+		movement, err = applyMovement(tx, stock, rModels.AssetMovementTypeReceipt, in.Quantity, in.UnitCost, in)
+		return err
+	})
+	return movement, err
+}
+
+// IssueToWorkOrder saca material del almacén para una OT, lo registra como repuesto usado,
+// consume las reservas del producto hasta la cantidad entregada y recalcula el coste de la OT. Si no se indica stock ni lote, el material
+// se toma de los lotes no caducados por orden de caducidad (FEFO), pudiendo generar varios movimientos.
+func (r *repository) IssueToWorkOrder(in MovementInput) ([]rModels.MrAssetRegisterMovement, error) {
+	if in.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: issue quantity must be positive", ErrInvalidMovement)
+	}
+	if in.WorkOrderID == nil {
+		return nil, fmt.Errorf("%w: work order is required to issue stock", ErrInvalidMovement)
+	}
+
+	var movements []rModels.MrAssetRegisterMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var stocks []*rModels.MrSparePartStock
+		if in.StockID != nil || in.BatchNumber != "" {
+			stock, err := lockStock(tx, in, false)
+			if err != nil {
+				return err
+			}
+			if isExpired(stock, time.Now()) {
+				return fmt.Errorf("%w: batch %s of stock %d expired on %s", ErrExpiredBatch, stock.BatchNumber, stock.ID, stock.ExpiryDate.Format("2006-01-02"))
+			}
+			stocks = []*rModels.MrSparePartStock{stock}
+		} else {
+			var err error
+			if stocks, err = lockFEFOStocks(tx, in.WarehouseAssetID, in.ProductID, in.Quantity); err != nil {
+				return err
+			}
+		}
+
+		remaining := in.Quantity
+		var issuedStockIDs []uint
+		for _, stock := range stocks {
+			quantity := math.Min(remaining, stock.Quantity)
+			if len(stocks) == 1 {
+				// Con un único stock se deja que applyMovement informe de la falta de material
+				quantity = remaining
+			}
+
+			movement, err := applyMovement(tx, stock, rModels.AssetMovementTypeConsumption, -quantity, nil, in)
+			if err != nil {
+				return err
+			}
+			if err := createWorkOrderSparePart(tx, stock, movement, quantity, in.AssetID); err != nil {
+				return err
+			}
+			movements = append(movements, *movement)
+			issuedStockIDs = append(issuedStockIDs, stock.ID)
+
+			remaining -= quantity
+			if remaining < stockEpsilon {
+				break
+			}
+		}
+
+		if err := consumeReservations(tx, *in.WorkOrderID, stocks[0].ProductID, issuedStockIDs, in.Quantity); err != nil {
+			return err
+		}
+		return rMaintenance.RecalculateWorkOrderCost(tx, *in.WorkOrderID, r.config.Maintenance.DefaultLaborRate)
+	})
+	return movements, err
+}
+
+// consumeReservations marca como entregadas las reservas del producto en la OT hasta cubrir quantity,
+// empezando por las de los stocks de los que ha salido el material. Si la última reserva no se cubre
+// entera se divide: lo entregado pasa a una reserva nueva y el resto sigue reservado.
+func consumeReservations(tx *gorm.DB, workOrderID uint, productID uint, stockIDs []uint, quantity float64) error {
+	var reservations []rModels.MrMaintSparePartReservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("work_order_id = ? AND product_id = ? AND status = ?", workOrderID, productID, rModels.ReservationReserved).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "CASE WHEN stock_id IN ? THEN 0 ELSE 1 END, id",
+			Vars: []interface{}{stockIDs},
+		}}).
+		Find(&reservations).Error; err != nil {
+		return err
+	}
+
+	remaining := quantity
+	for _, reservation := range reservations {
+		if remaining < stockEpsilon {
+			break
+		}
+		if reservation.Quantity <= remaining+stockEpsilon {
+			if err := tx.Model(&rModels.MrMaintSparePartReservation{}).
+				Where("id = ?", reservation.ID).
+				Update("status", rModels.ReservationIssued).Error; err != nil {
+				return err
+			}
+			remaining -= reservation.Quantity
+			continue
+		}
+
+		if err := tx.Model(&rModels.MrMaintSparePartReservation{}).
+			Where("id = ?", reservation.ID).
+			Update("quantity", reservation.Quantity-remaining).Error; err != nil {
+			return err
+		}
+		issued := rModels.MrMaintSparePartReservation{
+			WorkOrderID: reservation.WorkOrderID,
+			ProductID:   reservation.ProductID,
+			StockID:     reservation.StockID,
+			Quantity:    remaining,
+			Unit:        reservation.Unit,
+			Status:      rModels.ReservationIssued,
+		}
+		if err := tx.Omit("WorkOrder", "Product").Create(&issued).Error; err != nil {
+			return err
+		}
+		remaining = 0
+	}
+	return nil
+}
+
+// ReturnFromWorkOrder devuelve al almacén material entregado a una OT al coste con el que salió
+// y recalcula el coste de la OT
+func (r *repository) ReturnFromWorkOrder(in MovementInput) (*rModels.MrAssetRegisterMovement, error) {
+	if in.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: return quantity must be positive", ErrInvalidMovement)
+	}
+	if in.WorkOrderID == nil {
+		return nil, fmt.Errorf("%w: work order is required to return stock", ErrInvalidMovement)
+	}
+
+	var movement *rModels.MrAssetRegisterMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		stock, err := lockStock(tx, in, false)
+		if err != nil {
+			return err
+		}
+
+		// No se puede devolver más de lo entregado neto a la OT desde este stock. Se cuenta en el libro de
+		// movimientos: las líneas de repuesto de la OT también incluyen material añadido a mano
+		var issued struct {
+			Quantity float64
+			Cost     float64
+		}
+		if err := tx.Model(&rModels.MrAssetRegisterMovement{}).
+			Select("COALESCE(-SUM(quantity), 0) AS quantity, COALESCE(-SUM(total_cost), 0) AS cost").
+			Where("work_order_id = ? AND stock_id = ? AND movement_type IN ?", *in.WorkOrderID, stock.ID,
+				[]rModels.AssetMovementType{rModels.AssetMovementTypeConsumption, rModels.AssetMovementTypeReturn}).
+			Scan(&issued).Error; err != nil {
+			return err
+		}
+		if in.Quantity > issued.Quantity+stockEpsilon {
+			return fmt.Errorf("%w: only %.6f issued to work order %d from stock %d", ErrInvalidMovement, issued.Quantity, *in.WorkOrderID, stock.ID)
+		}
+
+		unitCost := in.UnitCost
+		if unitCost == nil && issued.Quantity > 0 {
+			cost := issued.Cost / issued.Quantity
+			unitCost = &cost
+		}
+
+		movement, err = applyMovement(tx, stock, rModels.AssetMovementTypeReturn, in.Quantity, unitCost, in)
+		if err != nil {
+			return err
+		}
+
+		if err := createWorkOrderSparePart(tx, stock, movement, -in.Quantity, in.AssetID); err != nil {
+			return err
+		}
+		return rMaintenance.RecalculateWorkOrderCost(tx, *in.WorkOrderID, r.config.Maintenance.DefaultLaborRate)
+	})
+	return movement, err
+}
+
+// Transfer mueve material entre almacenes conservando el coste medio de origen
+func (r *repository) Transfer(in MovementInput, toWarehouseAssetID uint, toLocation string) ([]rModels.MrAssetRegisterMovement, error) {
+	if in.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: transfer quantity must be positive", ErrInvalidMovement)
+	}
+
+	var movements []rModels.MrAssetRegisterMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		source, err := lockStock(tx, in, false)
+		if err != nil {
+			return err
+		}
+		if source.WarehouseAssetID == toWarehouseAssetID {
+			return fmt.Errorf("%w: source and destination warehouse are the same", ErrInvalidMovement)
+		}
+
+		out, err := applyMovement(tx, source, rModels.AssetMovementTypeTransfer, -in.Quantity, nil, in)
+		if err != nil {
+			return err
+		}
+
+		destInput := in
+		destInput.StockID = nil
+		destInput.WarehouseAssetID = toWarehouseAssetID
+		destInput.ProductID = source.ProductID
+		destInput.BatchNumber = source.BatchNumber
+		destInput.ExpiryDate = source.ExpiryDate
+		destInput.Location = toLocation
+		destination, err := lockStock(tx, destInput, true)
+		if err != nil {
+			return err
+		}
+
+		cost := out.UnitCost
+		destInput.WorkOrderID = nil
+		incoming, err := applyMovement(tx, destination, rModels.AssetMovementTypeTransfer, in.Quantity, &cost, destInput)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(incoming).Update("related_movement_id", out.ID).Error; err != nil {
+			return err
+		}
+		incoming.RelatedMovementID = &out.ID
+
+		movements = []rModels.MrAssetRegisterMovement{*out, *incoming}
+		return nil
+	})
+	return movements, err
+}
+
+// Adjust regulariza el stock tras un recuento; Quantity lleva signo
+func (r *repository) Adjust(in MovementInput) (*rModels.MrAssetRegisterMovement, error) {
+	if math.Abs(in.Quantity) < stockEpsilon {
+		return nil, fmt.Errorf("%w: adjustment quantity cannot be zero", ErrInvalidMovement)
+	}
+
+	var movement *rModels.MrAssetRegisterMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		stock, err := lockStock(tx, in, in.Quantity > 0)
+		if err != nil {
+			return err
+		}
+		movement, err = applyMovement(tx, stock, rModels.AssetMovementTypeAdjustment, in.Quantity, in.UnitCost, in)
+		return err
+	})
+	return movement, err
+}
+
+// lockFEFOStocks bloquea los lotes con existencias de un producto en un almacén, ordenados por caducidad,
+// y comprueba que los no caducados cubren la cantidad pedida
+func lockFEFOStocks(tx *gorm.DB, warehouseAssetID uint, productID uint, quantity float64) ([]*rModels.MrSparePartStock, error) {
+	var stocks []*rModels.MrSparePartStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("warehouse_asset_id = ? AND product_id = ? AND quantity > 0", warehouseAssetID, productID).
+		Order("expiry_date ASC NULLS LAST, id").
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var usable []*rModels.MrSparePartStock
+	var available, expired float64
+	for _, stock := range stocks {
+		if isExpired(stock, now) {
+			expired += stock.Quantity
+			continue
+		}
+		usable = append(usable, stock)
+		available += stock.Quantity
+	}
+
+	if available < quantity-stockEpsilon {
+		if expired > 0 {
+			return nil, fmt.Errorf("%w: product %d in warehouse %d has %.6f available (%.6f in expired batches), cannot issue %.6f",
+				ErrInsufficientStock, productID, warehouseAssetID, available, expired, quantity)
+		}
+		return nil, fmt.Errorf("%w: product %d in warehouse %d has %.6f available, cannot issue %.6f",
+			ErrInsufficientStock, productID, warehouseAssetID, available, quantity)
+	}
+	return usable, nil
+}
+
+// isExpired indica si el lote ha alcanzado su fecha de caducidad
+func isExpired(stock *rModels.MrSparePartStock, now time.Time) bool {
+	return stock.ExpiryDate != nil && !stock.ExpiryDate.After(now)
+}
+
+// lockStock bloquea la fila de stock hasta el fin de la transacción; la crea si se permite y no existe.
+// La fila se inserta con ON CONFLICT antes de bloquearla, de modo que dos entradas simultáneas del mismo
+// lote esperan una a la otra en lugar de fallar por el índice único.
+func lockStock(tx *gorm.DB, in MovementInput, create bool) (*rModels.MrSparePartStock, error) {
+	if create && in.StockID == nil {
+		candidate := rModels.MrSparePartStock{
+			WarehouseAssetID: in.WarehouseAssetID,
+			ProductID:        in.ProductID,
+			BatchNumber:      in.BatchNumber,
+			ExpiryDate:       in.ExpiryDate,
+			Location:         in.Location,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "warehouse_asset_id"}, {Name: "product_id"}, {Name: "batch_number"}},
+			DoNothing: true,
+		}).Omit("Product", "WarehouseAsset", "AssetMovements").Create(&candidate).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var stock rModels.MrSparePartStock
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	if in.StockID != nil {
+		query = query.Where("id = ?", *in.StockID)
+	} else {
+		query = query.Where("warehouse_asset_id = ? AND product_id = ? AND batch_number = ?",
+			in.WarehouseAssetID, in.ProductID, in.BatchNumber)
+	}
+	if err := query.First(&stock).Error; err != nil {
+		return nil, err
+	}
+
+	if create && in.ExpiryDate != nil && stock.ExpiryDate != nil && !in.ExpiryDate.Equal(*stock.ExpiryDate) {
+		return nil, fmt.Errorf("%w: batch %s already expires on %s", ErrInvalidMovement, stock.BatchNumber, stock.ExpiryDate.Format("2006-01-02"))
+	}
+	return &stock, nil
+}
+
+// applyMovement actualiza el stock bloqueado y escribe el movimiento con el stock anterior y posterior.
+// Las entradas recalculan el coste medio ponderado; las salidas valoran al coste medio actual.
+func applyMovement(tx *gorm.DB, stock *rModels.MrSparePartStock, movementType rModels.AssetMovementType, delta float64, unitCost *float64, in MovementInput) (*rModels.MrAssetRegisterMovement, error) {
+	previous := stock.Quantity
+	newStock := previous + delta
+	if newStock < -stockEpsilon {
+		return nil, fmt.Errorf("%w: stock %d has %.6f, cannot remove %.6f", ErrInsufficientStock, stock.ID, previous, -delta)
+	}
+	if math.Abs(newStock) < stockEpsilon {
+		newStock = 0
+	}
+
+	cost := stock.UnitCost
+	if delta > 0 && unitCost != nil {
+		cost = *unitCost
+		if previous > 0 && newStock > 0 {
+			stock.UnitCost = (previous*stock.UnitCost + delta*cost) / newStock
+		} else {
+			stock.UnitCost = cost
+		}
+	}
+
+	stock.Quantity = newStock
+	stock.TotalValue = newStock * stock.UnitCost
+	if in.Location != "" && stock.Location == "" {
+		stock.Location = in.Location
+	}
+	if in.ExpiryDate != nil && stock.ExpiryDate == nil {
+		stock.ExpiryDate = in.ExpiryDate
+	}
+
+	if err := tx.Model(stock).
+		Select("Quantity", "UnitCost", "TotalValue", "Location", "ExpiryDate", "UpdatedAt").
+		Updates(stock).Error; err != nil {
+		return nil, err
+	}
+
+	movement := &rModels.MrAssetRegisterMovement{
+		StockID:         stock.ID,
+		MovementType:    movementType,
+		MovementDate:    time.Now(),
+		Quantity:        delta,
+		UnitCost:        cost,
+		TotalCost:       delta * cost,
+		PreviousStock:   previous,
+		NewStock:        newStock,
+		WorkOrderID:     in.WorkOrderID,
+		PurchaseOrderID: in.PurchaseOrderID,
+		AssetID:         in.AssetID,
+		ReferenceNumber: in.ReferenceNumber,
+		Reason:          in.Reason,
+		CreatedBy:       in.CreatedBy,
+	}
+	if err := tx.Omit("Stock", "WorkOrder", "PurchaseOrder", "Asset", "Creator").Create(movement).Error; err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// createWorkOrderSparePart refleja en la OT la salida (cantidad positiva) o devolución (negativa) de material
+func createWorkOrderSparePart(tx *gorm.DB, stock *rModels.MrSparePartStock, movement *rModels.MrAssetRegisterMovement, quantity float64, assetID *uint) error {
+	stockID := stock.ID
+	movementID := movement.ID
+	sparePart := &rModels.MrMaintWorkOrderSparePart{
+		WorkOrderID:      *movement.WorkOrderID,
+		ProductID:        stock.ProductID,
+		StockID:          &stockID,
+		SparePartAssetID: assetID,
+		Quantity:         quantity,
+		UnitCost:         movement.UnitCost,
+		TotalCost:        quantity * movement.UnitCost,
+		AssetMovementID:  &movementID,
+	}
+	return tx.Omit("WorkOrder", "Product", "Stock", "SparePartAsset", "AssetMovement").Create(sparePart).Error
+}
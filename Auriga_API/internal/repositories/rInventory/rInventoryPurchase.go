@@ -0,0 +1,193 @@
+package rInventory
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) GetPurchaseOrders(filter PurchaseOrderFilter) ([]rModels.MrPurchaseOrder, error) {
+	var orders []rModels.MrPurchaseOrder
+
+	query := r.db.Preload("WarehouseAsset").Preload("OrderItems.Product")
+	if filter.WarehouseAssetID != 0 {
+		query = query.Where("warehouse_asset_id = ?", filter.WarehouseAssetID)
+	}
+	if len(filter.Status) > 0 {
+		query = query.Where("status IN ?", filter.Status)
+	}
+	if filter.Supplier != "" {
+		query = query.Where("supplier ILIKE ?", "%"+filter.Supplier+"%")
+	}
+	if filter.AutoGenerated != nil {
+		query = query.Where("auto_generated = ?", *filter.AutoGenerated)
+	}
+
+	err := query.Order("order_date DESC, id DESC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *repository) GetPurchaseOrderByID(id uint) (*rModels.MrPurchaseOrder, error) {
+	var order rModels.MrPurchaseOrder
+	err := r.db.Preload("WarehouseAsset").Preload("Creator").Preload("Approver").
+		Preload("OrderItems.Product").
+		Preload("AssetMovements", func(db *gorm.DB) *gorm.DB {
+			return db.Order("movement_date, id")
+		}).
+		First(&order, id).Error
+	return &order, err
+}
+
+func (r *repository) CreatePurchaseOrder(order *rModels.MrPurchaseOrder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		items := order.OrderItems
+		order.OrderItems = nil
+		if err := tx.Omit("WarehouseAsset", "Creator", "Approver", "OrderItems", "AssetMovements").Create(order).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].PurchaseOrderID = order.ID
+		}
+		if len(items) > 0 {
+			if err := tx.Omit("PurchaseOrder", "Product").Create(&items).Error; err != nil {
+				return err
+			}
+		}
+		order.OrderItems = items
+		return nil
+	})
+}
+
+// TransitionPurchaseOrder cambia el estado solo si la orden sigue en alguno de los estados de origen.
+// Devuelve false si otro proceso la modificó antes.
+func (r *repository) TransitionPurchaseOrder(id uint, from []rModels.PurchaseOrderStatus, to rModels.PurchaseOrderStatus, fields map[string]interface{}) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	for column, value := range fields {
+		updates[column] = value
+	}
+
+	result := r.db.Model(&rModels.MrPurchaseOrder{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CancelPurchaseOrder anula la orden y deja como importe solo lo ya recibido
+func (r *repository) CancelPurchaseOrder(id uint, from []rModels.PurchaseOrderStatus, reason string, cancelledAt time.Time) (bool, error) {
+	return r.TransitionPurchaseOrder(id, from, rModels.PurchaseOrderCancelled, map[string]interface{}{
+		"cancelled_at":  cancelledAt,
+		"cancel_reason": reason,
+		"total_amount": gorm.Expr(`(SELECT COALESCE(ROUND(SUM(COALESCE(received_quantity, 0) * unit_price), 2), 0)
+			FROM mr_purchase_order_items WHERE purchase_order_id = ?)`, id),
+	})
+}
+
+// ReceivePurchaseOrder registra una entrada de mercancía contra la orden: cada línea genera un movimiento
+// de compra en el almacén de la orden, acumula ReceivedQuantity y el estado pasa a recibida
+// parcial o totalmente. Todo ocurre en una única transacción.
+func (r *repository) ReceivePurchaseOrder(id uint, lines []PurchaseReceiptLine, in MovementInput) ([]rModels.MrAssetRegisterMovement, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: receipt has no lines", ErrInvalidMovement)
+	}
+
+	var movements []rModels.MrAssetRegisterMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var order rModels.MrPurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
+			return err
+		}
+		if order.Status != rModels.PurchaseOrderOrdered && order.Status != rModels.PurchaseOrderPartial {
+			return fmt.Errorf("%w: purchase order %s is %s", ErrInvalidOrderStatus, order.OrderNumber, order.Status)
+		}
+
+		var items []rModels.MrPurchaseOrderItem
+		if err := tx.Where("purchase_order_id = ?", order.ID).Order("id").Find(&items).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*rModels.MrPurchaseOrderItem, len(items))
+		for i := range items {
+			byID[items[i].ID] = &items[i]
+		}
+
+		orderID := order.ID
+		for _, line := range lines {
+			item, ok := byID[line.ItemID]
+			if !ok {
+				return fmt.Errorf("%w: item %d does not belong to purchase order %s", ErrInvalidMovement, line.ItemID, order.OrderNumber)
+			}
+			if line.Quantity <= 0 {
+				return fmt.Errorf("%w: received quantity must be positive", ErrInvalidMovement)
+			}
+			pending := item.Quantity - item.ReceivedQuantity
+			if line.Quantity > pending+stockEpsilon {
+				return fmt.Errorf("%w: item %d has %.6f pending, cannot receive %.6f", ErrInvalidMovement, item.ID, pending, line.Quantity)
+			}
+			if line.UnitPrice != nil {
+				item.UnitPrice = *line.UnitPrice
+			}
+
+			lineInput := in
+			lineInput.StockID = nil
+			lineInput.WarehouseAssetID = order.WarehouseAssetID
+			lineInput.ProductID = item.ProductID
+			lineInput.BatchNumber = line.BatchNumber
+			lineInput.ExpiryDate = line.ExpiryDate
+			lineInput.Location = line.Location
+			lineInput.Quantity = line.Quantity
+			lineInput.PurchaseOrderID = &orderID
+			if lineInput.ReferenceNumber == "" {
+				lineInput.ReferenceNumber = order.OrderNumber
+			}
+
+			stock, err := lockStock(tx, lineInput, true)
+			if err != nil {
+				return err
+			}
+			unitPrice := item.UnitPrice
+			movement, err := applyMovement(tx, stock, rModels.AssetMovementTypePurchase, line.Quantity, &unitPrice, lineInput)
+			if err != nil {
+				return err
+			}
+			movements = append(movements, *movement)
+
+			item.ReceivedQuantity += line.Quantity
+			item.TotalPrice = RoundAmount(item.Quantity * item.UnitPrice)
+			if err := tx.Model(item).
+				Select("ReceivedQuantity", "UnitPrice", "TotalPrice").
+				Updates(item).Error; err != nil {
+				return err
+			}
+		}
+
+		complete := true
+		order.TotalAmount = 0
+		for _, item := range items {
+			if item.ReceivedQuantity < item.Quantity-stockEpsilon {
+				complete = false
+			}
+			order.TotalAmount += item.TotalPrice
+		}
+		order.TotalAmount = RoundAmount(order.TotalAmount)
+
+		order.Status = rModels.PurchaseOrderPartial
+		if complete {
+			now := time.Now()
+			order.Status = rModels.PurchaseOrderReceived
+			order.ReceivedDate = &now
+		}
+
+		return tx.Model(&order).
+			Select("Status", "ReceivedDate", "TotalAmount", "UpdatedAt").
+			Updates(&order).Error
+	})
+	return movements, err
+}
+
+// RoundAmount redondea importes a céntimos
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,142 @@
+package rInventory
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// reorderLockKey clave del advisory lock que serializa la generación de propuestas
+const reorderLockKey = 7302
+
+// openPurchaseOrderStatuses son los estados cuyas cantidades pendientes cuentan como ya pedidas
+var openPurchaseOrderStatuses = []rModels.PurchaseOrderStatus{
+	rModels.PurchaseOrderDraft,
+	rModels.PurchaseOrderApproved,
+	rModels.PurchaseOrderOrdered,
+	rModels.PurchaseOrderPartial,
+}
+
+// openWorkOrderStatuses son los estados de OT cuyas reservas aún consumirán stock
+var openWorkOrderStatuses = []rModels.WorkOrderStatus{
+	rModels.WorkOrderRequested,
+	rModels.WorkOrderPlanned,
+	rModels.WorkOrderInProgress,
+	rModels.WorkOrderOnHold,
+}
+
+// GetReorderLines devuelve la situación de stock por almacén y producto con MinStock definido.
+// Los lotes caducados no cuentan como existencias ni en el coste medio; sin existencias válidas se toma el
+// mayor coste unitario registrado.
+// Las reservas cuentan en el almacén del stock contra el que se hicieron.
+func (r *repository) GetReorderLines() ([]ReorderLine, error) {
+	var lines []ReorderLine
+	err := r.db.Raw(`
+		WITH stock AS (
+			SELECT warehouse_asset_id, product_id,
+				COALESCE(SUM(quantity) FILTER (WHERE expiry_date IS NULL OR expiry_date > NOW()), 0) AS on_hand,
+				MAX(min_stock) AS min_stock,
+				MAX(max_stock) AS max_stock,
+				CASE WHEN SUM(quantity) FILTER (WHERE expiry_date IS NULL OR expiry_date > NOW()) > 0
+					THEN SUM(total_value) FILTER (WHERE expiry_date IS NULL OR expiry_date > NOW()) /
+						SUM(quantity) FILTER (WHERE expiry_date IS NULL OR expiry_date > NOW())
+					ELSE MAX(unit_cost) END AS unit_cost
+			FROM mr_spare_part_stocks
+			GROUP BY warehouse_asset_id, product_id
+			HAVING MAX(min_stock) > 0
+		), on_order AS (
+			SELECT o.warehouse_asset_id, i.product_id,
+				SUM(GREATEST(i.quantity - COALESCE(i.received_quantity, 0), 0)) AS quantity
+			FROM mr_purchase_order_items i
+			JOIN mr_purchase_orders o ON o.id = i.purchase_order_id
+			WHERE o.status IN ?
+			GROUP BY o.warehouse_asset_id, i.product_id
+		), reserved AS (
+			SELECT st.warehouse_asset_id, r.product_id, SUM(r.quantity) AS quantity
+			FROM mr_maint_spare_part_reservations r
+			JOIN mr_maint_work_orders w ON w.id = r.work_order_id
+			JOIN mr_spare_part_stocks st ON st.id = r.stock_id
+			WHERE r.status = ? AND w.status IN ?
+			GROUP BY st.warehouse_asset_id, r.product_id
+		), last_supplier AS (
+			SELECT DISTINCT ON (i.product_id) i.product_id, o.supplier, i.unit_price
+			FROM mr_purchase_order_items i
+			JOIN mr_purchase_orders o ON o.id = i.purchase_order_id
+			WHERE o.status <> ?
+			ORDER BY i.product_id, o.order_date DESC, o.id DESC
+		)
+		SELECT s.warehouse_asset_id, s.product_id, p.name AS product_name,
+			s.on_hand, s.min_stock, s.max_stock, COALESCE(s.unit_cost, 0) AS unit_cost,
+			COALESCE(oo.quantity, 0) AS on_order,
+			COALESCE(rs.quantity, 0) AS reserved,
+			COALESCE(NULLIF(ls.supplier, ''), p.manufacturer) AS supplier,
+			COALESCE(ls.unit_price, 0) AS last_unit_price
+		FROM stock s
+		JOIN mr_products p ON p.id = s.product_id
+		LEFT JOIN on_order oo ON oo.warehouse_asset_id = s.warehouse_asset_id AND oo.product_id = s.product_id
+		LEFT JOIN reserved rs ON rs.warehouse_asset_id = s.warehouse_asset_id AND rs.product_id = s.product_id
+		LEFT JOIN last_supplier ls ON ls.product_id = s.product_id
+		ORDER BY s.warehouse_asset_id, s.product_id`,
+		openPurchaseOrderStatuses,
+		rModels.ReservationReserved, openWorkOrderStatuses,
+		rModels.PurchaseOrderCancelled,
+	).Scan(&lines).Error
+	return lines, err
+}
+
+// CreateReorderPurchaseOrders crea las propuestas en borrador. Bajo el advisory lock descarta las líneas
+// de productos que ya tienen una propuesta automática pendiente en el mismo almacén, de modo que
+// dos ejecuciones simultáneas no dupliquen pedidos.
+func (r *repository) CreateReorderPurchaseOrders(orders []*rModels.MrPurchaseOrder) ([]rModels.MrPurchaseOrder, error) {
+	var createdIDs []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", reorderLockKey).Error; err != nil {
+			return err
+		}
+
+		for _, order := range orders {
+			items := make([]rModels.MrPurchaseOrderItem, 0, len(order.OrderItems))
+			for _, item := range order.OrderItems {
+				var pending int64
+				err := tx.Model(&rModels.MrPurchaseOrderItem{}).
+					Joins("JOIN mr_purchase_orders ON mr_purchase_orders.id = mr_purchase_order_items.purchase_order_id").
+					Where("mr_purchase_orders.warehouse_asset_id = ? AND mr_purchase_order_items.product_id = ?", order.WarehouseAssetID, item.ProductID).
+					Where("mr_purchase_orders.status = ? AND mr_purchase_orders.auto_generated", rModels.PurchaseOrderDraft).
+					Count(&pending).Error
+				if err != nil {
+					return err
+				}
+				if pending == 0 {
+					items = append(items, item)
+				}
+			}
+			if len(items) == 0 {
+				continue
+			}
+
+			order.OrderItems = nil
+			order.TotalAmount = 0
+			for _, item := range items {
+				order.TotalAmount += item.TotalPrice
+			}
+			if err := tx.Omit("WarehouseAsset", "Creator", "Approver", "OrderItems", "AssetMovements").Create(order).Error; err != nil {
+				return err
+			}
+			for i := range items {
+				items[i].PurchaseOrderID = order.ID
+			}
+			if err := tx.Omit("PurchaseOrder", "Product").Create(&items).Error; err != nil {
+				return err
+			}
+			createdIDs = append(createdIDs, order.ID)
+		}
+		return nil
+	})
+	if err != nil || len(createdIDs) == 0 {
+		return nil, err
+	}
+
+	var created []rModels.MrPurchaseOrder
+	err = r.db.Preload("WarehouseAsset").Preload("OrderItems.Product").
+		Where("id IN ?", createdIDs).Order("id").Find(&created).Error
+	return created, err
+}
@@ -0,0 +1,72 @@
+package rInventory
+
+import (
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+func (r *repository) GetStocks(filter StockFilter) ([]rModels.MrSparePartStock, error) {
+	var stocks []rModels.MrSparePartStock
+
+	query := r.db.Preload("Product").Preload("WarehouseAsset")
+	if filter.WarehouseAssetID != 0 {
+		query = query.Where("warehouse_asset_id = ?", filter.WarehouseAssetID)
+	}
+	if filter.ProductID != 0 {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+
+	err := query.Order("warehouse_asset_id, product_id, batch_number").Find(&stocks).Error
+	return stocks, err
+}
+
+func (r *repository) GetStockByID(id uint) (*rModels.MrSparePartStock, error) {
+	var stock rModels.MrSparePartStock
+	err := r.db.Preload("Product").Preload("WarehouseAsset").First(&stock, id).Error
+	return &stock, err
+}
+
+// GetExpiringStocks devuelve los lotes con existencias que caducan antes de until, incluidos los ya caducados
+func (r *repository) GetExpiringStocks(warehouseAssetID uint, until time.Time) ([]rModels.MrSparePartStock, error) {
+	var stocks []rModels.MrSparePartStock
+
+	query := r.db.Preload("Product").Preload("WarehouseAsset").
+		Where("quantity > 0 AND expiry_date IS NOT NULL AND expiry_date <= ?", until)
+	if warehouseAssetID != 0 {
+		query = query.Where("warehouse_asset_id = ?", warehouseAssetID)
+	}
+
+	err := query.Order("warehouse_asset_id, expiry_date, product_id").Find(&stocks).Error
+	return stocks, err
+}
+
+func (r *repository) GetMovements(filter MovementFilter) ([]rModels.MrAssetRegisterMovement, error) {
+	var movements []rModels.MrAssetRegisterMovement
+
+	query := r.db.Preload("Stock.Product").Preload("Stock.WarehouseAsset").Preload("Creator").
+		Joins("JOIN mr_spare_part_stocks ON mr_spare_part_stocks.id = mr_asset_register_movements.stock_id")
+
+	if filter.ProductID != 0 {
+		query = query.Where("mr_spare_part_stocks.product_id = ?", filter.ProductID)
+	}
+	if filter.WarehouseAssetID != 0 {
+		query = query.Where("mr_spare_part_stocks.warehouse_asset_id = ?", filter.WarehouseAssetID)
+	}
+	if filter.WorkOrderID != 0 {
+		query = query.Where("mr_asset_register_movements.work_order_id = ?", filter.WorkOrderID)
+	}
+	if filter.StockID != 0 {
+		query = query.Where("mr_asset_register_movements.stock_id = ?", filter.StockID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("mr_asset_register_movements.movement_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("mr_asset_register_movements.movement_date <= ?", filter.To)
+	}
+
+	err := query.Order("mr_asset_register_movements.movement_date DESC, mr_asset_register_movements.id DESC").
+		Find(&movements).Error
+	return movements, err
+}
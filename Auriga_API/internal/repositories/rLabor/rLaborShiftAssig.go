@@ -50,6 +50,7 @@ func (r *repository) GetCurrentAssignment(employeeID uint, date time.Time) (*rMo
 	err := r.db.Preload("Shift").Preload("Employee").
 		Where("employee_id = ? AND active = ? AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)",
 			employeeID, true, date, date).
+		Order("start_date DESC").
 		First(&assignment).Error
 	return &assignment, err
 }
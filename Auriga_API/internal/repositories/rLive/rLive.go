@@ -0,0 +1,92 @@
+package rLive
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/remrafvil/Auriga_API/config"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// channel canal de PostgreSQL por el que las instancias de la API se reenvían los mensajes en vivo
+const channel = "auriga_live"
+
+// MaxPayload tamaño máximo de un mensaje; PostgreSQL rechaza las notificaciones de 8000 bytes o más
+const MaxPayload = 7900
+
+var ErrPayloadTooLarge = errors.New("live message too large for a notification")
+
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	// pingInterval comprueba la conexión cuando no llegan notificaciones
+	pingInterval = 90 * time.Second
+)
+
+type Repository interface {
+	Notify(payload []byte) error
+	Listen(ctx context.Context, handle func(payload []byte), connected func(bool)) error
+}
+
+type repository struct {
+	db     *gorm.DB
+	config *config.Settings
+	logger *zap.Logger
+}
+
+func New(db *gorm.DB, cfg *config.Settings, logger *zap.Logger) Repository {
+	return &repository{
+		db:     db,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// Notify envía el mensaje a todas las instancias que escuchan el canal, incluida esta
+func (r *repository) Notify(payload []byte) error {
+	if len(payload) > MaxPayload {
+		return ErrPayloadTooLarge
+	}
+	return r.db.Exec("SELECT pg_notify(?, ?)", channel, string(payload)).Error
+}
+
+// Listen entrega a handle los mensajes del canal hasta que se cancela el contexto. La conexión es propia
+// y se restablece sola; connected informa de cada cambio para que mientras tanto se entregue en local.
+func (r *repository) Listen(ctx context.Context, handle func(payload []byte), connected func(bool)) error {
+	listener := pq.NewListener(r.config.DB.DSN(), minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventConnected, pq.ListenerEventReconnected:
+			connected(true)
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			connected(false)
+			r.logger.Warn("Live notification listener disconnected", zap.Error(err))
+		}
+	})
+	defer func() {
+		connected(false)
+		listener.Close()
+	}()
+
+	if err := listener.Listen(channel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// Tras una reconexión llega una notificación vacía; lo publicado mientras tanto se ha perdido
+			if notification != nil {
+				handle([]byte(notification.Extra))
+			}
+		case <-time.After(pingInterval):
+			if err := listener.Ping(); err != nil {
+				r.logger.Debug("Live notification listener ping failed", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package rMaintenance
+
+import (
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// workOrderCostSQL recalcula horas y costes de las OT seleccionadas. La mano de obra usa la tarifa
+// del empleado, después la del rol de la asignación y por último la tarifa por defecto.
+const workOrderCostSQL = `
+	UPDATE mr_maint_work_orders w SET
+		actual_hours = c.hours,
+		labor_cost = c.labor,
+		parts_cost = c.parts,
+		cost = c.labor + c.parts,
+		updated_at = NOW()
+	FROM (
+		SELECT wo.id,
+			COALESCE((SELECT SUM(a.hours_worked) FROM mr_maint_work_order_assignments a WHERE a.work_order_id = wo.id), 0) AS hours,
+			COALESCE((SELECT ROUND(SUM(a.hours_worked * COALESCE(er.hourly_rate, rr.hourly_rate, ?)), 2)
+				FROM mr_maint_work_order_assignments a
+				LEFT JOIN mr_maint_labor_rates er ON er.employee_id = a.employee_id
+				LEFT JOIN mr_maint_labor_rates rr ON rr.employee_id IS NULL AND rr.role = a.role
+				WHERE a.work_order_id = wo.id), 0) AS labor,
+			COALESCE((SELECT ROUND(SUM(p.total_cost), 2) FROM mr_maint_work_order_spare_parts p WHERE p.work_order_id = wo.id), 0) AS parts
+		FROM mr_maint_work_orders wo
+		WHERE `
+
+// RecalculateWorkOrderCost actualiza ActualHours, LaborCost, PartsCost y Cost de una OT. Se ejecuta
+// en la transacción que cambia asignaciones, tramos o repuestos para que el coste nunca quede desfasado.
+func RecalculateWorkOrderCost(tx *gorm.DB, workOrderID uint, defaultRate float64) error {
+	return tx.Exec(workOrderCostSQL+`wo.id = ?
+	) c WHERE w.id = c.id`, defaultRate, workOrderID).Error
+}
+
+// recalculateOpenWorkOrderCosts recalcula las OT no finalizadas tras un cambio de tarifas
+func recalculateOpenWorkOrderCosts(tx *gorm.DB, defaultRate float64) error {
+	return tx.Exec(workOrderCostSQL+`wo.status NOT IN ?
+	) c WHERE w.id = c.id`, defaultRate, rModels.FinalWorkOrderStatuses).Error
+}
+
+// GetCostWorkOrders devuelve las OT no canceladas del subárbol cuya fecha de coste cae en la ventana.
+// La fecha de coste es la de finalización o, si sigue abierta, la de creación.
+func (r *repository) GetCostWorkOrders(rootID uint, from time.Time, to time.Time) ([]rModels.MrMaintWorkOrder, error) {
+	var workOrders []rModels.MrMaintWorkOrder
+	err := r.db.
+		Select("id", "asset_id", "work_order_type", "priority", "status", "created_at", "end_date", "actual_hours", "labor_cost", "parts_cost", "cost").
+		Where("asset_id IN ("+assetSubtreeSQL+")", rootID).
+		Where("status <> ?", rModels.WorkOrderCancelled).
+		Where("COALESCE(end_date, created_at) BETWEEN ? AND ?", from, to).
+		Find(&workOrders).Error
+	return workOrders, err
+}
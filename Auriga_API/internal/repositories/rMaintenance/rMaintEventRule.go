@@ -0,0 +1,157 @@
+package rMaintenance
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// eventWorkOrderLockKey serializa la creación de OT por eventos de un mismo activo
+const eventWorkOrderLockKey = 7303
+
+func (r *repository) GetEventRules() ([]rModels.MrMaintEventRule, error) {
+	var rules []rModels.MrMaintEventRule
+	err := r.db.Preload("EventCategory").Preload("EventType").Preload("AssignedTeam").
+		Order("id").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *repository) GetEventRuleByID(id uint) (*rModels.MrMaintEventRule, error) {
+	var rule rModels.MrMaintEventRule
+	err := r.db.Preload("EventCategory").Preload("EventType").Preload("AssignedTeam").
+		First(&rule, id).Error
+	return &rule, err
+}
+
+// GetActiveEventRules devuelve las reglas activas de la categoría; primero las de un tipo concreto
+func (r *repository) GetActiveEventRules(categoryName string) ([]rModels.MrMaintEventRule, error) {
+	var rules []rModels.MrMaintEventRule
+	err := r.db.Preload("EventCategory").Preload("EventType").
+		Where("active = ?", true).
+		Where("event_category_id IN (SELECT id FROM mr_event_categories WHERE name = ?)", categoryName).
+		Order("event_type_id NULLS LAST, id").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *repository) CreateEventRule(rule *rModels.MrMaintEventRule) error {
+	return r.db.Omit("EventCategory", "EventType", "AssignedTeam").Create(rule).Error
+}
+
+func (r *repository) UpdateEventRule(rule *rModels.MrMaintEventRule) error {
+	return r.db.Omit("EventCategory", "EventType", "AssignedTeam").Save(rule).Error
+}
+
+func (r *repository) DeleteEventRule(id uint) error {
+	return r.db.Delete(&rModels.MrMaintEventRule{}, id).Error
+}
+
+func (r *repository) GetEventCategory(id uint) (*rModels.MrEventCategory, error) {
+	var category rModels.MrEventCategory
+	err := r.db.First(&category, id).Error
+	return &category, err
+}
+
+func (r *repository) GetEventType(id uint) (*rModels.MrEventType, error) {
+	var eventType rModels.MrEventType
+	err := r.db.First(&eventType, id).Error
+	return &eventType, err
+}
+
+func (r *repository) GetCommitEvent(id uint) (*rModels.MrCommitEvents, error) {
+	var event rModels.MrCommitEvents
+	err := r.db.First(&event, id).Error
+	return &event, err
+}
+
+// FindAssetByPath devuelve el activo cuyo hierarchical_level coincide con el prefijo más largo de la ruta
+func (r *repository) FindAssetByPath(path []string) (*rModels.MrAsset, error) {
+	for n := len(path); n > 0; n-- {
+		var asset rModels.MrAsset
+		err := r.db.Where("hierarchical_level = ?", pq.StringArray(path[:n])).First(&asset).Error
+		if err == nil {
+			return &asset, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// CreateEventWorkOrder vincula el evento a la OT abierta de la misma regla y activo o, si no hay
+// ninguna, crea la OT con su histórico. Devuelve la OT vinculada y si se ha creado. Si el evento se ha
+// vinculado o modificado desde que se leyó devuelve ErrEventChanged y no crea nada.
+func (r *repository) CreateEventWorkOrder(workOrder *rModels.MrMaintWorkOrder, history *rModels.MrMaintWorkOrderStatusHistory, event *rModels.MrCommitEvents, openStatuses []rModels.WorkOrderStatus) (uint, bool, error) {
+	var workOrderID uint
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", eventWorkOrderLockKey, workOrder.AssetID).Error; err != nil {
+			return err
+		}
+
+		var open rModels.MrMaintWorkOrder
+		err := tx.Select("id").
+			Where("event_rule_id = ? AND asset_id = ? AND status IN ?", *workOrder.EventRuleID, workOrder.AssetID, openStatuses).
+			Order("id DESC").
+			First(&open).Error
+		switch {
+		case err == nil:
+			workOrderID = open.ID
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Omit("Asset", "Creator", "AssignedTeam", "MaintenancePlan").Create(workOrder).Error; err != nil {
+				return err
+			}
+			history.WorkOrderID = workOrder.ID
+			if err := tx.Omit("WorkOrder", "Employee").Create(history).Error; err != nil {
+				return err
+			}
+			workOrderID = workOrder.ID
+			created = true
+		default:
+			return err
+		}
+
+		// Solo se vincula si nadie lo ha hecho ya ni ha reclasificado el evento; vincular no cuenta como
+		// modificación, así que no cambia updated_at
+		result := tx.Model(&rModels.MrCommitEvents{}).
+			Where("id = ? AND work_order_id IS NULL AND updated_at = ?", event.ID, event.UpdatedAt).
+			UpdateColumn("work_order_id", workOrderID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEventChanged
+		}
+		return nil
+	})
+	return workOrderID, created, err
+}
+
+// GetPendingStopEvents devuelve los eventos confirmados a los que aún no se han aplicado las reglas
+func (r *repository) GetPendingStopEvents(limit int) ([]rModels.MrCommitEvents, error) {
+	var events []rModels.MrCommitEvents
+	err := r.db.Where("maint_processed_at IS NULL").
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// RecordStopEventResult guarda el resultado de aplicar las reglas: processed saca el evento de la cola y,
+// si no, se cuenta el intento fallido. Si el evento se ha modificado desde que se leyó no se toca y
+// sigue pendiente con su nueva clasificación.
+func (r *repository) RecordStopEventResult(event *rModels.MrCommitEvents, processed bool, errMsg string) error {
+	updates := map[string]interface{}{"maint_error": errMsg}
+	if processed {
+		updates["maint_processed_at"] = gorm.Expr("NOW()")
+	} else {
+		updates["maint_attempts"] = gorm.Expr("maint_attempts + 1")
+	}
+	return r.db.Model(&rModels.MrCommitEvents{}).
+		Where("id = ? AND updated_at = ?", event.ID, event.UpdatedAt).
+		UpdateColumns(updates).Error
+}
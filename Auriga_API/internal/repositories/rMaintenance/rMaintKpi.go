@@ -0,0 +1,83 @@
+package rMaintenance
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+// AssetNode activo del subárbol con lo necesario para agregar indicadores
+type AssetNode struct {
+	ID                uint
+	ParentID          *uint
+	Code              string
+	HierarchicalLevel pq.StringArray `gorm:"type:text[]"`
+}
+
+// GetAssetSubtree devuelve el activo indicado y todos sus descendientes
+func (r *repository) GetAssetSubtree(rootID uint) ([]AssetNode, error) {
+	var nodes []AssetNode
+	err := r.db.Raw(`
+		SELECT id, parent_id, code, hierarchical_level FROM mr_assets
+		WHERE id IN (`+assetSubtreeSQL+`)
+		ORDER BY id`, rootID).
+		Scan(&nodes).Error
+	return nodes, err
+}
+
+// KpiWorkOrder OT con la hora del fallo: la de la primera parada vinculada, la de la medición que la
+// disparó o, en su defecto, la de creación
+type KpiWorkOrder struct {
+	ID            uint
+	AssetID       uint
+	WorkOrderType rModels.WorkOrderType
+	CreatedAt     time.Time
+	EndDate       *time.Time
+	FailureAt     time.Time
+}
+
+// KpiStop parada confirmada con la ruta en la que se produjo
+type KpiStop struct {
+	EventTime time.Time
+	EndTime   *time.Time
+	Factory   string
+	ProdLine  string
+	System    string
+	Machine   string
+	Part      string
+}
+
+// GetKpiWorkOrders devuelve las OT no canceladas del subárbol que estaban abiertas en algún momento de la ventana
+func (r *repository) GetKpiWorkOrders(rootID uint, from time.Time, to time.Time) ([]KpiWorkOrder, error) {
+	var workOrders []KpiWorkOrder
+	err := r.db.Raw(`
+		SELECT w.id, w.asset_id, w.work_order_type, w.created_at, w.end_date,
+			COALESCE(
+				(SELECT MIN(e.event_time) FROM mr_commit_events e WHERE e.work_order_id = w.id AND e.deleted_at IS NULL),
+				w.triggered_at,
+				w.created_at
+			) AS failure_at
+		FROM mr_maint_work_orders w
+		WHERE w.asset_id IN (`+assetSubtreeSQL+`)
+			AND w.status <> ? AND w.created_at <= ? AND (w.end_date IS NULL OR w.end_date >= ?)
+		ORDER BY w.created_at`,
+		rootID, rModels.WorkOrderCancelled, to, from,
+	).Scan(&workOrders).Error
+	return workOrders, err
+}
+
+// GetKpiStops devuelve las paradas confirmadas bajo la ruta indicada que se solapan con la ventana
+func (r *repository) GetKpiStops(path []string, from time.Time, to time.Time) ([]KpiStop, error) {
+	var stops []KpiStop
+	query := r.db.Model(&rModels.MrCommitEvents{}).
+		Select("event_time, end_time, factory, prod_line, system, machine, part").
+		Where("event_time < ? AND (end_time IS NULL OR end_time > ?)", to, from)
+	for i, column := range []string{"factory", "prod_line", "system", "machine", "part"} {
+		if i < len(path) {
+			query = query.Where(column+" = ?", path[i])
+		}
+	}
+	err := query.Order("event_time").Scan(&stops).Error
+	return stops, err
+}
@@ -0,0 +1,49 @@
+package rMaintenance
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+func (r *repository) GetLaborRates() ([]rModels.MrMaintLaborRate, error) {
+	var rates []rModels.MrMaintLaborRate
+	err := r.db.Preload("Employee").
+		Order("employee_id NULLS FIRST, role").
+		Find(&rates).Error
+	return rates, err
+}
+
+func (r *repository) GetLaborRateByID(id uint) (*rModels.MrMaintLaborRate, error) {
+	var rate rModels.MrMaintLaborRate
+	err := r.db.Preload("Employee").First(&rate, id).Error
+	return &rate, err
+}
+
+// CreateLaborRate, UpdateLaborRate y DeleteLaborRate aplican la nueva tarifa a las OT no finalizadas
+// en la misma transacción; las finalizadas conservan su coste
+func (r *repository) CreateLaborRate(rate *rModels.MrMaintLaborRate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Employee").Create(rate).Error; err != nil {
+			return err
+		}
+		return recalculateOpenWorkOrderCosts(tx, r.config.Maintenance.DefaultLaborRate)
+	})
+}
+
+func (r *repository) UpdateLaborRate(rate *rModels.MrMaintLaborRate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Employee").Save(rate).Error; err != nil {
+			return err
+		}
+		return recalculateOpenWorkOrderCosts(tx, r.config.Maintenance.DefaultLaborRate)
+	})
+}
+
+func (r *repository) DeleteLaborRate(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&rModels.MrMaintLaborRate{}, id).Error; err != nil {
+			return err
+		}
+		return recalculateOpenWorkOrderCosts(tx, r.config.Maintenance.DefaultLaborRate)
+	})
+}
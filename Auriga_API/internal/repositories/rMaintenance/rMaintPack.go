@@ -0,0 +1,36 @@
+package rMaintenance
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// GetWorkOrderPack carga la orden con todo lo necesario para imprimir su dossier de trabajo
+func (r *repository) GetWorkOrderPack(id uint) (*rModels.MrMaintWorkOrder, error) {
+	var workOrder rModels.MrMaintWorkOrder
+	err := r.db.Preload("Asset").Preload("Asset.Documents").Preload("AssignedTeam").
+		Preload("MaintenancePlan").
+		Preload("MaintenancePlan.Procedures", func(db *gorm.DB) *gorm.DB { return db.Order("step_number") }).
+		Preload("Tasks", func(db *gorm.DB) *gorm.DB { return db.Order("task_number") }).
+		Preload("AssignedMembers.Employee").
+		Preload("UsedSpareParts.Product").
+		Preload("UsedSpareParts.Stock.WarehouseAsset").
+		Preload("Documents").
+		Preload("Reservations.Product").
+		First(&workOrder, id).Error
+	return &workOrder, err
+}
+
+// GetAvailableStocks devuelve los lotes con existencias de los productos, primero los que caducan antes
+func (r *repository) GetAvailableStocks(productIDs []uint) ([]rModels.MrSparePartStock, error) {
+	var stocks []rModels.MrSparePartStock
+	if len(productIDs) == 0 {
+		return stocks, nil
+	}
+	err := r.db.Preload("WarehouseAsset").
+		Where("product_id IN ? AND quantity > 0", productIDs).
+		Where("expiry_date IS NULL OR expiry_date > NOW()").
+		Order("product_id, expiry_date ASC NULLS LAST, id").
+		Find(&stocks).Error
+	return stocks, err
+}
@@ -0,0 +1,187 @@
+package rMaintenance
+
+import (
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// Clave de advisory lock de PostgreSQL para la generación de OT preventivas
+const planLockKey = 7301
+
+// GetDuePlans devuelve los planes activos vencidos con sus procedimientos y repuestos
+func (r *repository) GetDuePlans(now time.Time, frequencyTypes []rModels.FrequencyType) ([]rModels.MrMaintenancePlan, error) {
+	var plans []rModels.MrMaintenancePlan
+	err := r.db.
+		Preload("Procedures", func(db *gorm.DB) *gorm.DB { return db.Order("step_number") }).
+		Preload("RequiredSpareParts").
+		Where("active = ? AND next_scheduled <= ? AND frequency_type IN ?", true, now, frequencyTypes).
+		Order("next_scheduled").
+		Find(&plans).Error
+	return plans, err
+}
+
+// CreatePlanWorkOrder crea la OT de una ocurrencia de plan con sus tareas y reservas.
+// Devuelve false si otra réplica tiene el plan bloqueado o la ocurrencia ya tiene OT.
+func (r *repository) CreatePlanWorkOrder(workOrder *rModels.MrMaintWorkOrder, history *rModels.MrMaintWorkOrderStatusHistory) (bool, error) {
+	created := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// El bloqueo se libera automáticamente al terminar la transacción
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?, ?)", planLockKey, *workOrder.MaintenancePlanID).
+			Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var count int64
+		if err := tx.Model(&rModels.MrMaintWorkOrder{}).
+			Where("maintenance_plan_id = ? AND plan_occurrence = ?", *workOrder.MaintenancePlanID, *workOrder.PlanOccurrence).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		tasks, reservations := workOrder.Tasks, workOrder.Reservations
+		if err := tx.Omit("Asset", "Creator", "AssignedTeam", "MaintenancePlan", "Tasks", "Reservations").
+			Create(workOrder).Error; err != nil {
+			return err
+		}
+
+		for i := range tasks {
+			tasks[i].WorkOrderID = workOrder.ID
+		}
+		if len(tasks) > 0 {
+			if err := tx.Omit("WorkOrder", "Assignee").Create(&tasks).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range reservations {
+			reservations[i].WorkOrderID = workOrder.ID
+			stockID, err := reservationStock(tx, reservations[i].ProductID, reservations[i].Quantity)
+			if err != nil {
+				return err
+			}
+			reservations[i].StockID = stockID
+		}
+		if len(reservations) > 0 {
+			if err := tx.Omit("WorkOrder", "Product").Create(&reservations).Error; err != nil {
+				return err
+			}
+		}
+
+		history.WorkOrderID = workOrder.ID
+		if err := tx.Omit("WorkOrder", "Employee").Create(history).Error; err != nil {
+			return err
+		}
+
+		created = true
+		return nil
+	})
+
+	// El índice único plan+ocurrencia es la última garantía frente a duplicados
+	if IsDuplicateKey(err) {
+		return false, nil
+	}
+
+	return created, err
+}
+
+// reservationStock elige el stock contra el que reservar: lotes sin caducar primero, y entre ellos el
+// primero por caducidad que cubra la cantidad sin contar lo ya reservado; si ninguno la cubre, el que
+// tenga más disponible. Devuelve nil si el producto no tiene stock en ningún almacén.
+func reservationStock(tx *gorm.DB, productID uint, quantity float64) (*uint, error) {
+	var ids []uint
+	err := tx.Raw(`
+		SELECT s.id
+		FROM mr_spare_part_stocks s
+		LEFT JOIN (
+			SELECT stock_id, SUM(quantity) AS quantity
+			FROM mr_maint_spare_part_reservations
+			WHERE status = ? AND stock_id IS NOT NULL
+			GROUP BY stock_id
+		) rs ON rs.stock_id = s.id
+		WHERE s.product_id = ?
+		ORDER BY (s.expiry_date IS NULL OR s.expiry_date > NOW()) DESC,
+			(s.quantity - COALESCE(rs.quantity, 0) >= ?) DESC,
+			s.expiry_date ASC NULLS LAST,
+			s.quantity - COALESCE(rs.quantity, 0) DESC,
+			s.id
+		LIMIT 1`,
+		rModels.ReservationReserved, productID, quantity,
+	).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return &ids[0], nil
+}
+
+// rollPlanForward avanza el siguiente vencimiento solo si el plan sigue en la ocurrencia indicada.
+// lastExecuted nulo conserva la última ejecución (ocurrencia cancelada).
+func rollPlanForward(tx *gorm.DB, planID uint, occurrence time.Time, lastExecuted *time.Time, next time.Time) error {
+	updates := map[string]interface{}{"next_scheduled": next}
+	if lastExecuted != nil {
+		updates["last_executed"] = *lastExecuted
+	}
+
+	return tx.Model(&rModels.MrMaintenancePlan{}).
+		Where("id = ? AND next_scheduled = ?", planID, occurrence).
+		Updates(updates).Error
+}
+
+// releaseReservations libera las reservas pendientes de la OT
+func releaseReservations(tx *gorm.DB, workOrderID uint) error {
+	return tx.Model(&rModels.MrMaintSparePartReservation{}).
+		Where("work_order_id = ? AND status = ?", workOrderID, rModels.ReservationReserved).
+		Update("status", rModels.ReservationReleased).Error
+}
+
+// GetActivePlans devuelve los planes activos de los tipos de frecuencia indicados con su activo
+func (r *repository) GetActivePlans(frequencyTypes []rModels.FrequencyType) ([]rModels.MrMaintenancePlan, error) {
+	var plans []rModels.MrMaintenancePlan
+	err := r.db.Preload("Asset").
+		Preload("Procedures", func(db *gorm.DB) *gorm.DB { return db.Order("step_number") }).
+		Preload("RequiredSpareParts").
+		Where("active = ? AND frequency_type IN ?", true, frequencyTypes).
+		Order("id").
+		Find(&plans).Error
+	return plans, err
+}
+
+func (r *repository) GetPlanByID(id uint) (*rModels.MrMaintenancePlan, error) {
+	var plan rModels.MrMaintenancePlan
+	err := r.db.Preload("Asset").First(&plan, id).Error
+	return &plan, err
+}
+
+// UpdatePlanUsageBaseline fija una nueva lectura de referencia del contador.
+// Solo se aplica si la referencia no ha cambiado desde previousAt (nil si el plan aún no tenía referencia).
+func (r *repository) UpdatePlanUsageBaseline(planID uint, previousAt *time.Time, baseline float64, baselineAt time.Time, lastExecuted *time.Time) error {
+	return updatePlanUsageBaseline(r.db, planID, previousAt, baseline, baselineAt, lastExecuted)
+}
+
+func updatePlanUsageBaseline(tx *gorm.DB, planID uint, previousAt *time.Time, baseline float64, baselineAt time.Time, lastExecuted *time.Time) error {
+	updates := map[string]interface{}{
+		"usage_baseline":    baseline,
+		"usage_baseline_at": baselineAt,
+	}
+	if lastExecuted != nil {
+		updates["last_executed"] = *lastExecuted
+	}
+
+	query := tx.Model(&rModels.MrMaintenancePlan{}).Where("id = ?", planID)
+	if previousAt == nil {
+		query = query.Where("usage_baseline_at IS NULL")
+	} else {
+		query = query.Where("usage_baseline_at = ?", *previousAt)
+	}
+
+	return query.Updates(updates).Error
+}
@@ -0,0 +1,41 @@
+package rMaintenance
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// GetConditionPlans devuelve los planes activos con parámetro y umbral de condición definidos
+func (r *repository) GetConditionPlans() ([]rModels.MrMaintenancePlan, error) {
+	var plans []rModels.MrMaintenancePlan
+	err := r.db.Preload("Asset").Preload("SensorAsset").
+		Preload("Procedures", func(db *gorm.DB) *gorm.DB { return db.Order("step_number") }).
+		Preload("RequiredSpareParts").
+		Where("active = ? AND condition_parameter IS NOT NULL AND condition_parameter <> '' AND threshold_value IS NOT NULL", true).
+		Order("id").
+		Find(&plans).Error
+	return plans, err
+}
+
+// SetPlanConditionLatched cambia el estado de disparo del plan solo si estaba en el estado contrario.
+// Devuelve true si este proceso realizó el cambio, lo que evita disparos duplicados entre réplicas.
+func (r *repository) SetPlanConditionLatched(planID uint, latched bool) (bool, error) {
+	result := r.db.Model(&rModels.MrMaintenancePlan{}).
+		Where("id = ? AND condition_latched = ?", planID, !latched).
+		Update("condition_latched", latched)
+	return result.RowsAffected > 0, result.Error
+}
+
+// HasOpenPlanWorkOrder indica si el plan tiene alguna OT sin terminar
+func (r *repository) HasOpenPlanWorkOrder(planID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&rModels.MrMaintWorkOrder{}).
+		Where("maintenance_plan_id = ? AND status IN ?", planID, []rModels.WorkOrderStatus{
+			rModels.WorkOrderRequested,
+			rModels.WorkOrderPlanned,
+			rModels.WorkOrderInProgress,
+			rModels.WorkOrderOnHold,
+		}).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,100 @@
+package rMaintenance
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+func (r *repository) GetTimeSegmentsByWorkOrder(workOrderID uint) ([]rModels.MrMaintTimeSegment, error) {
+	var segments []rModels.MrMaintTimeSegment
+	err := r.db.Preload("Employee").Preload("Shift").
+		Where("work_order_id = ?", workOrderID).
+		Order("start_time, id").
+		Find(&segments).Error
+	return segments, err
+}
+
+// GetOpenTimeSegment devuelve el tramo en curso del empleado en cualquier orden
+func (r *repository) GetOpenTimeSegment(employeeID uint) (*rModels.MrMaintTimeSegment, error) {
+	var segment rModels.MrMaintTimeSegment
+	err := r.db.Where("employee_id = ? AND end_time IS NULL", employeeID).First(&segment).Error
+	return &segment, err
+}
+
+func (r *repository) GetOpenTimeSegmentsByWorkOrder(workOrderID uint) ([]rModels.MrMaintTimeSegment, error) {
+	var segments []rModels.MrMaintTimeSegment
+	err := r.db.Where("work_order_id = ? AND end_time IS NULL", workOrderID).Find(&segments).Error
+	return segments, err
+}
+
+func (r *repository) CountTimeSegments(assignmentID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&rModels.MrMaintTimeSegment{}).Where("assignment_id = ?", assignmentID).Count(&count).Error
+	return count, err
+}
+
+// StartTimeSegment abre un tramo y fija el inicio de la asignación en el primer fichaje.
+// El índice único parcial sobre employee_id impide dos tramos abiertos del mismo empleado.
+func (r *repository) StartTimeSegment(segment *rModels.MrMaintTimeSegment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Assignment", "Employee", "Shift").Create(segment).Error; err != nil {
+			return err
+		}
+		return tx.Model(&rModels.MrMaintWorkOrderAssignment{}).
+			Where("id = ? AND start_time IS NULL", segment.AssignmentID).
+			Update("start_time", segment.StartTime).Error
+	})
+}
+
+// CloseTimeSegment cierra el tramo si sigue abierto y recalcula HoursWorked de la asignación y el coste
+// de la OT. Con stop se da por terminado el trabajo del técnico en la orden.
+func (r *repository) CloseTimeSegment(segment *rModels.MrMaintTimeSegment, stop bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := closeTimeSegment(tx, segment, stop); err != nil {
+			return err
+		}
+		return r.recalculateCost(tx, segment.WorkOrderID)
+	})
+}
+
+func closeTimeSegment(tx *gorm.DB, segment *rModels.MrMaintTimeSegment, stop bool) error {
+	result := tx.Model(&rModels.MrMaintTimeSegment{}).
+		Where("id = ? AND end_time IS NULL", segment.ID).
+		Updates(map[string]interface{}{
+			"end_time":            segment.EndTime,
+			"hours":               segment.Hours,
+			"shift_id":            segment.ShiftID,
+			"outside_shift_hours": segment.OutsideShiftHours,
+			"outside_shift":       segment.OutsideShift,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSegmentClosed
+	}
+
+	updates := map[string]interface{}{
+		"hours_worked": gorm.Expr("(SELECT COALESCE(SUM(hours), 0) FROM mr_maint_time_segments WHERE assignment_id = ?)", segment.AssignmentID),
+	}
+	if stop {
+		updates["end_time"] = segment.EndTime
+	}
+	return tx.Model(&rModels.MrMaintWorkOrderAssignment{}).
+		Where("id = ?", segment.AssignmentID).
+		Updates(updates).Error
+}
+
+// ReopenAssignment permite volver a fichar en una asignación ya terminada
+func (r *repository) ReopenAssignment(assignmentID uint) error {
+	return r.db.Model(&rModels.MrMaintWorkOrderAssignment{}).
+		Where("id = ?", assignmentID).
+		Update("end_time", gorm.Expr("NULL")).Error
+}
+
+// GetAssignmentByEmployee devuelve la asignación del empleado en la orden
+func (r *repository) GetAssignmentByEmployee(workOrderID uint, employeeID uint) (*rModels.MrMaintWorkOrderAssignment, error) {
+	var assignment rModels.MrMaintWorkOrderAssignment
+	err := r.db.Where("work_order_id = ? AND employee_id = ?", workOrderID, employeeID).First(&assignment).Error
+	return &assignment, err
+}
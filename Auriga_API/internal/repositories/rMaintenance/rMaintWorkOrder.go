@@ -0,0 +1,71 @@
+package rMaintenance
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+func (r *repository) GetWorkOrders(filter WorkOrderFilter) ([]rModels.MrMaintWorkOrder, error) {
+	var workOrders []rModels.MrMaintWorkOrder
+
+	query := r.db.Preload("Asset").Preload("Creator").Preload("AssignedTeam")
+
+	if filter.AssetID != 0 {
+		query = query.Where("asset_id IN ("+assetSubtreeSQL+")", filter.AssetID)
+	}
+	if filter.MaintenancePlanID != 0 {
+		query = query.Where("maintenance_plan_id = ?", filter.MaintenancePlanID)
+	}
+	if len(filter.Status) > 0 {
+		query = query.Where("status IN ?", filter.Status)
+	}
+	if len(filter.Priority) > 0 {
+		query = query.Where("priority IN ?", filter.Priority)
+	}
+	if filter.WorkOrderType != "" {
+		query = query.Where("work_order_type = ?", filter.WorkOrderType)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("scheduled_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("scheduled_date <= ?", filter.To)
+	}
+
+	err := query.Order("scheduled_date DESC").Find(&workOrders).Error
+	return workOrders, err
+}
+
+func (r *repository) GetWorkOrderByID(id uint) (*rModels.MrMaintWorkOrder, error) {
+	var workOrder rModels.MrMaintWorkOrder
+	err := r.db.Preload("Asset").Preload("Creator").Preload("AssignedTeam").
+		Preload("MaintenancePlan").
+		Preload("Tasks", func(db *gorm.DB) *gorm.DB { return db.Order("task_number") }).
+		Preload("Tasks.Assignee").
+		Preload("AssignedMembers.Employee").
+		Preload("UsedSpareParts.Product").
+		Preload("Documents").
+		Preload("Reservations.Product").
+		Preload("StopEvents").
+		First(&workOrder, id).Error
+	return &workOrder, err
+}
+
+// CreateWorkOrder crea la orden junto con su primera entrada de histórico
+func (r *repository) CreateWorkOrder(workOrder *rModels.MrMaintWorkOrder, history *rModels.MrMaintWorkOrderStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Asset", "Creator", "AssignedTeam", "MaintenancePlan").Create(workOrder).Error; err != nil {
+			return err
+		}
+		history.WorkOrderID = workOrder.ID
+		return tx.Omit("WorkOrder", "Employee").Create(history).Error
+	})
+}
+
+// UpdateWorkOrder no modifica el estado ni sus fechas, que solo cambian mediante TransitionWorkOrder,
+// ni las horas y costes, que se recalculan con RecalculateWorkOrderCost
+func (r *repository) UpdateWorkOrder(workOrder *rModels.MrMaintWorkOrder) error {
+	return r.db.Omit("Status", "StartDate", "EndDate", "ActualHours", "LaborCost", "PartsCost", "Cost",
+		"Asset", "Creator", "AssignedTeam", "MaintenancePlan", "Tasks", "AssignedMembers", "UsedSpareParts", "Documents", "StatusHistory", "Reservations", "StopEvents").
+		Save(workOrder).Error
+}
@@ -0,0 +1,48 @@
+package rMaintenance
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+func (r *repository) GetAssignmentsByWorkOrder(workOrderID uint) ([]rModels.MrMaintWorkOrderAssignment, error) {
+	var assignments []rModels.MrMaintWorkOrderAssignment
+	err := r.db.Preload("Employee").
+		Where("work_order_id = ?", workOrderID).
+		Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *repository) GetAssignmentByID(id uint) (*rModels.MrMaintWorkOrderAssignment, error) {
+	var assignment rModels.MrMaintWorkOrderAssignment
+	err := r.db.Preload("Employee").First(&assignment, id).Error
+	return &assignment, err
+}
+
+// CreateAssignment, UpdateAssignment y DeleteAssignment recalculan el coste de la OT en la misma transacción
+func (r *repository) CreateAssignment(assignment *rModels.MrMaintWorkOrderAssignment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("WorkOrder", "Employee", "TimeSegments").Create(assignment).Error; err != nil {
+			return err
+		}
+		return r.recalculateCost(tx, assignment.WorkOrderID)
+	})
+}
+
+func (r *repository) UpdateAssignment(assignment *rModels.MrMaintWorkOrderAssignment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("WorkOrder", "Employee", "TimeSegments").Save(assignment).Error; err != nil {
+			return err
+		}
+		return r.recalculateCost(tx, assignment.WorkOrderID)
+	})
+}
+
+func (r *repository) DeleteAssignment(assignment *rModels.MrMaintWorkOrderAssignment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&rModels.MrMaintWorkOrderAssignment{}, assignment.ID).Error; err != nil {
+			return err
+		}
+		return r.recalculateCost(tx, assignment.WorkOrderID)
+	})
+}
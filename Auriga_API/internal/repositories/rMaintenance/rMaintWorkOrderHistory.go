@@ -0,0 +1,93 @@
+package rMaintenance
+
+import (
+	"errors"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+// TransitionEffects cambios que acompañan a la transición y se guardan en su misma transacción
+type TransitionEffects struct {
+	PlanRoll            *PlanRoll
+	UsageBaseline       *UsageBaseline
+	ReleaseReservations bool
+	// Tramos abiertos ya calculados; con StopSegments además se terminan sus asignaciones
+	CloseSegments []rModels.MrMaintTimeSegment
+	StopSegments  bool
+}
+
+// PlanRoll avance del plan por calendario a la siguiente ocurrencia
+type PlanRoll struct {
+	PlanID       uint
+	Occurrence   time.Time
+	LastExecuted *time.Time
+	Next         time.Time
+}
+
+// UsageBaseline nueva lectura de referencia de un plan por uso
+type UsageBaseline struct {
+	PlanID       uint
+	PreviousAt   *time.Time
+	Baseline     float64
+	BaselineAt   time.Time
+	LastExecuted *time.Time
+}
+
+// TransitionWorkOrder aplica el cambio de estado, registra el histórico y aplica los efectos en la misma
+// transacción. La actualización solo se aplica si la orden sigue en el estado 'from'.
+func (r *repository) TransitionWorkOrder(workOrder *rModels.MrMaintWorkOrder, from rModels.WorkOrderStatus, history *rModels.MrMaintWorkOrderStatusHistory, effects TransitionEffects) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&rModels.MrMaintWorkOrder{}).
+			Where("id = ? AND status = ?", workOrder.ID, from).
+			Select("Status", "StartDate", "EndDate", "CompletionNotes", "QualityCheck", "UpdatedAt").
+			Updates(workOrder)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+
+		history.WorkOrderID = workOrder.ID
+		if err := tx.Omit("WorkOrder", "Employee").Create(history).Error; err != nil {
+			return err
+		}
+
+		if roll := effects.PlanRoll; roll != nil {
+			if err := rollPlanForward(tx, roll.PlanID, roll.Occurrence, roll.LastExecuted, roll.Next); err != nil {
+				return err
+			}
+		}
+		if baseline := effects.UsageBaseline; baseline != nil {
+			if err := updatePlanUsageBaseline(tx, baseline.PlanID, baseline.PreviousAt, baseline.Baseline, baseline.BaselineAt, baseline.LastExecuted); err != nil {
+				return err
+			}
+		}
+		if effects.ReleaseReservations {
+			if err := releaseReservations(tx, workOrder.ID); err != nil {
+				return err
+			}
+		}
+		for i := range effects.CloseSegments {
+			// Un tramo cerrado a la vez por el propio técnico no impide la transición
+			err := closeTimeSegment(tx, &effects.CloseSegments[i], effects.StopSegments)
+			if err != nil && !errors.Is(err, ErrSegmentClosed) {
+				return err
+			}
+		}
+		if len(effects.CloseSegments) > 0 {
+			return r.recalculateCost(tx, workOrder.ID)
+		}
+		return nil
+	})
+}
+
+func (r *repository) GetStatusHistory(workOrderID uint) ([]rModels.MrMaintWorkOrderStatusHistory, error) {
+	var history []rModels.MrMaintWorkOrderStatusHistory
+	err := r.db.Preload("Employee").
+		Where("work_order_id = ?", workOrderID).
+		Order("changed_at, id").Find(&history).Error
+	return history, err
+}
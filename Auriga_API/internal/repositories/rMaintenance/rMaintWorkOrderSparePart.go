@@ -0,0 +1,55 @@
+package rMaintenance
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+func (r *repository) GetSparePartsByWorkOrder(workOrderID uint) ([]rModels.MrMaintWorkOrderSparePart, error) {
+	var spareParts []rModels.MrMaintWorkOrderSparePart
+	err := r.db.Preload("Product").Preload("Stock").Preload("SparePartAsset").
+		Where("work_order_id = ?", workOrderID).
+		Find(&spareParts).Error
+	return spareParts, err
+}
+
+func (r *repository) GetSparePartByID(id uint) (*rModels.MrMaintWorkOrderSparePart, error) {
+	var sparePart rModels.MrMaintWorkOrderSparePart
+	err := r.db.Preload("Product").Preload("Stock").Preload("SparePartAsset").
+		First(&sparePart, id).Error
+	return &sparePart, err
+}
+
+// CreateSparePart, UpdateSparePart y DeleteSparePart recalculan el coste de la OT en la misma transacción
+func (r *repository) CreateSparePart(sparePart *rModels.MrMaintWorkOrderSparePart) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("WorkOrder", "Product", "Stock", "SparePartAsset", "AssetMovement").Create(sparePart).Error; err != nil {
+			return err
+		}
+		return r.recalculateCost(tx, sparePart.WorkOrderID)
+	})
+}
+
+func (r *repository) UpdateSparePart(sparePart *rModels.MrMaintWorkOrderSparePart) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("WorkOrder", "Product", "Stock", "SparePartAsset", "AssetMovement").Save(sparePart).Error; err != nil {
+			return err
+		}
+		return r.recalculateCost(tx, sparePart.WorkOrderID)
+	})
+}
+
+func (r *repository) GetSparePartStock(id uint) (*rModels.MrSparePartStock, error) {
+	var stock rModels.MrSparePartStock
+	err := r.db.First(&stock, id).Error
+	return &stock, err
+}
+
+func (r *repository) DeleteSparePart(sparePart *rModels.MrMaintWorkOrderSparePart) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&rModels.MrMaintWorkOrderSparePart{}, sparePart.ID).Error; err != nil {
+			return err
+		}
+		return r.recalculateCost(tx, sparePart.WorkOrderID)
+	})
+}
@@ -0,0 +1,31 @@
+package rMaintenance
+
+import (
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+func (r *repository) GetTasksByWorkOrder(workOrderID uint) ([]rModels.MrMaintWorkOrderTask, error) {
+	var tasks []rModels.MrMaintWorkOrderTask
+	err := r.db.Preload("Assignee").
+		Where("work_order_id = ?", workOrderID).
+		Order("task_number").Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *repository) GetTaskByID(id uint) (*rModels.MrMaintWorkOrderTask, error) {
+	var task rModels.MrMaintWorkOrderTask
+	err := r.db.Preload("Assignee").First(&task, id).Error
+	return &task, err
+}
+
+func (r *repository) CreateTask(task *rModels.MrMaintWorkOrderTask) error {
+	return r.db.Omit("WorkOrder", "Assignee").Create(task).Error
+}
+
+func (r *repository) UpdateTask(task *rModels.MrMaintWorkOrderTask) error {
+	return r.db.Omit("WorkOrder", "Assignee").Save(task).Error
+}
+
+func (r *repository) DeleteTask(id uint) error {
+	return r.db.Delete(&rModels.MrMaintWorkOrderTask{}, id).Error
+}
@@ -0,0 +1,140 @@
+package rMaintenance
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/config"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetWorkOrders(filter WorkOrderFilter) ([]rModels.MrMaintWorkOrder, error)
+	GetWorkOrderByID(id uint) (*rModels.MrMaintWorkOrder, error)
+	CreateWorkOrder(workOrder *rModels.MrMaintWorkOrder, history *rModels.MrMaintWorkOrderStatusHistory) error
+	UpdateWorkOrder(workOrder *rModels.MrMaintWorkOrder) error
+	TransitionWorkOrder(workOrder *rModels.MrMaintWorkOrder, from rModels.WorkOrderStatus, history *rModels.MrMaintWorkOrderStatusHistory, effects TransitionEffects) error
+	GetStatusHistory(workOrderID uint) ([]rModels.MrMaintWorkOrderStatusHistory, error)
+
+	GetTasksByWorkOrder(workOrderID uint) ([]rModels.MrMaintWorkOrderTask, error)
+	GetTaskByID(id uint) (*rModels.MrMaintWorkOrderTask, error)
+	CreateTask(task *rModels.MrMaintWorkOrderTask) error
+	UpdateTask(task *rModels.MrMaintWorkOrderTask) error
+	DeleteTask(id uint) error
+
+	GetAssignmentsByWorkOrder(workOrderID uint) ([]rModels.MrMaintWorkOrderAssignment, error)
+	GetAssignmentByID(id uint) (*rModels.MrMaintWorkOrderAssignment, error)
+	CreateAssignment(assignment *rModels.MrMaintWorkOrderAssignment) error
+	UpdateAssignment(assignment *rModels.MrMaintWorkOrderAssignment) error
+	DeleteAssignment(assignment *rModels.MrMaintWorkOrderAssignment) error
+	GetAssignmentByEmployee(workOrderID uint, employeeID uint) (*rModels.MrMaintWorkOrderAssignment, error)
+	ReopenAssignment(assignmentID uint) error
+
+	GetTimeSegmentsByWorkOrder(workOrderID uint) ([]rModels.MrMaintTimeSegment, error)
+	GetOpenTimeSegment(employeeID uint) (*rModels.MrMaintTimeSegment, error)
+	GetOpenTimeSegmentsByWorkOrder(workOrderID uint) ([]rModels.MrMaintTimeSegment, error)
+	CountTimeSegments(assignmentID uint) (int64, error)
+	StartTimeSegment(segment *rModels.MrMaintTimeSegment) error
+	CloseTimeSegment(segment *rModels.MrMaintTimeSegment, stop bool) error
+
+	GetDuePlans(now time.Time, frequencyTypes []rModels.FrequencyType) ([]rModels.MrMaintenancePlan, error)
+	CreatePlanWorkOrder(workOrder *rModels.MrMaintWorkOrder, history *rModels.MrMaintWorkOrderStatusHistory) (bool, error)
+	GetActivePlans(frequencyTypes []rModels.FrequencyType) ([]rModels.MrMaintenancePlan, error)
+	GetPlanByID(id uint) (*rModels.MrMaintenancePlan, error)
+	UpdatePlanUsageBaseline(planID uint, previousAt *time.Time, baseline float64, baselineAt time.Time, lastExecuted *time.Time) error
+	GetConditionPlans() ([]rModels.MrMaintenancePlan, error)
+	SetPlanConditionLatched(planID uint, latched bool) (bool, error)
+	HasOpenPlanWorkOrder(planID uint) (bool, error)
+
+	GetAssetSubtree(rootID uint) ([]AssetNode, error)
+	GetKpiWorkOrders(rootID uint, from time.Time, to time.Time) ([]KpiWorkOrder, error)
+	GetKpiStops(path []string, from time.Time, to time.Time) ([]KpiStop, error)
+
+	GetCostWorkOrders(rootID uint, from time.Time, to time.Time) ([]rModels.MrMaintWorkOrder, error)
+
+	GetLaborRates() ([]rModels.MrMaintLaborRate, error)
+	GetLaborRateByID(id uint) (*rModels.MrMaintLaborRate, error)
+	CreateLaborRate(rate *rModels.MrMaintLaborRate) error
+	UpdateLaborRate(rate *rModels.MrMaintLaborRate) error
+	DeleteLaborRate(id uint) error
+
+	GetSparePartsByWorkOrder(workOrderID uint) ([]rModels.MrMaintWorkOrderSparePart, error)
+	GetSparePartByID(id uint) (*rModels.MrMaintWorkOrderSparePart, error)
+	CreateSparePart(sparePart *rModels.MrMaintWorkOrderSparePart) error
+	UpdateSparePart(sparePart *rModels.MrMaintWorkOrderSparePart) error
+	DeleteSparePart(sparePart *rModels.MrMaintWorkOrderSparePart) error
+	GetSparePartStock(id uint) (*rModels.MrSparePartStock, error)
+
+	GetEventRules() ([]rModels.MrMaintEventRule, error)
+	GetEventRuleByID(id uint) (*rModels.MrMaintEventRule, error)
+	GetActiveEventRules(categoryName string) ([]rModels.MrMaintEventRule, error)
+	CreateEventRule(rule *rModels.MrMaintEventRule) error
+	UpdateEventRule(rule *rModels.MrMaintEventRule) error
+	DeleteEventRule(id uint) error
+	GetEventCategory(id uint) (*rModels.MrEventCategory, error)
+	GetEventType(id uint) (*rModels.MrEventType, error)
+	GetCommitEvent(id uint) (*rModels.MrCommitEvents, error)
+	FindAssetByPath(path []string) (*rModels.MrAsset, error)
+	CreateEventWorkOrder(workOrder *rModels.MrMaintWorkOrder, history *rModels.MrMaintWorkOrderStatusHistory, event *rModels.MrCommitEvents, openStatuses []rModels.WorkOrderStatus) (uint, bool, error)
+	GetPendingStopEvents(limit int) ([]rModels.MrCommitEvents, error)
+	RecordStopEventResult(event *rModels.MrCommitEvents, processed bool, errMsg string) error
+
+	GetWorkOrderPack(id uint) (*rModels.MrMaintWorkOrder, error)
+	GetAvailableStocks(productIDs []uint) ([]rModels.MrSparePartStock, error)
+}
+
+// Errores de concurrencia: otro proceso modificó el registro entre la lectura y la escritura
+var (
+	ErrStatusChanged = errors.New("work order status changed concurrently")
+	ErrSegmentClosed = errors.New("time segment already closed")
+	ErrEventChanged  = errors.New("stop event changed concurrently")
+)
+
+// WorkOrderFilter criterios de búsqueda de órdenes de trabajo
+type WorkOrderFilter struct {
+	AssetID           uint // Incluye todo el subárbol del activo
+	MaintenancePlanID uint
+	Status            []rModels.WorkOrderStatus
+	Priority          []rModels.PriorityLevel
+	WorkOrderType     rModels.WorkOrderType
+	From              time.Time // Sobre ScheduledDate
+	To                time.Time
+}
+
+type repository struct {
+	db     *gorm.DB
+	config *config.Settings
+	logger *zap.Logger
+}
+
+func New(db *gorm.DB, cfg *config.Settings, logger *zap.Logger) Repository {
+	return &repository{
+		db:     db,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// recalculateCost actualiza el coste de la OT con la tarifa por defecto de la configuración
+func (r *repository) recalculateCost(tx *gorm.DB, workOrderID uint) error {
+	return RecalculateWorkOrderCost(tx, workOrderID, r.config.Maintenance.DefaultLaborRate)
+}
+
+// assetSubtreeSQL devuelve los IDs del activo indicado y de todos sus descendientes
+const assetSubtreeSQL = `
+	WITH RECURSIVE asset_tree AS (
+		SELECT id FROM mr_assets WHERE id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT a.id FROM mr_assets a
+		INNER JOIN asset_tree at ON a.parent_id = at.id
+		WHERE a.deleted_at IS NULL
+	)
+	SELECT id FROM asset_tree`
+
+// IsDuplicateKey detecta violaciones de índice único de PostgreSQL (SQLSTATE 23505)
+func IsDuplicateKey(err error) bool {
+	return err != nil && (errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "23505"))
+}
@@ -6,6 +6,7 @@ import (
 	"gorm.io/gorm"
 )
 
+// MrRawEvents evento bruto de la línea; instante y ruta forman su clave natural (idx_mr_raw_events_natural_key)
 type MrRawEvents struct {
 	Time      time.Time      `gorm:"column:_time"`
 	ESW       int            `gorm:"column:\"ESW\";type:int4"`
@@ -22,14 +23,21 @@ type MrRawEvents struct {
 }
 
 type MrCommitEvents struct {
-	EventTime     time.Time
-	EventType     string `gorm:"type:text"`
-	EventCategory string `gorm:"type:text"`
-	Factory       string `gorm:"type:text"`
-	ProdLine      string `gorm:"type:text"`
-	System        string `gorm:"type:text"`
-	Machine       string `gorm:"type:text"`
-	Part          string `gorm:"type:text"`
+	EventTime     time.Time  // Inicio de la parada
+	EndTime       *time.Time `gorm:"index"` // Fin de la parada; nulo mientras la línea sigue parada
+	EventType     string     `gorm:"type:text"`
+	EventCategory string     `gorm:"type:text"`
+	Factory       string     `gorm:"type:text"`
+	ProdLine      string     `gorm:"type:text"`
+	System        string     `gorm:"type:text"`
+	Machine       string     `gorm:"type:text"`
+	Part          string     `gorm:"type:text"`
+	WorkOrderID   *uint      `gorm:"index"` // OT de mantenimiento vinculada al evento
+	// Cola de las reglas de mantenimiento: el evento queda pendiente hasta que se aplican con éxito o
+	// se agotan los intentos, y el último error queda guardado para revisarlo
+	MaintProcessedAt *time.Time `gorm:"index"`
+	MaintAttempts    int        `gorm:"not null;default:0"`
+	MaintError       string     `gorm:"type:text"`
 	gorm.Model
 }
 
@@ -54,3 +62,50 @@ type MrEventType struct {
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
+
+// Tipos de evento y acciones del registro de auditoría
+const (
+	EventAuditRaw    = "raw"
+	EventAuditCommit = "commit"
+
+	EventAuditCreated   = "created"
+	EventAuditUpdated   = "updated"
+	EventAuditClosed    = "closed"
+	EventAuditDeleted   = "deleted"
+	EventAuditRestored  = "restored"
+	EventAuditCommitted = "committed" // Evento bruto confirmado; After es el evento confirmado creado
+)
+
+// MrEventAudit - Registro de solo inserción de los cambios en eventos brutos y confirmados
+type MrEventAudit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	EventKind string    `gorm:"size:16;not null;index:idx_eventaudit_event" json:"event_kind"`
+	EventID   uint      `gorm:"not null;index:idx_eventaudit_event" json:"event_id"`
+	Action    string    `gorm:"size:16;not null" json:"action"`
+	Before    string    `gorm:"type:text" json:"before"` // JSON del evento antes del cambio; vacío en la creación
+	After     string    `gorm:"type:text" json:"after"`  // JSON del evento tras el cambio; vacío en el borrado
+	UserID    string    `gorm:"size:255" json:"user_id"` // Sujeto del token; "system" en los procesos automáticos
+	UserName  string    `gorm:"size:255" json:"user_name"`
+	Reason    string    `gorm:"type:text" json:"reason"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// MrEventClassificationRule - Clasificación automática de eventos brutos según su código ESW y ruta.
+// Los campos de ruta vacíos valen para cualquier valor.
+type MrEventClassificationRule struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ESW         int       `gorm:"not null;index" json:"esw"`
+	Factory     string    `gorm:"size:32" json:"factory"`
+	ProdLine    string    `gorm:"size:32" json:"prod_line"`
+	System      string    `gorm:"size:255" json:"system"`
+	Machine     string    `gorm:"size:255" json:"machine"`
+	EventTypeID uint      `gorm:"not null;index" json:"event_type_id"`
+	Priority    int       `gorm:"default:0" json:"priority"`  // Entre reglas aplicables gana la de mayor prioridad
+	Confidence  int       `gorm:"not null" json:"confidence"` // 0-100; por debajo del umbral solo se sugiere
+	Active      bool      `gorm:"not null" json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// RELACIONES
+	EventType MrEventType `gorm:"foreignKey:EventTypeID" json:"event_type"`
+}
@@ -144,16 +144,53 @@ type MrShift struct {
 	ShiftAssignments []MrShiftAssignment `gorm:"foreignKey:ShiftID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"shift_assignments,omitempty"`
 }
 
+// Overlap calcula el tiempo del intervalo que cae dentro de las ventanas diarias del turno, ampliadas
+// con tolerance por cada lado. Los turnos que cruzan la medianoche terminan al día siguiente.
+func (s *MrShift) Overlap(start time.Time, end time.Time, tolerance time.Duration) time.Duration {
+	startHour, startMin, startSec := s.StartTime.Clock()
+	endHour, endMin, endSec := s.EndTime.Clock()
+
+	var inside time.Duration
+	loc := start.Location()
+	// Se empieza el día anterior por el turno de noche que arrancó antes de medianoche
+	for day := time.Date(start.Year(), start.Month(), start.Day()-1, 0, 0, 0, 0, loc); !day.After(end); day = day.AddDate(0, 0, 1) {
+		windowStart := time.Date(day.Year(), day.Month(), day.Day(), startHour, startMin, startSec, 0, loc)
+		windowEnd := time.Date(day.Year(), day.Month(), day.Day(), endHour, endMin, endSec, 0, loc)
+		if !windowEnd.After(windowStart) {
+			windowEnd = windowEnd.AddDate(0, 0, 1)
+		}
+		windowStart = windowStart.Add(-tolerance)
+		windowEnd = windowEnd.Add(tolerance)
+
+		from, to := start, end
+		if windowStart.After(from) {
+			from = windowStart
+		}
+		if windowEnd.Before(to) {
+			to = windowEnd
+		}
+		if to.After(from) {
+			inside += to.Sub(from)
+		}
+	}
+
+	// Con tolerancias grandes las ventanas pueden solaparse
+	if total := end.Sub(start); inside > total {
+		inside = total
+	}
+	return inside
+}
+
 // MrShiftAssignment - Asignación de turnos
 type MrShiftAssignment struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	EmployeeID uint      `gorm:"not null;index" json:"employee_id"`
-	ShiftID    uint      `gorm:"not null;index" json:"shift_id"`
-	StartDate  time.Time `json:"start_date"`
-	EndDate    time.Time `json:"end_date"`
-	Active     bool      `gorm:"default:true" json:"active"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	EmployeeID uint       `gorm:"not null;index" json:"employee_id"`
+	ShiftID    uint       `gorm:"not null;index" json:"shift_id"`
+	StartDate  time.Time  `json:"start_date"`
+	EndDate    *time.Time `json:"end_date"` // Nulo mientras la asignación sigue vigente
+	Active     bool       `gorm:"default:true" json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 
 	// RELACIONES
 	Employee MrEmployee `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"employee"`
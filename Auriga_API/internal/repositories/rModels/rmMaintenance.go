@@ -26,6 +26,19 @@ type MrMaintenancePlan struct {
 	ThresholdValue     *float64 `gorm:"type:decimal(10,4)" json:"threshold_value"`
 	SensorAssetID      *uint    `gorm:"index" json:"sensor_asset_id"` // Activo sensor relacionado
 
+	// Evaluación de la condición: la señal ConditionParameter se lee en la línea del activo sensor
+	ConditionPath        *string             `gorm:"size:255" json:"condition_path"`                 // EL_Lv1/EL_Lv2/EL_Lv3 de la señal
+	ConditionComparator  ConditionComparator `gorm:"type:varchar(5)" json:"condition_comparator"`    // gt, gte, lt, lte (gt por defecto)
+	ConditionHysteresis  float64             `gorm:"type:decimal(10,4)" json:"condition_hysteresis"` // Margen para rearmar la condición
+	ConditionMinDuration int                 `json:"condition_min_duration"`                         // Segundos que debe mantenerse la condición
+	ConditionLatched     bool                `gorm:"default:false" json:"condition_latched"`         // Disparada y pendiente de normalizarse
+
+	// Campos para mantenimiento por uso: contador acumulado en InfluxDB en la unidad de FrequencyType
+	UsageCounterPath  *string    `gorm:"size:255" json:"usage_counter_path"`       // EL_Lv1/EL_Lv2/EL_Lv3 del contador
+	UsageCounterField *string    `gorm:"size:100" json:"usage_counter_field"`      // _field con el valor acumulado
+	UsageBaseline     *float64   `gorm:"type:decimal(18,4)" json:"usage_baseline"` // Lectura del contador en la última ejecución
+	UsageBaselineAt   *time.Time `json:"usage_baseline_at"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -72,7 +85,8 @@ type MrMaintenanceSparePart struct {
 // MrMaintWorkOrder - Orden de trabajo de mantenimiento
 type MrMaintWorkOrder struct {
 	ID                uint            `gorm:"primaryKey" json:"id"`
-	MaintenancePlanID *uint           `gorm:"index" json:"maintenance_plan_id"` // Puede ser nulo para OT correctivas no planificadas
+	MaintenancePlanID *uint           `gorm:"index;uniqueIndex:idx_maintworkorder_plan_occurrence" json:"maintenance_plan_id"` // Puede ser nulo para OT correctivas no planificadas
+	PlanOccurrence    *time.Time      `gorm:"uniqueIndex:idx_maintworkorder_plan_occurrence" json:"plan_occurrence"`           // Vencimiento del plan que originó la OT (evita duplicados)
 	AssetID           uint            `gorm:"not null;index:idx_maintworkorder_asset_status" json:"asset_id"`
 	WorkOrderType     WorkOrderType   `gorm:"type:varchar(20);not null" json:"work_order_type"`
 	Priority          PriorityLevel   `gorm:"type:varchar(20);not null" json:"priority"`
@@ -83,11 +97,21 @@ type MrMaintWorkOrder struct {
 	StartDate         *time.Time      `json:"start_date"`
 	EndDate           *time.Time      `json:"end_date"`
 	EstimatedHours    float64         `gorm:"type:decimal(4,2)" json:"estimated_hours"`
-	ActualHours       float64         `gorm:"type:decimal(4,2)" json:"actual_hours"`
-	Cost              float64         `gorm:"type:decimal(10,2)" json:"cost"`
-	CreatedBy         uint            `gorm:"not null" json:"created_by"` // EmployeeID del creador
+	ActualHours       float64         `gorm:"type:decimal(8,2)" json:"actual_hours"` // Suma de las horas trabajadas de las asignaciones
+	LaborCost         float64         `gorm:"type:decimal(10,2)" json:"labor_cost"`
+	PartsCost         float64         `gorm:"type:decimal(10,2)" json:"parts_cost"`
+	Cost              float64         `gorm:"type:decimal(10,2)" json:"cost"` // Mano de obra + repuestos, se recalcula automáticamente
+	CreatedBy         uint            `gorm:"not null" json:"created_by"`     // EmployeeID del creador
 	AssignedTeamID    *uint           `gorm:"index" json:"assigned_team_id"`
 
+	// Medición que disparó la OT (mantenimiento por condición)
+	TriggerParameter *string    `gorm:"size:100" json:"trigger_parameter"`
+	TriggerValue     *float64   `gorm:"type:decimal(18,4)" json:"trigger_value"`
+	TriggeredAt      *time.Time `json:"triggered_at"`
+
+	// Regla que generó la OT a partir de un evento de parada
+	EventRuleID *uint `gorm:"index" json:"event_rule_id"`
+
 	// Campos adicionales para trazabilidad
 	CompletionNotes *string `gorm:"type:text" json:"completion_notes"` // Observaciones al cerrar
 	QualityCheck    *bool   `gorm:"default:null" json:"quality_check"` // Verificación de calidad
@@ -96,14 +120,82 @@ type MrMaintWorkOrder struct {
 	UpdatedAt time.Time `json:"updated_at"`
 
 	// RELACIONES
-	MaintenancePlan *MrMaintenancePlan           `gorm:"foreignKey:MaintenancePlanID" json:"maintenance_plan,omitempty"`
-	Asset           MrAsset                      `gorm:"foreignKey:AssetID" json:"asset"`
-	Creator         MrEmployee                   `gorm:"foreignKey:CreatedBy" json:"creator"`
-	AssignedTeam    *MrTeam                      `gorm:"foreignKey:AssignedTeamID" json:"assigned_team,omitempty"`
-	AssignedMembers []MrMaintWorkOrderAssignment `gorm:"foreignKey:WorkOrderID" json:"assigned_members"`
-	Tasks           []MrMaintWorkOrderTask       `gorm:"foreignKey:WorkOrderID" json:"tasks"`
-	UsedSpareParts  []MrMaintWorkOrderSparePart  `gorm:"foreignKey:WorkOrderID" json:"used_spare_parts"`
-	Documents       []MrDocuments                `gorm:"many2many:mr_maint_work_order_documents;joinForeignKey:WorkOrderID;joinReferences:DocumentID" json:"documents"`
+	MaintenancePlan *MrMaintenancePlan              `gorm:"foreignKey:MaintenancePlanID" json:"maintenance_plan,omitempty"`
+	Asset           MrAsset                         `gorm:"foreignKey:AssetID" json:"asset"`
+	Creator         MrEmployee                      `gorm:"foreignKey:CreatedBy" json:"creator"`
+	AssignedTeam    *MrTeam                         `gorm:"foreignKey:AssignedTeamID" json:"assigned_team,omitempty"`
+	AssignedMembers []MrMaintWorkOrderAssignment    `gorm:"foreignKey:WorkOrderID" json:"assigned_members"`
+	Tasks           []MrMaintWorkOrderTask          `gorm:"foreignKey:WorkOrderID" json:"tasks"`
+	UsedSpareParts  []MrMaintWorkOrderSparePart     `gorm:"foreignKey:WorkOrderID" json:"used_spare_parts"`
+	Documents       []MrDocuments                   `gorm:"many2many:mr_maint_work_order_documents;joinForeignKey:WorkOrderID;joinReferences:DocumentID" json:"documents"`
+	StatusHistory   []MrMaintWorkOrderStatusHistory `gorm:"foreignKey:WorkOrderID" json:"status_history,omitempty"`
+	Reservations    []MrMaintSparePartReservation   `gorm:"foreignKey:WorkOrderID" json:"reservations"`
+	StopEvents      []MrCommitEvents                `gorm:"foreignKey:WorkOrderID" json:"stop_events,omitempty"`
+}
+
+// MrMaintSparePartReservation - Reserva de repuestos para una orden de trabajo (según MrMaintenanceSparePart del plan)
+type MrMaintSparePartReservation struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	WorkOrderID uint              `gorm:"not null;index" json:"work_order_id"`
+	ProductID   uint              `gorm:"not null;index:idx_maintsparepartreservation_product_status" json:"product_id"`
+	StockID     *uint             `gorm:"index" json:"stock_id"` // Stock del que se recogerá; nulo si el producto no tiene stock
+	Quantity    float64           `gorm:"type:decimal(15,6);not null" json:"quantity"`
+	Unit        string            `gorm:"size:50" json:"unit"`
+	Status      ReservationStatus `gorm:"type:varchar(20);not null;index:idx_maintsparepartreservation_product_status" json:"status"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+
+	// RELACIONES
+	WorkOrder MrMaintWorkOrder `gorm:"foreignKey:WorkOrderID" json:"-"`
+	Product   MrProduct        `gorm:"foreignKey:ProductID" json:"product"`
+}
+
+// MrMaintLaborRate - Tarifa horaria de mano de obra por empleado o, en su defecto, por rol
+type MrMaintLaborRate struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID *uint     `gorm:"uniqueIndex" json:"employee_id"`                                                     // Tarifa específica del empleado
+	Role       string    `gorm:"size:100;uniqueIndex:idx_maintlaborrate_role,where:employee_id IS NULL" json:"role"` // Tarifa del rol de la asignación
+	HourlyRate float64   `gorm:"type:decimal(10,2);not null" json:"hourly_rate"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// RELACIONES
+	Employee *MrEmployee `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+}
+
+// MrMaintEventRule - Regla que genera una OT correctiva al confirmar un evento de parada de la categoría/tipo indicados
+type MrMaintEventRule struct {
+	ID              uint          `gorm:"primaryKey" json:"id"`
+	Name            string        `gorm:"size:255;not null" json:"name"`
+	EventCategoryID uint          `gorm:"not null;index" json:"event_category_id"`
+	EventTypeID     *uint         `gorm:"index" json:"event_type_id"` // Nulo: cualquier tipo de la categoría
+	Priority        PriorityLevel `gorm:"type:varchar(20);not null" json:"priority"`
+	Title           string        `gorm:"size:255" json:"title"` // Título de la OT; por defecto categoría, tipo y activo
+	AssignedTeamID  *uint         `gorm:"index" json:"assigned_team_id"`
+	Active          bool          `gorm:"default:true" json:"active"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+
+	// RELACIONES
+	EventCategory MrEventCategory `gorm:"foreignKey:EventCategoryID" json:"event_category"`
+	EventType     *MrEventType    `gorm:"foreignKey:EventTypeID" json:"event_type,omitempty"`
+	AssignedTeam  *MrTeam         `gorm:"foreignKey:AssignedTeamID" json:"assigned_team,omitempty"`
+}
+
+// MrMaintWorkOrderStatusHistory - Histórico de cambios de estado de la orden de trabajo
+type MrMaintWorkOrderStatusHistory struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	WorkOrderID uint            `gorm:"not null;index:idx_maintworkorderhistory_workorder_date" json:"work_order_id"`
+	FromStatus  WorkOrderStatus `gorm:"type:varchar(20)" json:"from_status"` // Vacío en la creación de la orden
+	ToStatus    WorkOrderStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	ChangedBy   uint            `gorm:"not null" json:"changed_by"` // EmployeeID que realiza el cambio
+	ChangedAt   time.Time       `gorm:"not null;index:idx_maintworkorderhistory_workorder_date" json:"changed_at"`
+	Reason      string          `gorm:"type:text" json:"reason"`
+	CreatedAt   time.Time       `json:"created_at"`
+
+	// RELACIONES
+	WorkOrder MrMaintWorkOrder `gorm:"foreignKey:WorkOrderID" json:"-"`
+	Employee  MrEmployee       `gorm:"foreignKey:ChangedBy" json:"employee"`
 }
 
 // MrMaintWorkOrderAssignment - Asignación de empleados a orden de trabajo de mantenimiento
@@ -114,12 +206,34 @@ type MrMaintWorkOrderAssignment struct {
 	Role        string     `gorm:"size:100;not null" json:"role"` // Líder, Técnico, Ayudante, etc.
 	StartTime   *time.Time `json:"start_time"`
 	EndTime     *time.Time `json:"end_time"`
-	HoursWorked float64    `gorm:"type:decimal(4,2)" json:"hours_worked"`
+	HoursWorked float64    `gorm:"type:decimal(8,2)" json:"hours_worked"` // Suma de los tramos de fichaje
 	CreatedAt   time.Time  `json:"created_at"`
 
 	// RELACIONES
-	WorkOrder MrMaintWorkOrder `gorm:"foreignKey:WorkOrderID" json:"work_order"`
-	Employee  MrEmployee       `gorm:"foreignKey:EmployeeID" json:"employee"`
+	WorkOrder    MrMaintWorkOrder     `gorm:"foreignKey:WorkOrderID" json:"work_order"`
+	Employee     MrEmployee           `gorm:"foreignKey:EmployeeID" json:"employee"`
+	TimeSegments []MrMaintTimeSegment `gorm:"foreignKey:AssignmentID" json:"time_segments,omitempty"`
+}
+
+// MrMaintTimeSegment - Tramo de trabajo fichado por un técnico en una orden de trabajo
+type MrMaintTimeSegment struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	AssignmentID      uint       `gorm:"not null;index" json:"assignment_id"`
+	WorkOrderID       uint       `gorm:"not null;index" json:"work_order_id"`
+	EmployeeID        uint       `gorm:"not null;index;uniqueIndex:idx_mainttimesegment_employee_open,where:end_time IS NULL" json:"employee_id"` // Un empleado solo puede tener un tramo abierto
+	StartTime         time.Time  `gorm:"not null" json:"start_time"`
+	EndTime           *time.Time `json:"end_time"` // Nulo mientras el técnico está fichado
+	Hours             float64    `gorm:"type:decimal(8,2)" json:"hours"`
+	ShiftID           *uint      `gorm:"index" json:"shift_id"`                        // Turno asignado al empleado al iniciar el tramo
+	OutsideShiftHours float64    `gorm:"type:decimal(8,2)" json:"outside_shift_hours"` // Parte del tramo fuera del turno
+	OutsideShift      bool       `gorm:"not null;default:false" json:"outside_shift"`  // Marca para revisión
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// RELACIONES
+	Assignment MrMaintWorkOrderAssignment `gorm:"foreignKey:AssignmentID" json:"-"`
+	Employee   MrEmployee                 `gorm:"foreignKey:EmployeeID" json:"employee"`
+	Shift      *MrShift                   `gorm:"foreignKey:ShiftID" json:"shift,omitempty"`
 }
 
 // MrMaintWorkOrderTask - Tareas específicas de la orden de trabajo de mantenimiento
@@ -166,16 +280,16 @@ type MrMaintWorkOrderSparePart struct {
 // MrSparePartStock - Control de stock para productos no discretos o control avanzado
 type MrSparePartStock struct {
 	ID               uint       `gorm:"primaryKey" json:"id"`
-	WarehouseAssetID uint       `gorm:"not null;index:idx_sparepartstock_warehouse_product" json:"warehouse_asset_id"`
-	ProductID        uint       `gorm:"not null;index:idx_sparepartstock_warehouse_product" json:"product_id"`
+	WarehouseAssetID uint       `gorm:"not null;index:idx_sparepartstock_warehouse_product;uniqueIndex:idx_sparepartstock_warehouse_product_batch" json:"warehouse_asset_id"`
+	ProductID        uint       `gorm:"not null;index:idx_sparepartstock_warehouse_product;uniqueIndex:idx_sparepartstock_warehouse_product_batch" json:"product_id"`
 	Quantity         float64    `gorm:"type:decimal(15,6);not null" json:"quantity"`
 	MinStock         float64    `gorm:"type:decimal(15,6)" json:"min_stock"`
 	MaxStock         float64    `gorm:"type:decimal(15,6)" json:"max_stock"`
-	BatchNumber      string     `gorm:"size:100" json:"batch_number"`          // Para trazabilidad
-	ExpiryDate       *time.Time `json:"expiry_date"`                           // Para productos perecederos
-	Location         string     `gorm:"size:100" json:"location"`              // Ubicación específica
-	UnitCost         float64    `gorm:"type:decimal(15,6)" json:"unit_cost"`   // Costo unitario promedio
-	TotalValue       float64    `gorm:"type:decimal(15,2)" json:"total_value"` // Valor total
+	BatchNumber      string     `gorm:"size:100;uniqueIndex:idx_sparepartstock_warehouse_product_batch" json:"batch_number"` // Para trazabilidad
+	ExpiryDate       *time.Time `json:"expiry_date"`                                                                         // Para productos perecederos
+	Location         string     `gorm:"size:100" json:"location"`                                                            // Ubicación específica
+	UnitCost         float64    `gorm:"type:decimal(15,6)" json:"unit_cost"`                                                 // Costo unitario promedio
+	TotalValue       float64    `gorm:"type:decimal(15,2)" json:"total_value"`                                               // Valor total
 	CreatedAt        time.Time  `json:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at"`
 
@@ -191,17 +305,18 @@ type MrAssetRegisterMovement struct {
 	StockID       uint              `gorm:"not null;index:idx_assetregistermovement_stock_date" json:"stock_id"`
 	MovementType  AssetMovementType `gorm:"type:varchar(20);not null" json:"movement_type"`
 	MovementDate  time.Time         `gorm:"index:idx_assetregistermovement_stock_date" json:"movement_date"`
-	Quantity      float64           `gorm:"type:decimal(15,6);not null" json:"quantity"`
+	Quantity      float64           `gorm:"type:decimal(15,6);not null" json:"quantity"` // Variación del stock: negativa en las salidas
 	UnitCost      float64           `gorm:"type:decimal(15,6)" json:"unit_cost"`
 	TotalCost     float64           `gorm:"type:decimal(15,2)" json:"total_cost"`
 	PreviousStock float64           `gorm:"type:decimal(15,6)" json:"previous_stock"`
 	NewStock      float64           `gorm:"type:decimal(15,6)" json:"new_stock"`
 
 	// Referencias para trazabilidad
-	WorkOrderID     *uint `gorm:"index" json:"work_order_id"`     // Si es consumo por OT
-	PurchaseOrderID *uint `gorm:"index" json:"purchase_order_id"` // Si es entrada por compra
-	RequisitionID   *uint `gorm:"index" json:"requisition_id"`    // Si es por requisición
-	AssetID         *uint `gorm:"index" json:"asset_id"`          // Activo específico relacionado
+	WorkOrderID       *uint `gorm:"index" json:"work_order_id"`       // Si es consumo por OT
+	PurchaseOrderID   *uint `gorm:"index" json:"purchase_order_id"`   // Si es entrada por compra
+	RequisitionID     *uint `gorm:"index" json:"requisition_id"`      // Si es por requisición
+	AssetID           *uint `gorm:"index" json:"asset_id"`            // Activo específico relacionado
+	RelatedMovementID *uint `gorm:"index" json:"related_movement_id"` // Movimiento de salida de una transferencia
 
 	// Información del movimiento
 	ReferenceNumber string    `gorm:"size:100" json:"reference_number"` // Nº documento
@@ -228,6 +343,12 @@ type MrPurchaseOrder struct {
 	ExpectedDate     time.Time           `json:"expected_date"`
 	ReceivedDate     *time.Time          `json:"received_date"`
 	TotalAmount      float64             `gorm:"type:decimal(15,2)" json:"total_amount"`
+	AutoGenerated    bool                `gorm:"not null;default:false" json:"auto_generated"` // Propuesta de reaprovisionamiento
+	ApprovedBy       *uint               `json:"approved_by"`
+	ApprovedAt       *time.Time          `json:"approved_at"`
+	SentAt           *time.Time          `json:"sent_at"`
+	CancelledAt      *time.Time          `json:"cancelled_at"`
+	CancelReason     string              `gorm:"type:text" json:"cancel_reason"`
 	CreatedBy        uint                `gorm:"not null" json:"created_by"`
 	CreatedAt        time.Time           `json:"created_at"`
 	UpdatedAt        time.Time           `json:"updated_at"`
@@ -235,6 +356,7 @@ type MrPurchaseOrder struct {
 	// RELACIONES
 	WarehouseAsset MrAsset                   `gorm:"foreignKey:WarehouseAssetID" json:"warehouse_asset"`
 	Creator        MrEmployee                `gorm:"foreignKey:CreatedBy" json:"creator"`
+	Approver       *MrEmployee               `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
 	OrderItems     []MrPurchaseOrderItem     `gorm:"foreignKey:PurchaseOrderID" json:"order_items"`
 	AssetMovements []MrAssetRegisterMovement `gorm:"foreignKey:PurchaseOrderID" json:"asset_movements"`
 }
@@ -290,18 +412,43 @@ const (
 	FrequencyMonths FrequencyType = "months"
 	FrequencyHours  FrequencyType = "hours"
 	FrequencyUses   FrequencyType = "uses"
+
+	// Frecuencias por uso, leídas de contadores de InfluxDB
+	FrequencyOperatingHours FrequencyType = "operating_hours"
+	FrequencyRevolutions    FrequencyType = "revolutions"
+	FrequencyTons           FrequencyType = "tons"
+)
+
+type ConditionComparator string
+
+const (
+	ConditionGreater      ConditionComparator = "gt"
+	ConditionGreaterEqual ConditionComparator = "gte"
+	ConditionLess         ConditionComparator = "lt"
+	ConditionLessEqual    ConditionComparator = "lte"
 )
 
 type WorkOrderStatus string
 
 const (
-	WorkOrderPending    WorkOrderStatus = "pending"
-	WorkOrderScheduled  WorkOrderStatus = "scheduled"
-	WorkOrderInProgress WorkOrderStatus = "in_progress"
-	WorkOrderCompleted  WorkOrderStatus = "completed"
-	WorkOrderCancelled  WorkOrderStatus = "cancelled"
+	WorkOrderRequested  WorkOrderStatus = "requested"   // Solicitada, pendiente de planificar
+	WorkOrderPlanned    WorkOrderStatus = "planned"     // Planificada con fecha y recursos
+	WorkOrderInProgress WorkOrderStatus = "in_progress" // En ejecución
+	WorkOrderOnHold     WorkOrderStatus = "on_hold"     // Detenida (espera de repuestos, parada de línea...)
+	WorkOrderCompleted  WorkOrderStatus = "completed"   // Trabajo terminado
+	WorkOrderVerified   WorkOrderStatus = "verified"    // Verificada por calidad
+	WorkOrderClosed     WorkOrderStatus = "closed"      // Cerrada definitivamente
+	WorkOrderCancelled  WorkOrderStatus = "cancelled"   // Anulada
 )
 
+// FinalWorkOrderStatuses son los estados que ya no admiten cambios; su coste tampoco se recalcula al cambiar las tarifas
+var FinalWorkOrderStatuses = []WorkOrderStatus{WorkOrderVerified, WorkOrderClosed, WorkOrderCancelled}
+
+// IsFinal indica si la orden ya no admite cambios en sus datos, sus detalles ni su material
+func (s WorkOrderStatus) IsFinal() bool {
+	return s == WorkOrderVerified || s == WorkOrderClosed || s == WorkOrderCancelled
+}
+
 type WorkOrderTaskStatus string
 
 const (
@@ -310,6 +457,14 @@ const (
 	TaskCompleted  WorkOrderTaskStatus = "completed"
 )
 
+type ReservationStatus string
+
+const (
+	ReservationReserved ReservationStatus = "reserved" // Pendiente de consumir
+	ReservationIssued   ReservationStatus = "issued"   // Entregada a la OT
+	ReservationReleased ReservationStatus = "released" // Liberada (OT cancelada o cerrada sin consumo)
+)
+
 type PurchaseRequisitionStatus string
 
 const (
@@ -330,16 +485,18 @@ const (
 	AssetMovementTypeTransfer    AssetMovementType = "transfer"    // Transferencia entre almacenes
 	AssetMovementTypeInitial     AssetMovementType = "initial"     // Stock inicial
 	AssetMovementTypeReturn      AssetMovementType = "return"      // Devolución
+	AssetMovementTypeReceipt     AssetMovementType = "receipt"     // Entrada sin pedido de compra
 )
 
 type PurchaseOrderStatus string
 
 const (
 	PurchaseOrderDraft     PurchaseOrderStatus = "draft"
-	PurchaseOrderOrdered   PurchaseOrderStatus = "ordered"
+	PurchaseOrderApproved  PurchaseOrderStatus = "approved"
+	PurchaseOrderOrdered   PurchaseOrderStatus = "ordered" // Enviada al proveedor
+	PurchaseOrderPartial   PurchaseOrderStatus = "partially_received"
 	PurchaseOrderReceived  PurchaseOrderStatus = "received"
 	PurchaseOrderCancelled PurchaseOrderStatus = "cancelled"
-	PurchaseOrderPartial   PurchaseOrderStatus = "partial"
 )
 
 /*
@@ -0,0 +1,51 @@
+package rModels
+
+import "time"
+
+type SapOutboxStatus string
+
+const (
+	SapOutboxPending   SapOutboxStatus = "pending"   // En cola o a la espera del siguiente reintento
+	SapOutboxDelivered SapOutboxStatus = "delivered" // SAP confirmó la recepción
+	SapOutboxDead      SapOutboxStatus = "dead"      // Reintentos agotados, requiere intervención
+	SapOutboxDiscarded SapOutboxStatus = "discarded" // Descartado por un operador
+)
+
+// MrSapOutbox - Mensaje ZPP_IOT_PARADA_LOG pendiente de entregar a SAP. Se crea en la misma transacción que
+// retira el evento confirmado, así que ningún evento enviado se pierde ni se envía dos veces desde la API.
+type MrSapOutbox struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	IdempotencyKey string          `gorm:"size:64;not null;uniqueIndex" json:"idempotency_key"` // Se repite en todos los intentos
+	EventID        uint            `gorm:"not null;index" json:"event_id"`                      // Evento confirmado de origen
+	Factory        string          `gorm:"size:100;not null;index" json:"factory"`
+	ProdLine       string          `gorm:"size:100" json:"prod_line"`
+	Maquina        string          `gorm:"size:50;not null" json:"maquina"` // Código SAP de la línea
+	EventTime      time.Time       `gorm:"not null" json:"event_time"`
+	Estado         string          `gorm:"size:255" json:"estado"`
+	Motivo         string          `gorm:"size:255" json:"motivo"`
+	OrderNumber    string          `gorm:"size:50" json:"order_number"`
+	Operario       string          `gorm:"size:255" json:"operario"`
+	Status         SapOutboxStatus `gorm:"size:20;not null;default:'pending';index:idx_sapoutbox_due" json:"status"`
+	NextAttemptAt  time.Time       `gorm:"not null;index:idx_sapoutbox_due" json:"next_attempt_at"`
+	Attempts       int             `gorm:"not null;default:0" json:"attempts"` // Intentos desde el alta o el último reintento manual
+	LastError      string          `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	DiscardedBy    string          `gorm:"size:255" json:"discarded_by,omitempty"`
+	DiscardReason  string          `gorm:"type:text" json:"discard_reason,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	AttemptLog []MrSapOutboxAttempt `gorm:"foreignKey:OutboxID" json:"attempt_log,omitempty"`
+}
+
+// MrSapOutboxAttempt - Resultado de cada intento de entrega con la respuesta de SAP
+type MrSapOutboxAttempt struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	OutboxID     uint      `gorm:"not null;index" json:"outbox_id"`
+	Attempt      int       `gorm:"not null" json:"attempt"`
+	StatusCode   int       `json:"status_code"` // 0 si no hubo respuesta
+	ResponseBody string    `gorm:"type:text" json:"response_body"`
+	Error        string    `gorm:"type:text" json:"error"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -6,9 +6,12 @@ import (
 	"github.com/remrafvil/Auriga_API/internal/repositories/rDocuments"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rEvents"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rInfluxQuery"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rInventory"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rLabor"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rLabor_KKK"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rLineOrders"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rLive"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rProducts"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rUsers"
 	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
@@ -30,5 +33,8 @@ var Module = fx.Module("repositories", fx.Provide(
 	rInfluxQuery.New,
 	rLabor.New,
 	rLabor_KKK.New,
+	rMaintenance.New,
+	rInventory.New,
 	rwWorkera.New,
+	rLive.New,
 ))
@@ -0,0 +1,100 @@
+package riInfluxdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// counterLookback limita la búsqueda de la última lectura de un contador
+const counterLookback = 30 * 24 * time.Hour
+
+// GetCounterValue obtiene la última lectura de un contador acumulado (horas, vueltas, toneladas...)
+// de una línea. path contiene los niveles EL_Lv1, EL_Lv2 y EL_Lv3 que identifican el contador.
+func (m *repository) GetCounterValue(factory string, lineCode string, path []string, field string) (FieldValue, error) {
+	logger := m.logger.With(
+		zap.String("factory", factory),
+		zap.String("lineCode", lineCode),
+		zap.Strings("path", path),
+		zap.String("field", field),
+	)
+
+	filters, err := levelFilters(path)
+	if err != nil {
+		return FieldValue{}, err
+	}
+
+	client, connectionName, err := m.getClient(factory)
+	if err != nil {
+		return FieldValue{}, fmt.Errorf("failed to get InfluxDB client for factory %s: %w", factory, err)
+	}
+
+	cfg, exists := m.influxManager.GetConfig(connectionName)
+	if !exists {
+		return FieldValue{}, fmt.Errorf("configuración no encontrada para conexión: %s", connectionName)
+	}
+
+	query := fmt.Sprintf(
+		`from(bucket: "%s")
+		|> range(start: -%s)
+		|> filter(fn: (r) => r["name"] == "%s")%s
+		|> filter(fn: (r) => r["_field"] == "%s")
+		|> last()`,
+		cfg.Bucket,
+		counterLookback.String(),
+		lineCode,
+		filters,
+		field)
+
+	logger.Debug("Executing InfluxDB query for counter", zap.String("query", query))
+
+	queryAPI := client.QueryAPI(cfg.Org)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := queryAPI.Query(ctx, query)
+	if err != nil {
+		logger.Error("Failed to execute InfluxDB query for counter", zap.Error(err))
+		return FieldValue{}, fmt.Errorf("failed to query InfluxDB counter %s: %w", field, err)
+	}
+	defer result.Close()
+
+	for result.Next() {
+		record := result.Record()
+		switch val := record.Value().(type) {
+		case float64:
+			return FieldValue{Value: val, Time: record.Time()}, nil
+		case int64:
+			return FieldValue{Value: float64(val), Time: record.Time()}, nil
+		case uint64:
+			return FieldValue{Value: float64(val), Time: record.Time()}, nil
+		default:
+			logger.Warn("Unexpected value type in counter data",
+				zap.String("type", fmt.Sprintf("%T", record.Value())))
+		}
+	}
+	if result.Err() != nil {
+		return FieldValue{}, fmt.Errorf("error processing counter query results: %w", result.Err())
+	}
+
+	return FieldValue{}, fmt.Errorf("no data for counter %s on line %s", field, lineCode)
+}
+
+// levelFilters construye los filtros Flux de EL_Lv1, EL_Lv2 y EL_Lv3 a partir de la ruta de la señal
+func levelFilters(path []string) (string, error) {
+	if len(path) > 3 {
+		return "", fmt.Errorf("signal path has %d levels, maximum is 3", len(path))
+	}
+
+	var filters strings.Builder
+	for i, level := range path {
+		if level == "" {
+			continue
+		}
+		fmt.Fprintf(&filters, "\n\t\t|> filter(fn: (r) => r[\"EL_Lv%d\"] == \"%s\")", i+1, level)
+	}
+	return filters.String(), nil
+}
@@ -0,0 +1,88 @@
+package riInfluxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetFieldValues obtiene las lecturas de una señal de línea en el intervalo indicado, ordenadas por tiempo
+func (m *repository) GetFieldValues(factory string, lineCode string, path []string, field string, start time.Time, stop time.Time) ([]FieldValue, error) {
+	logger := m.logger.With(
+		zap.String("factory", factory),
+		zap.String("lineCode", lineCode),
+		zap.Strings("path", path),
+		zap.String("field", field),
+	)
+
+	filters, err := levelFilters(path)
+	if err != nil {
+		return nil, err
+	}
+
+	client, connectionName, err := m.getClient(factory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get InfluxDB client for factory %s: %w", factory, err)
+	}
+
+	cfg, exists := m.influxManager.GetConfig(connectionName)
+	if !exists {
+		return nil, fmt.Errorf("configuración no encontrada para conexión: %s", connectionName)
+	}
+
+	query := fmt.Sprintf(
+		`from(bucket: "%s")
+		|> range(start: %s, stop: %s)
+		|> filter(fn: (r) => r["name"] == "%s")%s
+		|> filter(fn: (r) => r["_field"] == "%s")
+		|> group()
+		|> sort(columns: ["_time"])`,
+		cfg.Bucket,
+		start.Format(time.RFC3339),
+		stop.Format(time.RFC3339),
+		lineCode,
+		filters,
+		field)
+
+	logger.Debug("Executing InfluxDB query for field values", zap.String("query", query))
+
+	queryAPI := client.QueryAPI(cfg.Org)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := queryAPI.Query(ctx, query)
+	if err != nil {
+		logger.Error("Failed to execute InfluxDB query for field values", zap.Error(err))
+		return nil, fmt.Errorf("failed to query InfluxDB field %s: %w", field, err)
+	}
+	defer result.Close()
+
+	var values []FieldValue
+	for result.Next() {
+		record := result.Record()
+		switch val := record.Value().(type) {
+		case float64:
+			values = append(values, FieldValue{Value: val, Time: record.Time()})
+		case int64:
+			values = append(values, FieldValue{Value: float64(val), Time: record.Time()})
+		case uint64:
+			values = append(values, FieldValue{Value: float64(val), Time: record.Time()})
+		case bool:
+			value := 0.0
+			if val {
+				value = 1
+			}
+			values = append(values, FieldValue{Value: value, Time: record.Time()})
+		default:
+			logger.Warn("Unexpected value type in field data",
+				zap.String("type", fmt.Sprintf("%T", record.Value())))
+		}
+	}
+	if result.Err() != nil {
+		return values, fmt.Errorf("error processing field query results: %w", result.Err())
+	}
+
+	return values, nil
+}
@@ -15,6 +15,9 @@ type Repository interface {
 	GetLineStatus(factory string, lineCode string) (string, float64, error)
 	GetLinesStatus(factory string, lineCodes []string) (map[string]LineStatusResponse, error)
 	GetLineThroughput(factory string, lineCode string, startTime time.Time, stopTime time.Time, windowPeriod string) ([]ThroughputData, error)
+	GetCounterValue(factory string, lineCode string, path []string, field string) (FieldValue, error)
+	GetFieldValues(factory string, lineCode string, path []string, field string, start time.Time, stop time.Time) ([]FieldValue, error)
+	GetStatusWords(factory string, fields []string, start time.Time, stop time.Time) ([]StatusWord, error)
 }
 
 // LineStatusResponse representa el estado de una línea
@@ -32,6 +35,12 @@ type ThroughputData struct {
 	LineCode string    `json:"line_code"`
 }
 
+// FieldValue representa una lectura de un campo de InfluxDB
+type FieldValue struct {
+	Value float64   `json:"value"`
+	Time  time.Time `json:"time"`
+}
+
 type repository struct {
 	influxManager *databases.InfluxClientManager
 	logger        *zap.Logger
@@ -0,0 +1,120 @@
+package riInfluxdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StatusWord lectura de una palabra de estado con la jerarquía de la señal que la emite
+type StatusWord struct {
+	Time    time.Time
+	Value   int
+	Field   string
+	Name    string // Línea
+	EL_Lv1  string
+	EL_Lv2  string
+	EL_Lv3  string
+	Host    string
+	SlaveID string
+	Type    string
+}
+
+// GetStatusWords obtiene las lecturas de los campos de estado de todas las líneas de la fábrica en el
+// intervalo indicado, agrupadas por señal y ordenadas por tiempo dentro de cada una
+func (m *repository) GetStatusWords(factory string, fields []string, start time.Time, stop time.Time) ([]StatusWord, error) {
+	logger := m.logger.With(
+		zap.String("factory", factory),
+		zap.Strings("fields", fields),
+	)
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no status fields configured")
+	}
+
+	client, connectionName, err := m.getClient(factory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get InfluxDB client for factory %s: %w", factory, err)
+	}
+
+	cfg, exists := m.influxManager.GetConfig(connectionName)
+	if !exists {
+		return nil, fmt.Errorf("configuración no encontrada para conexión: %s", connectionName)
+	}
+
+	conditions := make([]string, 0, len(fields))
+	for _, field := range fields {
+		conditions = append(conditions, fmt.Sprintf(`r["_field"] == "%s"`, field))
+	}
+
+	query := fmt.Sprintf(
+		`from(bucket: "%s")
+		|> range(start: %s, stop: %s)
+		|> filter(fn: (r) => %s)
+		|> group(columns: ["name", "EL_Lv1", "EL_Lv2", "EL_Lv3", "_field"])
+		|> sort(columns: ["_time"])`,
+		cfg.Bucket,
+		start.Format(time.RFC3339),
+		stop.Format(time.RFC3339),
+		strings.Join(conditions, " or "))
+
+	logger.Debug("Executing InfluxDB query for status words", zap.String("query", query))
+
+	queryAPI := client.QueryAPI(cfg.Org)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := queryAPI.Query(ctx, query)
+	if err != nil {
+		logger.Error("Failed to execute InfluxDB query for status words", zap.Error(err))
+		return nil, fmt.Errorf("failed to query InfluxDB status words: %w", err)
+	}
+	defer result.Close()
+
+	var words []StatusWord
+	for result.Next() {
+		record := result.Record()
+		var value int
+		switch val := record.Value().(type) {
+		case float64:
+			value = int(val)
+		case int64:
+			value = int(val)
+		case uint64:
+			value = int(val)
+		case bool:
+			if val {
+				value = 1
+			}
+		default:
+			logger.Warn("Unexpected value type in status word data",
+				zap.String("type", fmt.Sprintf("%T", record.Value())))
+			continue
+		}
+
+		tag := func(key string) string {
+			text, _ := record.ValueByKey(key).(string)
+			return text
+		}
+		words = append(words, StatusWord{
+			Time:    record.Time(),
+			Value:   value,
+			Field:   record.Field(),
+			Name:    tag("name"),
+			EL_Lv1:  tag("EL_Lv1"),
+			EL_Lv2:  tag("EL_Lv2"),
+			EL_Lv3:  tag("EL_Lv3"),
+			Host:    tag("host"),
+			SlaveID: tag("slave_id"),
+			Type:    tag("type"),
+		})
+	}
+	if result.Err() != nil {
+		return words, fmt.Errorf("error processing status word query results: %w", result.Err())
+	}
+
+	return words, nil
+}
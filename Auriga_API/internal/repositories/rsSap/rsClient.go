@@ -0,0 +1,233 @@
+package rsSap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/config"
+	"go.uber.org/zap"
+)
+
+const (
+	rfcNamespace     = "urn:sap-com:document:sap:rfc:functions"
+	xmlDeclaration   = `<?xml version="1.0" encoding="UTF-8"?>`
+	defaultTimeout   = 30 * time.Second
+	defaultRetryWait = time.Second
+	logBodyLimit     = 4 << 10
+)
+
+// Request parámetros de una función RFC ZPP_IOT_*. El cliente construye el elemento raíz con el nombre
+// de la función y el espacio de nombres RFC, así que los tipos solo declaran los parámetros.
+type Request interface {
+	Function() string
+	// Retryable indica que la función solo consulta datos y se puede repetir tras un error transitorio.
+	// Los envíos no se repiten: un timeout no garantiza que SAP no los haya registrado.
+	Retryable() bool
+}
+
+// SapResponse respuesta de SAP a una llamada, se guarda en cada intento de la cola de salida
+type SapResponse struct {
+	StatusCode int
+	Body       string
+}
+
+// Client cliente único de las funciones RFC publicadas por SAP en la URL de config.Settings.Sap
+type Client struct {
+	config config.SapDBConfig
+	http   *http.Client
+	logger *zap.Logger
+}
+
+// NewClient crea el cliente; transport permite sustituir la red por un SAP simulado en las pruebas y
+// con nil se usa http.DefaultTransport
+func NewClient(cfg config.SapDBConfig, logger *zap.Logger, transport http.RoundTripper) *Client {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Client{
+		config: cfg,
+		http:   &http.Client{Transport: transport},
+		logger: logger.Named("sap"),
+	}
+}
+
+// Call envía la función a SAP y decodifica la respuesta en response si no es nil. headers añade cabeceras
+// a la petición, por ejemplo la clave de idempotencia. El plazo de ctx prevalece sobre el timeout
+// configurado. Los fallos se devuelven como *FaultError o *StatusError, o envolviendo ErrSapUnavailable
+// si no hubo respuesta; la respuesta se devuelve también en los errores para registrarla.
+func (c *Client) Call(ctx context.Context, request Request, response interface{}, headers map[string]string) (SapResponse, error) {
+	function := request.Function()
+	body, err := marshalRequest(request)
+	if err != nil {
+		return SapResponse{}, fmt.Errorf("marshal %s: %w", function, err)
+	}
+
+	retries := 0
+	if request.Retryable() && c.config.Retries > 0 {
+		retries = c.config.Retries
+	}
+	wait := c.config.RetryWait
+	if wait <= 0 {
+		wait = defaultRetryWait
+	}
+
+	var result SapResponse
+	for attempt := 0; ; attempt++ {
+		result, err = c.do(ctx, function, body, headers, attempt)
+		if err == nil || attempt >= retries || !IsTransient(err) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(wait << attempt):
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if response != nil {
+		if err := xml.Unmarshal([]byte(result.Body), response); err != nil {
+			return result, fmt.Errorf("unmarshal %s response: %w", function, err)
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) do(ctx context.Context, function string, body []byte, headers map[string]string, attempt int) (SapResponse, error) {
+	var result SapResponse
+
+	timeout := c.config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Url, bytes.NewReader(body))
+	if err != nil {
+		return result, fmt.Errorf("build %s request: %w", function, err)
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Authorization", "Basic "+c.config.Auth)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	fields := []zap.Field{
+		zap.String("function", function),
+		zap.String("url", redactURL(c.config.Url)),
+		zap.Int("attempt", attempt+1),
+	}
+	c.logger.Debug("SAP request", append(fields,
+		zap.Any("headers", redactHeaders(req.Header)),
+		zap.String("body", c.redact(string(body))))...)
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	fields = append(fields, zap.Duration("duration", time.Since(start)))
+	if err != nil {
+		err = c.redactError(err)
+		c.logger.Warn("SAP call failed", append(fields, zap.Error(err))...)
+		return result, fmt.Errorf("%w: %s: %v", ErrSapUnavailable, function, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	result.StatusCode = resp.StatusCode
+	result.Body = string(data)
+	if err != nil {
+		c.logger.Warn("SAP response read failed", append(fields, zap.Int("status_code", resp.StatusCode), zap.Error(err))...)
+		return result, fmt.Errorf("%w: %s: read response: %v", ErrSapUnavailable, function, err)
+	}
+
+	fields = append(fields, zap.Int("status_code", resp.StatusCode))
+	c.logger.Debug("SAP response", append(fields, zap.String("body", c.redact(limit(result.Body, logBodyLimit))))...)
+
+	if fault := parseFault(function, resp.StatusCode, data); fault != nil {
+		c.logger.Warn("SAP returned a fault", append(fields, zap.String("code", fault.Code), zap.String("message", fault.Message))...)
+		return result, fault
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		c.logger.Warn("SAP returned an error status", fields...)
+		return result, &StatusError{Function: function, StatusCode: resp.StatusCode, Body: limit(result.Body, logBodyLimit)}
+	}
+	c.logger.Info("SAP call completed", fields...)
+	return result, nil
+}
+
+// marshalRequest envuelve los parámetros en el elemento de la función con la declaración XML
+func marshalRequest(request Request) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xmlDeclaration)
+	start := xml.StartElement{
+		Name: xml.Name{Local: "ns1:" + request.Function()},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns:ns1"}, Value: rfcNamespace}},
+	}
+	if err := xml.NewEncoder(&buf).EncodeElement(request, start); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// redact oculta las credenciales, de la cabecera o de la URL, si aparecen en el texto que se registra
+func (c *Client) redact(text string) string {
+	if c.config.Auth != "" {
+		text = strings.ReplaceAll(text, c.config.Auth, "[REDACTED]")
+	}
+	if u, err := url.Parse(c.config.Url); err == nil && u.User != nil {
+		if password, ok := u.User.Password(); ok && password != "" {
+			text = strings.ReplaceAll(text, password, "xxxxx")
+		}
+	}
+	return text
+}
+
+// redactError oculta las credenciales de la URL que net/http incluye en sus errores
+func (c *Client) redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+	return errors.New(c.redact(err.Error()))
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	values := make(map[string]string, len(header))
+	for name := range header {
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization", "Proxy-Authorization", "Cookie":
+			values[name] = "[REDACTED]"
+		default:
+			values[name] = header.Get(name)
+		}
+	}
+	return values
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "[invalid url]"
+	}
+	return u.Redacted()
+}
+
+func limit(text string, size int) string {
+	if len(text) <= size {
+		return text
+	}
+	return text[:size] + "..."
+}
@@ -0,0 +1,116 @@
+package rsSap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrSapUnavailable SAP no respondió: error de red, timeout o cancelación
+var ErrSapUnavailable = errors.New("SAP unavailable")
+
+// FaultError fallo SOAP o excepción RFC devuelta por SAP
+type FaultError struct {
+	Function   string
+	StatusCode int
+	Code       string // faultcode SOAP o TYPE/ID del mensaje RFC
+	Message    string
+}
+
+func (e *FaultError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("SAP fault in %s (status %d): %s", e.Function, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("SAP fault in %s (status %d, %s): %s", e.Function, e.StatusCode, e.Code, e.Message)
+}
+
+// StatusError respuesta HTTP no 2xx sin un fallo SAP reconocible en el cuerpo
+type StatusError struct {
+	Function   string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("SAP returned status %d for %s", e.StatusCode, e.Function)
+}
+
+// IsTransient indica si el error puede desaparecer al repetir la llamada: sin respuesta, 408, 429 o 5xx
+// sin fallo SAP. Los fallos SOAP y RFC son respuestas de la función y no se repiten.
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrSapUnavailable) {
+		return true
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusRequestTimeout ||
+			statusErr.StatusCode == http.StatusTooManyRequests ||
+			statusErr.StatusCode >= 500
+	}
+	return false
+}
+
+type soapFault struct {
+	Code   string `xml:"faultcode"`
+	String string `xml:"faultstring"`
+	// SOAP 1.2
+	CodeValue  string `xml:"Code>Value"`
+	ReasonText string `xml:"Reason>Text"`
+}
+
+type rfcMessage struct {
+	Type    string `xml:"TYPE"`
+	ID      string `xml:"ID"`
+	Number  string `xml:"NUMBER"`
+	Message string `xml:"MESSAGE"`
+}
+
+type rfcReturn struct {
+	rfcMessage
+	Items []rfcMessage `xml:"item"`
+}
+
+// parseFault busca en la respuesta un Fault SOAP o un mensaje RFC de tipo E o A en RETURN, ET_RETURN o
+// ES_RETURN; devuelve nil si no lo hay
+func parseFault(function string, statusCode int, body []byte) *FaultError {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch name := start.Name.Local; {
+		case name == "Fault":
+			var fault soapFault
+			if err := decoder.DecodeElement(&fault, &start); err != nil {
+				return nil
+			}
+			code, message := fault.Code, fault.String
+			if code == "" && message == "" {
+				code, message = fault.CodeValue, fault.ReasonText
+			}
+			return &FaultError{Function: function, StatusCode: statusCode, Code: strings.TrimSpace(code), Message: strings.TrimSpace(message)}
+		case name == "RETURN" || strings.HasSuffix(name, "_RETURN"):
+			var ret rfcReturn
+			if err := decoder.DecodeElement(&ret, &start); err != nil {
+				return nil
+			}
+			for _, msg := range append([]rfcMessage{ret.rfcMessage}, ret.Items...) {
+				if msg.Type == "E" || msg.Type == "A" {
+					code := msg.Type
+					if msg.ID != "" {
+						code = fmt.Sprintf("%s %s/%s", msg.Type, msg.ID, msg.Number)
+					}
+					return &FaultError{Function: function, StatusCode: statusCode, Code: code, Message: strings.TrimSpace(msg.Message)}
+				}
+			}
+		}
+	}
+}
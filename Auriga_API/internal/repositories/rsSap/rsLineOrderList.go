@@ -1,22 +1,19 @@
 package rsSap
 
 import (
+	"context"
 	"log"
 
-	"encoding/xml"
-
-	"fmt"
-
-	"github.com/go-resty/resty/v2"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
 )
 
 type ZPP_IOT_ORDER_SEQUENCE struct {
-	XMLName  xml.Name `xml:"ns1:ZPP_IOT_ORDER_SEQUENCE"`
-	Xmlns    string   `xml:"xmlns:ns1,attr"`
-	IV_LINEA string   `xml:"IV_LINEA"`
+	IV_LINEA string `xml:"IV_LINEA"`
 }
 
+func (ZPP_IOT_ORDER_SEQUENCE) Function() string { return "ZPP_IOT_ORDER_SEQUENCE" }
+func (ZPP_IOT_ORDER_SEQUENCE) Retryable() bool  { return true }
+
 type OrderSequence struct {
 	ET_ORDERS struct {
 		Item []struct {
@@ -40,41 +37,11 @@ func (m *repository) RsLineOrderList(factory string, lineNumber string, lineSapC
 
 	var data = make([]rModels.MrProductionOrder, 0)
 
-	url := m.config.Sap.Url
-	auth := m.config.Sap.Auth
-	// url := "https://l20163-iflmap.hcisbp.eu1.hana.ondemand.com/http/SAP_RFC_IOT_DHM"
-	// auth := "czAwMjA2OTUzMjI6MUxhbnRlcltd"
-
 	z := ZPP_IOT_ORDER_SEQUENCE{
-		Xmlns:    "urn:sap-com:document:sap:rfc:functions",
 		IV_LINEA: lineSapCode,
 	}
-	xmlBytes, err := xml.Marshal(z)
-	if err != nil {
-		fmt.Println(err)
-		return data, err
-	}
-	// Add the XML declaration
-	xmlDecl := []byte(`<?xml version="1.0" encoding="UTF-8"?>`)
-	xmlBytes = append(xmlDecl, xmlBytes...)
-	xmlStr := string(xmlBytes)
-
-	client := resty.New()
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/xml").
-		SetHeader("Authorization", "Basic "+auth).
-		SetBody(xmlStr).
-		Post(url)
-	if err != nil {
-		fmt.Println("api failure", err)
-		return data, err
-	}
-
-	body := resp.Body()
 	dataS := &OrderSequence{}
-	err = xml.Unmarshal(body, dataS)
-	if err != nil {
-		fmt.Println("Unmarshal Error - ", err)
+	if _, err := m.client.Call(context.Background(), z, dataS, nil); err != nil {
 		return data, err
 	}
 
@@ -0,0 +1,28 @@
+package rsSap
+
+import (
+	"context"
+)
+
+//API POST para enviar a SAP el consumo de un componente en una orden de fabricación
+
+type ZPP_IOT_CONSUMOS_ORDEN struct {
+	IV_WERKS         string `xml:"IV_WERKS"`   //centro de fabricación
+	IV_AUFNR         string `xml:"IV_AUFNR"`   //orden de fabricación
+	IV_ARBPL         string `xml:"IV_ARBPL"`   //puesto de trabajo (línea)
+	IV_EXIDV         string `xml:"IV_EXIDV"`   //dosificador
+	IV_ID_SILO       string `xml:"IV_ID_SILO"` //silo
+	IV_OPERARIO      string `xml:"IV_OPERARIO"`
+	IV_TURNO         string `xml:"IV_TURNO"`
+	IV_MATNR         string `xml:"IV_MATNR"` //componente
+	IV_TIMESTAMP_INI string `xml:"IV_TIMESTAMP_INI"`
+	IV_TIMESTAMP_FIN string `xml:"IV_TIMESTAMP_FIN"`
+}
+
+func (ZPP_IOT_CONSUMOS_ORDEN) Function() string { return "ZPP_IOT_CONSUMOS_ORDEN" }
+func (ZPP_IOT_CONSUMOS_ORDEN) Retryable() bool  { return false }
+
+// RsOrderConsumption envía el consumo; una respuesta que no sea 2xx se devuelve como error junto al cuerpo
+func (m *repository) RsOrderConsumption(ctx context.Context, consumption ZPP_IOT_CONSUMOS_ORDEN) (SapResponse, error) {
+	return m.client.Call(ctx, consumption, nil, nil)
+}
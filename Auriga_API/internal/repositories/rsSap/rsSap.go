@@ -3,28 +3,38 @@ package rsSap
 // lo llamaremso repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/remrafvil/Auriga_API/config"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type Repository interface {
-	rsFactoryLineList()
+	RsFactoryLineList(factorySapCode string, language string) ([]MrsFactoryLine, error)
 	RsLineOrderList(factory string, lineNumber string, lineSapCode string) ([]rModels.MrProductionOrder, error)
 	RsLineRecipe(lineNumber string) ([]MrsComponent, error)
-	RsLineStopEvent(maquina string, timeEvent time.Time, estado string, motivo string, of string, operario string) error
+	RsLineStopEvent(ctx context.Context, maquina string, timeEvent time.Time, estado string, motivo string, of string, operario string, idempotencyKey string) (SapResponse, error)
+	RsOrderConsumption(ctx context.Context, consumption ZPP_IOT_CONSUMOS_ORDEN) (SapResponse, error)
 }
 
 type repository struct {
 	db     *gorm.DB
 	config *config.Settings
+	client *Client
 }
 
-func New(db *gorm.DB, cfg *config.Settings) Repository {
+func New(db *gorm.DB, cfg *config.Settings, logger *zap.Logger) Repository {
+	return NewWithClient(db, cfg, NewClient(cfg.Sap, logger, nil))
+}
+
+// NewWithClient crea el repositorio sobre un cliente ya construido, por ejemplo con un transporte simulado
+func NewWithClient(db *gorm.DB, cfg *config.Settings, client *Client) Repository {
 	return &repository{
 		db:     db,
 		config: cfg,
+		client: client,
 	}
 }
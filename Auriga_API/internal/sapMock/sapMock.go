@@ -0,0 +1,299 @@
+package sapMock
+
+// SAP simulado para desarrollo y pruebas: atiende las funciones RFC ZPP_IOT_* que usa rsSap con datos
+// de ficheros, guarda los mensajes recibidos y permite inyectar fallos y latencia
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrDropped la conexión se cortó sin respuesta por un fallo inyectado
+var ErrDropped = errors.New("sap mock: connection dropped")
+
+// Message llamada recibida por el SAP simulado
+type Message struct {
+	Function       string            `json:"Function"`
+	ReceivedAt     time.Time         `json:"ReceivedAt"`
+	Params         map[string]string `json:"Params"`
+	IdempotencyKey string            `json:"IdempotencyKey,omitempty"`
+	Body           string            `json:"Body"`
+	StatusCode     int               `json:"StatusCode"` // 0 si la conexión se cortó
+}
+
+// Fault fallo inyectado en las siguientes llamadas. Con solo Latency la llamada responde con normalidad
+// tras la espera.
+type Fault struct {
+	Function   string        // Vacío afecta a todas las funciones
+	Times      int           // Llamadas afectadas; 0 hasta que se borren los fallos
+	Latency    time.Duration // Espera antes de responder
+	StatusCode int           // Respuesta HTTP de error; con SoapFault vale 500 si no se indica
+	SoapFault  string        // faultstring de un Fault SOAP
+	RfcError   string        // Mensaje tipo E en ET_RETURN con status 200
+	Drop       bool          // Corta la conexión sin responder
+}
+
+// Server SAP simulado; implementa http.Handler para servirlo por red y Transport para usarlo en proceso
+type Server struct {
+	fixtures *Fixtures
+	auth     string // Basic auth esperada en base64; vacía acepta cualquiera
+	logger   *zap.Logger
+
+	mu       sync.Mutex
+	latency  time.Duration
+	faults   []*Fault
+	messages []Message
+}
+
+func New(fixtures *Fixtures, auth string, logger *zap.Logger) *Server {
+	if fixtures == nil {
+		fixtures = &Fixtures{}
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Server{
+		fixtures: fixtures,
+		auth:     auth,
+		logger:   logger,
+	}
+}
+
+// SetLatency espera aplicada a todas las llamadas, además de la de los fallos
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// Faults fallos pendientes, con las llamadas que les quedan en Times
+func (s *Server) Faults() []Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	faults := make([]Fault, 0, len(s.faults))
+	for _, fault := range s.faults {
+		faults = append(faults, *fault)
+	}
+	return faults
+}
+
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Messages llamadas recibidas de la función, o de todas si function está vacío, en orden de llegada
+func (s *Server) Messages(function string) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := make([]Message, 0, len(s.messages))
+	for _, msg := range s.messages {
+		if function == "" || msg.Function == function {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+func (s *Server) ClearMessages() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+}
+
+// Reset borra mensajes, fallos y latencia
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+	s.faults = nil
+	s.latency = 0
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, controlPrefix) {
+		s.serveControl(w, r)
+		return
+	}
+	if err := s.handle(w, r); errors.Is(err, ErrDropped) {
+		// Aborta la respuesta y cierra la conexión sin escribir nada
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// Transport permite usar el SAP simulado en proceso como transporte del cliente de rsSap
+func (s *Server) Transport() http.RoundTripper {
+	return transport{server: s}
+}
+
+type transport struct {
+	server *Server
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	rec := httptest.NewRecorder()
+	if err := t.server.handle(rec, req); err != nil {
+		return nil, err
+	}
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// handle atiende una llamada RFC; devuelve error si no se debe responder
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil
+	}
+	if s.auth != "" && r.Header.Get("Authorization") != "Basic "+s.auth {
+		w.Header().Set("WWW-Authenticate", `Basic realm="SAP"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	function, params, err := parseRequest(body)
+	if err != nil {
+		writeSoapFault(w, http.StatusInternalServerError, "soap:Client", err.Error())
+		return nil
+	}
+
+	msg := Message{
+		Function:       function,
+		ReceivedAt:     time.Now(),
+		Params:         params,
+		IdempotencyKey: r.Header.Get("Idempotency-Key"),
+		Body:           string(body),
+	}
+	fault, latency := s.nextFault(function)
+	s.logger.Info("SAP mock call", zap.String("function", function), zap.Any("params", params), zap.Bool("fault", fault != nil))
+
+	if latency > 0 {
+		if err := sleep(r.Context(), latency); err != nil {
+			s.record(msg)
+			return err
+		}
+	}
+
+	rec := &statusRecorder{ResponseWriter: w}
+	switch {
+	case fault != nil && fault.Drop:
+		s.record(msg)
+		return ErrDropped
+	case fault != nil && fault.SoapFault != "":
+		status := fault.StatusCode
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		writeSoapFault(rec, status, "soap:Server", fault.SoapFault)
+	case fault != nil && fault.RfcError != "":
+		writeResponse(rec, function, rfcResult{Return: []rfcMessage{rfcError(fault.RfcError)}})
+	case fault != nil && fault.StatusCode != 0:
+		http.Error(rec, "injected fault", fault.StatusCode)
+	default:
+		s.dispatch(rec, function, params)
+	}
+	msg.StatusCode = rec.status
+	s.record(msg)
+	return nil
+}
+
+// nextFault primer fallo pendiente para la función, descontando la llamada; devuelve también la espera total
+func (s *Server) nextFault(function string) (*Fault, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latency := s.latency
+	for i, fault := range s.faults {
+		if fault.Function != "" && fault.Function != function {
+			continue
+		}
+		matched := *fault
+		if fault.Times > 0 {
+			if fault.Times--; fault.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched, latency + matched.Latency
+	}
+	return nil, latency
+}
+
+func (s *Server) record(msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+}
+
+// parseRequest lee el nombre de la función del elemento raíz y sus parámetros simples
+func parseRequest(body []byte) (string, map[string]string, error) {
+	var root struct {
+		XMLName xml.Name
+		Params  []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := xml.Unmarshal(body, &root); err != nil {
+		return "", nil, fmt.Errorf("invalid RFC request: %v", err)
+	}
+	if root.XMLName.Space != rfcNamespace {
+		return "", nil, fmt.Errorf("invalid RFC namespace %q", root.XMLName.Space)
+	}
+	params := make(map[string]string, len(root.Params))
+	for _, p := range root.Params {
+		params[p.XMLName.Local] = strings.TrimSpace(p.Value)
+	}
+	return root.XMLName.Local, params, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(data)
+}
@@ -0,0 +1,122 @@
+package sapMock
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Rutas de control para usar el SAP simulado desde fuera del proceso:
+//
+//	GET    /_mock/messages?function=ZPP_IOT_PARADA_LOG  mensajes recibidos
+//	DELETE /_mock/messages                              borra los mensajes
+//	GET    /_mock/faults                                fallos pendientes
+//	POST   /_mock/faults                                añade un fallo (faultRequest)
+//	DELETE /_mock/faults                                borra los fallos
+//	PUT    /_mock/latency                               {"latency": "2s"} para todas las llamadas
+//	POST   /_mock/reset                                 borra mensajes, fallos y latencia
+const controlPrefix = "/_mock/"
+
+type responseMessage struct {
+	Message string `json:"message"`
+}
+
+// faultRequest Fault con la latencia como duración de Go ("500ms", "2s")
+type faultRequest struct {
+	Function   string `json:"function"`
+	Times      int    `json:"times"`
+	Latency    string `json:"latency"`
+	StatusCode int    `json:"status_code"`
+	SoapFault  string `json:"soap_fault"`
+	RfcError   string `json:"rfc_error"`
+	Drop       bool   `json:"drop"`
+}
+
+func (s *Server) serveControl(w http.ResponseWriter, r *http.Request) {
+	switch r.Method + " " + r.URL.Path {
+	case "GET /_mock/messages":
+		writeJSON(w, http.StatusOK, s.Messages(r.URL.Query().Get("function")))
+	case "DELETE /_mock/messages":
+		s.ClearMessages()
+		w.WriteHeader(http.StatusNoContent)
+	case "GET /_mock/faults":
+		writeJSON(w, http.StatusOK, s.faultRequests())
+	case "POST /_mock/faults":
+		var req faultRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, responseMessage{Message: "invalid fault: " + err.Error()})
+			return
+		}
+		latency, ok := parseLatency(w, req.Latency)
+		if !ok {
+			return
+		}
+		s.InjectFault(Fault{
+			Function:   req.Function,
+			Times:      req.Times,
+			Latency:    latency,
+			StatusCode: req.StatusCode,
+			SoapFault:  req.SoapFault,
+			RfcError:   req.RfcError,
+			Drop:       req.Drop,
+		})
+		writeJSON(w, http.StatusCreated, s.faultRequests())
+	case "DELETE /_mock/faults":
+		s.ClearFaults()
+		w.WriteHeader(http.StatusNoContent)
+	case "PUT /_mock/latency":
+		var req struct {
+			Latency string `json:"latency"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, responseMessage{Message: "invalid latency: " + err.Error()})
+			return
+		}
+		latency, ok := parseLatency(w, req.Latency)
+		if !ok {
+			return
+		}
+		s.SetLatency(latency)
+		w.WriteHeader(http.StatusNoContent)
+	case "POST /_mock/reset":
+		s.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeJSON(w, http.StatusNotFound, responseMessage{Message: "unknown control route"})
+	}
+}
+
+func (s *Server) faultRequests() []faultRequest {
+	faults := s.Faults()
+	data := make([]faultRequest, 0, len(faults))
+	for _, f := range faults {
+		data = append(data, faultRequest{
+			Function:   f.Function,
+			Times:      f.Times,
+			Latency:    f.Latency.String(),
+			StatusCode: f.StatusCode,
+			SoapFault:  f.SoapFault,
+			RfcError:   f.RfcError,
+			Drop:       f.Drop,
+		})
+	}
+	return data
+}
+
+func parseLatency(w http.ResponseWriter, value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, true
+	}
+	latency, err := time.ParseDuration(value)
+	if err != nil || latency < 0 {
+		writeJSON(w, http.StatusBadRequest, responseMessage{Message: "invalid latency: " + value})
+		return 0, false
+	}
+	return latency, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
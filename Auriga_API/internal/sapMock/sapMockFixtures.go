@@ -0,0 +1,95 @@
+package sapMock
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// Ficheros de datos; los que falten se dejan vacíos
+const (
+	linesFile      = "lines_factory.json"
+	ordersFile     = "order_sequence.json"
+	componentsFile = "order_components.json"
+)
+
+//go:embed fixtures/*.json
+var defaultFixtures embed.FS
+
+// Fixtures datos que devuelven las consultas, con los nombres de campo de SAP
+type Fixtures struct {
+	Lines      map[string][]Line      // Centro (IV_WERKS) → líneas
+	Orders     map[string][]Order     // Puesto de trabajo (IV_LINEA) → secuencia de órdenes
+	Components map[string][]Component // Orden (IV_AUFNR) → componentes
+}
+
+type Line struct {
+	ARBPL string `json:"ARBPL" xml:"ARBPL"`
+	KTEXT string `json:"KTEXT" xml:"KTEXT"`
+}
+
+type Order struct {
+	AUFNR    string `json:"AUFNR" xml:"AUFNR"`
+	AUART    string `json:"AUART" xml:"AUART"`
+	MATNR    string `json:"MATNR" xml:"MATNR"`
+	MAKTX    string `json:"MAKTX" xml:"MAKTX"`
+	GSTRI    string `json:"GSTRI" xml:"GSTRI"`
+	GSUZI    string `json:"GSUZI" xml:"GSUZI"`
+	GETRI    string `json:"GETRI" xml:"GETRI"`
+	GEUZI    string `json:"GEUZI" xml:"GEUZI"`
+	GAMNG    string `json:"GAMNG" xml:"GAMNG"`
+	WEMNG    string `json:"WEMNG" xml:"WEMNG"`
+	CANT_RES string `json:"CANT_RES" xml:"CANT_RES"`
+	GMEIN    string `json:"GMEIN" xml:"GMEIN"`
+}
+
+type Component struct {
+	COMPONENTE           string `json:"COMPONENTE" xml:"COMPONENTE"`
+	MATERIAL_DESCRIPTION string `json:"MATERIAL_DESCRIPTION" xml:"MATERIAL_DESCRIPTION"`
+	REQ_QUAN             string `json:"REQ_QUAN" xml:"REQ_QUAN"`
+	BASE_UOM             string `json:"BASE_UOM" xml:"BASE_UOM"`
+	COMMITED_QUANTITY    string `json:"COMMITED_QUANTITY" xml:"COMMITED_QUANTITY"`
+	ENTRY_UOM            string `json:"ENTRY_UOM" xml:"ENTRY_UOM"`
+	WITHDRAWN_QUANTITY   string `json:"WITHDRAWN_QUANTITY" xml:"WITHDRAWN_QUANTITY"`
+}
+
+// DefaultFixtures datos incluidos en el binario: centros 1004 y 1012 con órdenes en W1004121 y W1004122
+func DefaultFixtures() (*Fixtures, error) {
+	fsys, err := fs.Sub(defaultFixtures, "fixtures")
+	if err != nil {
+		return nil, err
+	}
+	return LoadFixtures(fsys)
+}
+
+// LoadFixtures lee lines_factory.json, order_sequence.json y order_components.json de fsys, por ejemplo
+// os.DirFS(dir)
+func LoadFixtures(fsys fs.FS) (*Fixtures, error) {
+	f := &Fixtures{}
+	if err := loadFile(fsys, linesFile, &f.Lines); err != nil {
+		return nil, err
+	}
+	if err := loadFile(fsys, ordersFile, &f.Orders); err != nil {
+		return nil, err
+	}
+	if err := loadFile(fsys, componentsFile, &f.Components); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func loadFile(fsys fs.FS, name string, v interface{}) error {
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("fixture %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package sapMock
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const rfcNamespace = "urn:sap-com:document:sap:rfc:functions"
+
+// Funciones atendidas
+const (
+	FunctionLinesFactory    = "ZPP_IOT_LINES_FACTORY"
+	FunctionOrderSequence   = "ZPP_IOT_ORDER_SEQUENCE"
+	FunctionOrderComponents = "ZPP_IOT_ORDER_COMPONENTES"
+	FunctionStopEvent       = "ZPP_IOT_PARADA_LOG"
+	FunctionConsumption     = "ZPP_IOT_CONSUMOS_ORDEN"
+)
+
+type rfcMessage struct {
+	Type    string `xml:"TYPE"`
+	ID      string `xml:"ID"`
+	Number  string `xml:"NUMBER"`
+	Message string `xml:"MESSAGE"`
+}
+
+// rfcResult tablas de salida de las funciones; cada función rellena las suyas
+type rfcResult struct {
+	Lines      []Line       `xml:"ET_LINES>item,omitempty"`
+	Orders     []Order      `xml:"ET_ORDERS>item,omitempty"`
+	Components []Component  `xml:"ET_COMPONENTES>item,omitempty"`
+	Return     []rfcMessage `xml:"ET_RETURN>item,omitempty"`
+}
+
+func rfcError(message string) rfcMessage {
+	return rfcMessage{Type: "E", ID: "ZPP_IOT", Number: "001", Message: message}
+}
+
+func rfcSuccess(message string) rfcMessage {
+	return rfcMessage{Type: "S", ID: "ZPP_IOT", Number: "000", Message: message}
+}
+
+func (s *Server) dispatch(w http.ResponseWriter, function string, params map[string]string) {
+	var result rfcResult
+	switch function {
+	case FunctionLinesFactory:
+		result.Lines = s.fixtures.Lines[params["IV_WERKS"]]
+	case FunctionOrderSequence:
+		result.Orders = s.fixtures.Orders[params["IV_LINEA"]]
+	case FunctionOrderComponents:
+		result.Components = s.fixtures.Components[params["IV_AUFNR"]]
+	case FunctionStopEvent:
+		result.Return = []rfcMessage{s.stopEvent(params)}
+	case FunctionConsumption:
+		result.Return = []rfcMessage{s.consumption(params)}
+	default:
+		writeSoapFault(w, http.StatusInternalServerError, "soap:Client", fmt.Sprintf("Function %s is not available", function))
+		return
+	}
+	writeResponse(w, function, result)
+}
+
+func (s *Server) stopEvent(params map[string]string) rfcMessage {
+	if msg, ok := required(params, "IV_MAQUINA", "IV_TIMESTAMP", "IV_ESTADO", "IV_OPERARIO"); !ok {
+		return msg
+	}
+	if _, err := time.Parse("2006-01-02T15:04:05", params["IV_TIMESTAMP"]); err != nil {
+		return rfcError(fmt.Sprintf("Invalid IV_TIMESTAMP %s", params["IV_TIMESTAMP"]))
+	}
+	return rfcSuccess("Stop registered")
+}
+
+// consumption valida que la orden exista en los datos y que el material sea uno de sus componentes
+func (s *Server) consumption(params map[string]string) rfcMessage {
+	if msg, ok := required(params, "IV_WERKS", "IV_AUFNR", "IV_ARBPL", "IV_MATNR", "IV_OPERARIO", "IV_TIMESTAMP_INI", "IV_TIMESTAMP_FIN"); !ok {
+		return msg
+	}
+	for _, name := range []string{"IV_TIMESTAMP_INI", "IV_TIMESTAMP_FIN"} {
+		if _, err := time.Parse("2006-01-02T15:04:05.000Z", params[name]); err != nil {
+			return rfcError(fmt.Sprintf("Invalid %s %s", name, params[name]))
+		}
+	}
+	components, ok := s.fixtures.Components[params["IV_AUFNR"]]
+	if !ok {
+		return rfcError(fmt.Sprintf("Order %s does not exist", params["IV_AUFNR"]))
+	}
+	for _, c := range components {
+		if c.COMPONENTE == params["IV_MATNR"] {
+			return rfcSuccess("Consumption posted")
+		}
+	}
+	return rfcError(fmt.Sprintf("Material %s is not a component of order %s", params["IV_MATNR"], params["IV_AUFNR"]))
+}
+
+func required(params map[string]string, names ...string) (rfcMessage, bool) {
+	for _, name := range names {
+		if params[name] == "" {
+			return rfcError(fmt.Sprintf("Parameter %s is required", name)), false
+		}
+	}
+	return rfcMessage{}, true
+}
+
+// writeResponse responde como el adaptador RFC de CPI: elemento <función>.Response en el espacio RFC
+func writeResponse(w http.ResponseWriter, function string, result rfcResult) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	start := xml.StartElement{
+		Name: xml.Name{Local: "ns0:" + function + ".Response"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns:ns0"}, Value: rfcNamespace}},
+	}
+	if err := xml.NewEncoder(&buf).EncodeElement(result, start); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func writeSoapFault(w http.ResponseWriter, status int, code string, message string) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>`)
+	xml.EscapeText(&buf, []byte(code))
+	buf.WriteString(`</faultcode><faultstring>`)
+	xml.EscapeText(&buf, []byte(message))
+	buf.WriteString(`</faultstring></soap:Fault></soap:Body></soap:Envelope>`)
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
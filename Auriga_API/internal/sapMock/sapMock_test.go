@@ -0,0 +1,290 @@
+package sapMock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// rfcRequest cuerpo de una llamada RFC como la que envía rsSap
+func rfcRequest(function string, params map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<ns0:%s xmlns:ns0="%s">`, function, rfcNamespace)
+	for name, value := range params {
+		fmt.Fprintf(&b, "<%s>%s</%s>", name, value, name)
+	}
+	fmt.Fprintf(&b, "</ns0:%s>", function)
+	return b.String()
+}
+
+func newRequest(t *testing.T, url string, function string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(rfcRequest(function, map[string]string{"IV_WERKS": "1004"})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestNextFaultCountdown(t *testing.T) {
+	s := New(nil, "", nil)
+	s.InjectFault(Fault{Function: FunctionStopEvent, Times: 2, StatusCode: http.StatusServiceUnavailable})
+	s.InjectFault(Fault{Times: 1, Latency: 10 * time.Millisecond})
+
+	// Una función distinta salta el primer fallo y consume el genérico
+	fault, latency := s.nextFault(FunctionLinesFactory)
+	if fault == nil || fault.Function != "" || latency != 10*time.Millisecond {
+		t.Fatalf("nextFault(lines) = %+v, %v", fault, latency)
+	}
+	if faults := s.Faults(); len(faults) != 1 || faults[0].Times != 2 {
+		t.Fatalf("faults after generic fault = %+v", faults)
+	}
+
+	fault, _ = s.nextFault(FunctionStopEvent)
+	if fault == nil || fault.StatusCode != http.StatusServiceUnavailable || fault.Times != 2 {
+		t.Fatalf("first nextFault(stop) = %+v", fault)
+	}
+	if faults := s.Faults(); len(faults) != 1 || faults[0].Times != 1 {
+		t.Fatalf("faults after first call = %+v", faults)
+	}
+	if fault, _ = s.nextFault(FunctionStopEvent); fault == nil {
+		t.Fatal("second nextFault(stop) = nil")
+	}
+	if faults := s.Faults(); len(faults) != 0 {
+		t.Fatalf("faults after last call = %+v", faults)
+	}
+	if fault, _ = s.nextFault(FunctionStopEvent); fault != nil {
+		t.Errorf("nextFault after removal = %+v", fault)
+	}
+}
+
+func TestNextFaultWithoutTimesPersists(t *testing.T) {
+	s := New(nil, "", nil)
+	s.SetLatency(5 * time.Millisecond)
+	s.InjectFault(Fault{Function: FunctionStopEvent, RfcError: "Locked", Latency: time.Millisecond})
+
+	for i := 0; i < 3; i++ {
+		fault, latency := s.nextFault(FunctionStopEvent)
+		if fault == nil || fault.RfcError != "Locked" || latency != 6*time.Millisecond {
+			t.Fatalf("call %d: nextFault = %+v, %v", i, fault, latency)
+		}
+	}
+	if faults := s.Faults(); len(faults) != 1 || faults[0].Times != 0 {
+		t.Errorf("faults = %+v", faults)
+	}
+
+	s.ClearFaults()
+	if fault, latency := s.nextFault(FunctionStopEvent); fault != nil || latency != 5*time.Millisecond {
+		t.Errorf("nextFault after ClearFaults = %+v, %v", fault, latency)
+	}
+}
+
+func TestDropInProcess(t *testing.T) {
+	s := New(nil, "", nil)
+	s.InjectFault(Fault{Times: 1, Drop: true})
+
+	resp, err := s.Transport().RoundTrip(newRequest(t, "http://sap.test/rfc", FunctionLinesFactory))
+	if !errors.Is(err, ErrDropped) {
+		t.Fatalf("RoundTrip = %v, %v; want ErrDropped", resp, err)
+	}
+
+	resp, err = s.Transport().RoundTrip(newRequest(t, "http://sap.test/rfc", FunctionLinesFactory))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("RoundTrip after drop = %v, %v", resp, err)
+	}
+	resp.Body.Close()
+
+	messages := s.Messages(FunctionLinesFactory)
+	if len(messages) != 2 || messages[0].StatusCode != 0 || messages[1].StatusCode != http.StatusOK {
+		t.Errorf("messages = %+v", messages)
+	}
+}
+
+func TestDropOverHTTP(t *testing.T) {
+	s := New(nil, "", nil)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	s.InjectFault(Fault{Times: 1, Drop: true})
+	resp, err := srv.Client().Do(newRequest(t, srv.URL+"/rfc", FunctionLinesFactory))
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("drop answered with status %d", resp.StatusCode)
+	}
+	if errors.Is(err, ErrDropped) {
+		t.Errorf("client saw the in-process error over HTTP: %v", err)
+	}
+
+	resp, err = srv.Client().Do(newRequest(t, srv.URL+"/rfc", FunctionLinesFactory))
+	if err != nil {
+		t.Fatalf("call after drop: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status after drop = %d", resp.StatusCode)
+	}
+}
+
+func TestHandleFaults(t *testing.T) {
+	fixtures, err := DefaultFixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(fixtures, "dXNlcjpwYXNz", nil)
+
+	req := newRequest(t, "http://sap.test/rfc", FunctionLinesFactory)
+	resp, err := s.Transport().RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unauthenticated call = %v, %v", resp, err)
+	}
+	if len(s.Messages("")) != 0 {
+		t.Error("unauthenticated call recorded")
+	}
+
+	tests := []struct {
+		name   string
+		fault  Fault
+		status int
+		body   string
+	}{
+		{"none", Fault{}, http.StatusOK, "W1004121"},
+		{"status", Fault{StatusCode: http.StatusBadGateway}, http.StatusBadGateway, "injected fault"},
+		{"soap fault", Fault{SoapFault: "Dump"}, http.StatusInternalServerError, "<faultstring>Dump</faultstring>"},
+		{"rfc error", Fault{RfcError: "Plant locked"}, http.StatusOK, "Plant locked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.Reset()
+			if tt.name != "none" {
+				tt.fault.Times = 1
+				s.InjectFault(tt.fault)
+			}
+			req := newRequest(t, "http://sap.test/rfc", FunctionLinesFactory)
+			req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+			req.Header.Set("Idempotency-Key", "key-1")
+			resp, err := s.Transport().RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.body) {
+				t.Errorf("response = %d %s", resp.StatusCode, body)
+			}
+			messages := s.Messages(FunctionLinesFactory)
+			if len(messages) != 1 || messages[0].StatusCode != tt.status || messages[0].IdempotencyKey != "key-1" || messages[0].Params["IV_WERKS"] != "1004" {
+				t.Errorf("messages = %+v", messages)
+			}
+		})
+	}
+}
+
+func TestDefaultFixtures(t *testing.T) {
+	f, err := DefaultFixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := f.Lines["1004"]; len(lines) != 4 || lines[0].ARBPL != "W1004121" || lines[0].KTEXT != "FSP Line 01" {
+		t.Errorf("lines 1004 = %+v", lines)
+	}
+	if len(f.Lines["1012"]) != 1 {
+		t.Errorf("lines 1012 = %+v", f.Lines["1012"])
+	}
+	if len(f.Orders["W1004121"]) == 0 || len(f.Orders["W1004122"]) == 0 {
+		t.Errorf("orders = %+v", f.Orders)
+	}
+	if len(f.Components) == 0 {
+		t.Error("no components loaded")
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	f, err := LoadFixtures(fstest.MapFS{
+		linesFile: {Data: []byte(`{"2001": [{"ARBPL": "W2001", "KTEXT": "Test line"}]}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Lines["2001"]) != 1 || f.Lines["2001"][0].ARBPL != "W2001" {
+		t.Errorf("lines = %+v", f.Lines)
+	}
+	if f.Orders != nil || f.Components != nil {
+		t.Errorf("missing files not left empty: %+v %+v", f.Orders, f.Components)
+	}
+
+	_, err = LoadFixtures(fstest.MapFS{
+		ordersFile: {Data: []byte(`{"W1": [`)},
+	})
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) || !strings.Contains(err.Error(), ordersFile) {
+		t.Errorf("invalid JSON error = %v", err)
+	}
+
+	f, err = LoadFixtures(fstest.MapFS{})
+	if err != nil || f.Lines != nil {
+		t.Errorf("empty fs = %+v, %v", f, err)
+	}
+}
+
+func TestControlRoutes(t *testing.T) {
+	s := New(nil, "", nil)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	do := func(method, path, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := do(http.MethodPost, "/_mock/faults", `{"function": "ZPP_IOT_PARADA_LOG", "times": 2, "latency": "5ms", "status_code": 503}`)
+	var faults []faultRequest
+	if err := json.NewDecoder(resp.Body).Decode(&faults); err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST faults = %d, %v", resp.StatusCode, err)
+	}
+	if len(faults) != 1 || faults[0].Times != 2 || faults[0].Latency != "5ms" || faults[0].StatusCode != 503 {
+		t.Errorf("faults = %+v", faults)
+	}
+	if got := s.Faults(); len(got) != 1 || got[0].Latency != 5*time.Millisecond {
+		t.Errorf("injected faults = %+v", got)
+	}
+
+	if resp := do(http.MethodPost, "/_mock/faults", `{"latency": "soon"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid latency = %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodPut, "/_mock/latency", `{"latency": "1ms"}`); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("PUT latency = %d", resp.StatusCode)
+	}
+
+	do(http.MethodPost, "/rfc", rfcRequest(FunctionLinesFactory, map[string]string{"IV_WERKS": "1004"}))
+	resp = do(http.MethodGet, "/_mock/messages?function="+FunctionLinesFactory, "")
+	var messages []Message
+	if err := json.NewDecoder(resp.Body).Decode(&messages); err != nil || len(messages) != 1 {
+		t.Errorf("GET messages = %+v, %v", messages, err)
+	}
+
+	if resp := do(http.MethodPost, "/_mock/reset", ""); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("reset = %d", resp.StatusCode)
+	}
+	if len(s.Faults()) != 0 || len(s.Messages("")) != 0 {
+		t.Error("reset left faults or messages")
+	}
+	if resp := do(http.MethodGet, "/_mock/unknown", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown route = %d", resp.StatusCode)
+	}
+}
@@ -2,45 +2,98 @@ package sEvents
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/remrafvil/Auriga_API/config"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rAssets"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rEvents"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rLabor"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rLineOrders"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
 	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rsSap"
+	"github.com/remrafvil/Auriga_API/internal/services/sLabor"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"go.uber.org/zap"
 )
 
 type Service interface {
 	EventsRawByLineList(factory string, lineNumber string) ([]msRawEvents, error)
-	EventsRawToCommitLine(id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string) ([]msCommitEvents, error)
-	EventsRawByLineDel(id uint) ([]msRawEvents, error)
+	EventsRawToCommitLine(ctx context.Context, id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, reason string) ([]msCommitEvents, error)
+	EventsRawByLineDel(ctx context.Context, id uint, reason string) ([]msRawEvents, error)
+	EventsRawBulkCommit(ctx context.Context, req BulkRawCommitRequest) (*msBulkReport, error)
+	EventsRawBulkClassify(ctx context.Context, req BulkIDsRequest) (*msBulkReport, error)
+	EventsRawBulkDiscard(ctx context.Context, req BulkIDsRequest) (*msBulkReport, error)
 
 	EventsCommitByLineList(factory string, lineNumber string) ([]msCommitEvents, error)
-	EventsCommitByLineAdd(eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, eventCategory string) ([]msCommitEvents, error)
-	EventsCommitByLineUpdate(id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, eventCategory string) ([]msCommitEvents, error)
-	EventsCommitByLineDel(id uint) ([]msCommitEvents, error)
+	EventsCommitByLineAdd(ctx context.Context, eventTime time.Time, endTime *time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, eventCategory string, reason string) ([]msCommitEvents, error)
+	EventsCommitByLineUpdate(ctx context.Context, id uint, eventTime time.Time, endTime *time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, eventCategory string, reason string) ([]msCommitEvents, error)
+	EventsCommitByLineDel(ctx context.Context, id uint, reason string) ([]msCommitEvents, error)
+	EventsCommitByLineClose(ctx context.Context, id uint, endTime time.Time, reason string) error
+	EventsCommitBulkMerge(ctx context.Context, req BulkIDsRequest) (*msBulkReport, error)
+	EventsCommitBulkSplit(ctx context.Context, req BulkSplitRequest) (*msBulkReport, error)
+	EventsCommitBulkDiscard(ctx context.Context, req BulkIDsRequest) (*msBulkReport, error)
 
-	EventsSapByLineDel(id uint) ([]msCommitEvents, error)
+	GetDowntimeTotals(factory string, prodLine string, from time.Time, to time.Time, groupBy string) (*msDowntimeReport, error)
+	GetEventAnalytics(factory string, prodLine string, from time.Time, to time.Time, interval string) (*msEventAnalytics, error)
+
+	GetClassificationRules() ([]rModels.MrEventClassificationRule, error)
+	CreateClassificationRule(req ClassificationRuleRequest) (*rModels.MrEventClassificationRule, error)
+	UpdateClassificationRule(id uint, req ClassificationRuleRequest) (*rModels.MrEventClassificationRule, error)
+	DeleteClassificationRule(id uint) error
+
+	GetEventHistory(kind string, id uint) ([]msEventAudit, error)
+	RestoreRawEvent(ctx context.Context, id uint, reason string) (*msRawEvents, error)
+	RestoreCommitEvent(ctx context.Context, id uint, reason string) (*msCommitEvents, error)
+
+	EventsSapByLineDel(ctx context.Context, id uint) ([]msCommitEvents, error)
+	ListSapOutbox(status string, factory string) ([]rModels.MrSapOutbox, error)
+	GetSapOutboxMessage(id uint) (*rModels.MrSapOutbox, error)
+	RetrySapOutboxMessage(id uint) (*rModels.MrSapOutbox, error)
+	DiscardSapOutboxMessage(ctx context.Context, id uint, reason string) (*rModels.MrSapOutbox, error)
 
 	GetAllCategoriesWithEventTypes(ctx context.Context) ([]msEventCategoryDTO, error)
 	GetCategoryWithEventTypesByName(ctx context.Context, name string) (*msEventCategoryDTO, error)
+
+	StartRawEventIngestion(ctx context.Context)
+	StartStopEventCloser(ctx context.Context)
+	StartRawEventClassifier(ctx context.Context)
+	StartSapOutboxDelivery(ctx context.Context)
 }
 
+// Errores que los handlers traducen a códigos HTTP
+var (
+	ErrNotFound       = errors.New("record not found")
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrConflict       = errors.New("conflict")
+)
+
 type service struct {
 	repositoryEven   rEvents.Repository
 	repositoryAss    rAssets.Repository
 	repositoryOrd    rLineOrders.Repository
 	repositorySap    rsSap.Repository
 	repositoryInflux riInfluxdb.Repository
+	repositoryLabor  rLabor.Repository
+	serviceLive      sLive.Service
+	serviceLabor     sLabor.Service
+	config           *config.Settings
+	logger           *zap.Logger
+	classifyCursor   uint // Último evento bruto revisado por el clasificador
 }
 
-func New(repositoryEven rEvents.Repository, repositoryAss rAssets.Repository, repositoryOrd rLineOrders.Repository, repositorySap rsSap.Repository, repositoryInflux riInfluxdb.Repository) Service {
+func New(repositoryEven rEvents.Repository, repositoryAss rAssets.Repository, repositoryOrd rLineOrders.Repository, repositorySap rsSap.Repository, repositoryInflux riInfluxdb.Repository, repositoryLabor rLabor.Repository, serviceLive sLive.Service, serviceLabor sLabor.Service, config *config.Settings, logger *zap.Logger) Service {
 	return &service{
 		repositoryEven:   repositoryEven,
 		repositoryAss:    repositoryAss,
 		repositoryOrd:    repositoryOrd,
 		repositorySap:    repositorySap,
 		repositoryInflux: repositoryInflux,
+		repositoryLabor:  repositoryLabor,
+		serviceLive:      serviceLive,
+		serviceLabor:     serviceLabor,
+		config:           config,
+		logger:           logger,
 	}
 }
@@ -0,0 +1,187 @@
+package sEvents
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+// Periodos de la serie de tendencia
+const (
+	TrendByDay  = "day"
+	TrendByWeek = "week"
+)
+
+// paretoGroupings dimensiones del evento sobre las que se calcula el Pareto
+var paretoGroupings = []string{DowntimeByCategory, DowntimeByType, DowntimeBySystem, DowntimeByMachine, DowntimeByPart}
+
+type msParetoItem struct {
+	msDowntimeTotal
+	Percent    float64 `json:"Percent"`    // Porcentaje del tiempo total de parada
+	Cumulative float64 `json:"Cumulative"` // Porcentaje acumulado en orden de Pareto
+}
+
+type msTrendPoint struct {
+	Period  string    `json:"Period"` // Día, o lunes de la semana, en formato 2006-01-02
+	Start   time.Time `json:"Start"`
+	Events  int       `json:"Events"`
+	Seconds float64   `json:"Seconds"`
+}
+
+type msEventAnalytics struct {
+	Factory  string                    `json:"Factory"`
+	ProdLine string                    `json:"ProdLine"`
+	From     time.Time                 `json:"From"`
+	To       time.Time                 `json:"To"`
+	Interval string                    `json:"Interval"`
+	Events   int                       `json:"Events"`
+	Seconds  float64                   `json:"Seconds"`
+	Pareto   map[string][]msParetoItem `json:"Pareto"` // Por category, type, system, machine y part
+	Shifts   []msDowntimeTotal         `json:"Shifts"`
+	Orders   []msDowntimeTotal         `json:"Orders"`
+	Trend    []msTrendPoint            `json:"Trend"`
+}
+
+// GetEventAnalytics analiza las paradas de la fábrica en el intervalo: Pareto por categoría, tipo,
+// sistema, máquina y pieza, totales por turno y por orden de fabricación y tendencia diaria o semanal
+func (s *service) GetEventAnalytics(factory string, prodLine string, from time.Time, to time.Time, interval string) (*msEventAnalytics, error) {
+	now := time.Now()
+	from, to, err := checkDowntimeRange(factory, from, to, now)
+	if err != nil {
+		return nil, err
+	}
+	if interval != TrendByDay && interval != TrendByWeek {
+		return nil, fmt.Errorf("%w: interval must be day or week", ErrInvalidRequest)
+	}
+
+	events, err := s.repositoryEven.EventsCommitInRange(factory, prodLine, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &msEventAnalytics{
+		Factory:  factory,
+		ProdLine: prodLine,
+		From:     from,
+		To:       to,
+		Interval: interval,
+		Pareto:   make(map[string][]msParetoItem),
+	}
+
+	for _, groupBy := range paretoGroupings {
+		split, err := s.downtimeSplit(factory, groupBy, now)
+		if err != nil {
+			return nil, err
+		}
+		totals, count, total, err := aggregateDowntime(events, from, to, now, split)
+		if err != nil {
+			return nil, err
+		}
+		analytics.Events = count
+		analytics.Seconds = total.Seconds()
+		analytics.Pareto[groupBy] = pareto(totals, total)
+	}
+
+	for _, groupBy := range []string{DowntimeByShift, DowntimeByOrder} {
+		split, err := s.downtimeSplit(factory, groupBy, now)
+		if err != nil {
+			return nil, err
+		}
+		totals, _, _, err := aggregateDowntime(events, from, to, now, split)
+		if err != nil {
+			return nil, err
+		}
+		sortByDowntime(totals)
+		if groupBy == DowntimeByShift {
+			analytics.Shifts = totals
+		} else {
+			analytics.Orders = totals
+		}
+	}
+
+	trend, _, _, err := aggregateDowntime(events, from, to, now, splitByPeriod(interval))
+	if err != nil {
+		return nil, err
+	}
+	analytics.Trend = trendSeries(trend, from, to, interval)
+
+	return analytics, nil
+}
+
+// pareto ordena de mayor a menor tiempo y añade el porcentaje y el acumulado sobre el total
+func pareto(totals []msDowntimeTotal, total time.Duration) []msParetoItem {
+	sortByDowntime(totals)
+	items := make([]msParetoItem, 0, len(totals))
+	var cumulative float64
+	for _, item := range totals {
+		percent := 0.0
+		if total > 0 {
+			percent = item.Seconds / total.Seconds() * 100
+		}
+		cumulative += percent
+		items = append(items, msParetoItem{
+			msDowntimeTotal: item,
+			Percent:         round2(percent),
+			Cumulative:      round2(cumulative),
+		})
+	}
+	return items
+}
+
+// splitByPeriod reparte el tramo del evento entre los días o semanas que abarca
+func splitByPeriod(interval string) splitFunc {
+	return func(event rModels.MrCommitEvents, start time.Time, end time.Time) ([]downtimeSlice, error) {
+		var slices []downtimeSlice
+		for period := periodStart(start, interval); period.Before(end); period = nextPeriod(period, interval) {
+			inside := overlap(start, end, period, nextPeriod(period, interval))
+			if inside > 0 {
+				slices = append(slices, downtimeSlice{key: period.Format("2006-01-02"), duration: inside})
+			}
+		}
+		return slices, nil
+	}
+}
+
+// trendSeries devuelve un punto por periodo del intervalo, también los que no tienen paradas
+func trendSeries(totals []msDowntimeTotal, from time.Time, to time.Time, interval string) []msTrendPoint {
+	byPeriod := make(map[string]msDowntimeTotal, len(totals))
+	for _, item := range totals {
+		byPeriod[item.Key] = item
+	}
+
+	var points []msTrendPoint
+	for period := periodStart(from, interval); period.Before(to); period = nextPeriod(period, interval) {
+		key := period.Format("2006-01-02")
+		item := byPeriod[key]
+		points = append(points, msTrendPoint{
+			Period:  key,
+			Start:   period,
+			Events:  item.Events,
+			Seconds: item.Seconds,
+		})
+	}
+	return points
+}
+
+// periodStart inicio del día, o del lunes de la semana, que contiene t
+func periodStart(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if interval == TrendByWeek {
+		offset := (int(day.Weekday()) + 6) % 7 // Días desde el lunes
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+func nextPeriod(period time.Time, interval string) time.Time {
+	if interval == TrendByWeek {
+		return period.AddDate(0, 0, 7)
+	}
+	return period.AddDate(0, 0, 1)
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,138 @@
+package sEvents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rEvents"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"github.com/remrafvil/Auriga_API/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Autor de los cambios que hacen los procesos automáticos
+const systemUser = "system"
+
+// RestoreEventRequest recuperar un evento borrado exige indicar el motivo
+type RestoreEventRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type msEventAudit struct {
+	ID        uint            `json:"ID"`
+	Action    string          `json:"Action"`
+	Before    json.RawMessage `json:"Before"` // Nulo en las altas
+	After     json.RawMessage `json:"After"`  // Nulo en los borrados
+	UserID    string          `json:"UserID"`
+	UserName  string          `json:"UserName"`
+	Reason    string          `json:"Reason"`
+	CreatedAt time.Time       `json:"CreatedAt"`
+}
+
+// auditFrom toma el usuario autenticado del contexto de la petición
+func auditFrom(ctx context.Context, reason string) rEvents.AuditInfo {
+	audit := rEvents.AuditInfo{UserID: "unknown", Reason: reason}
+	if ctx == nil {
+		return audit
+	}
+	if userID, ok := utils.GetUserID(ctx); ok && userID != "" {
+		audit.UserID = userID
+	}
+	if name, ok := utils.GetUserName(ctx); ok && name != "" {
+		audit.UserName = name
+	} else if email, ok := utils.GetUserEmail(ctx); ok {
+		audit.UserName = email
+	}
+	return audit
+}
+
+func systemAudit(reason string) rEvents.AuditInfo {
+	return rEvents.AuditInfo{UserID: systemUser, UserName: systemUser, Reason: reason}
+}
+
+// bulkAudit antepone la operación al motivo para distinguir los cambios masivos en la historia
+func bulkAudit(ctx context.Context, operation string, reason string) rEvents.AuditInfo {
+	text := "Bulk " + operation
+	if reason != "" {
+		text += ": " + reason
+	}
+	return auditFrom(ctx, text)
+}
+
+// GetEventHistory devuelve los cambios de un evento bruto (raw) o confirmado (commit), el más antiguo primero
+func (s *service) GetEventHistory(kind string, id uint) ([]msEventAudit, error) {
+	if kind != rModels.EventAuditRaw && kind != rModels.EventAuditCommit {
+		return nil, fmt.Errorf("%w: unknown event kind %q", ErrInvalidRequest, kind)
+	}
+	entries, err := s.repositoryEven.EventsAuditByEvent(kind, id)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]msEventAudit, 0, len(entries))
+	for _, entry := range entries {
+		data = append(data, msEventAudit{
+			ID:        entry.ID,
+			Action:    entry.Action,
+			Before:    rawJSON(entry.Before),
+			After:     rawJSON(entry.After),
+			UserID:    entry.UserID,
+			UserName:  entry.UserName,
+			Reason:    entry.Reason,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	return data, nil
+}
+
+// RestoreRawEvent devuelve a la cola un evento bruto descartado
+func (s *service) RestoreRawEvent(ctx context.Context, id uint, reason string) (*msRawEvents, error) {
+	restored, err := s.repositoryEven.EventsRawRestore(id, auditFrom(ctx, reason))
+	if err != nil {
+		return nil, restoreError(err, id)
+	}
+	s.logger.Info("Raw event restored", zap.Uint("raw_event_id", id))
+	s.publishRaw(sLive.ActionRestored, restored)
+
+	rules, err := s.repositoryEven.GetActiveClassificationRules()
+	if err != nil {
+		s.logger.Warn("Cannot get classification rules for restored raw event", zap.Error(err))
+	}
+	data := toMsRawEvent(restored, rules)
+	return &data, nil
+}
+
+// RestoreCommitEvent recupera un evento confirmado borrado
+func (s *service) RestoreCommitEvent(ctx context.Context, id uint, reason string) (*msCommitEvents, error) {
+	restored, err := s.repositoryEven.EventsCommitRestore(id, auditFrom(ctx, reason))
+	if err != nil {
+		return nil, restoreError(err, id)
+	}
+	s.logger.Info("Event restored", zap.Uint("event_id", id))
+	s.publishCommit(sLive.ActionRestored, restored)
+
+	data := toMsCommitEvent(restored, time.Now())
+	return &data, nil
+}
+
+func restoreError(err error, id uint) error {
+	switch {
+	case errors.Is(err, rEvents.ErrNotDeleted):
+		return fmt.Errorf("%w: event %d is not deleted", ErrConflict, id)
+	case errors.Is(err, rEvents.ErrRawCommitted):
+		return fmt.Errorf("%w: raw event %d was committed or its discard is not recorded, restore or edit the committed event instead", ErrConflict, id)
+	case errors.Is(err, rEvents.ErrSentToSap):
+		return fmt.Errorf("%w: event %d was sent to SAP and cannot be restored", ErrConflict, id)
+	}
+	return notFound(err)
+}
+
+func rawJSON(value string) json.RawMessage {
+	if value == "" {
+		return nil
+	}
+	return json.RawMessage(value)
+}
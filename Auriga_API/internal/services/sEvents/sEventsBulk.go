@@ -0,0 +1,480 @@
+package sEvents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rEvents"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"go.uber.org/zap"
+)
+
+// Operaciones masivas sobre eventos
+const (
+	BulkCommit   = "commit"
+	BulkClassify = "classify"
+	BulkDiscard  = "discard"
+	BulkMerge    = "merge"
+	BulkSplit    = "split"
+)
+
+// Estado de cada elemento del informe de una operación masiva
+const (
+	BulkItemApplied = "applied"
+	BulkItemFailed  = "failed"
+	BulkItemSkipped = "skipped" // Válido, pero no aplicado porque otro elemento del lote falló
+)
+
+type BulkIDsRequest struct {
+	IDs    []uint `json:"ids" validate:"required,min=1,max=500,dive,min=1"`
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+type BulkRawCommitRequest struct {
+	Items  []BulkRawCommitItem `json:"items" validate:"required,min=1,max=500,dive"`
+	Reason string              `json:"reason" validate:"max=500"`
+}
+
+// BulkRawCommitItem sin tipo se confirma con el código ESW como tipo, igual que la confirmación individual
+type BulkRawCommitItem struct {
+	ID       uint   `json:"id" validate:"required,min=1"`
+	Type     string `json:"type" validate:"required_with=Category,max=255"`
+	Category string `json:"category" validate:"max=255"`
+}
+
+type BulkSplitRequest struct {
+	Items  []BulkSplitItem `json:"items" validate:"required,min=1,max=500,dive"`
+	Reason string          `json:"reason" validate:"max=500"`
+}
+
+// BulkSplitItem corta el evento en el instante indicado; varios cortes del mismo evento generan varios tramos
+type BulkSplitItem struct {
+	ID uint      `json:"id" validate:"required,min=1"`
+	At time.Time `json:"at" validate:"notzerotime"`
+}
+
+type msBulkItemResult struct {
+	ID      uint   `json:"ID"`
+	Status  string `json:"Status"`
+	Error   string `json:"Error,omitempty"`
+	EventID uint   `json:"EventID,omitempty"` // Evento confirmado resultante
+}
+
+// msBulkReport resultado por elemento; Applied es false si algún elemento falló y no se aplicó nada
+type msBulkReport struct {
+	Operation string             `json:"Operation"`
+	Applied   bool               `json:"Applied"`
+	Items     []msBulkItemResult `json:"Items"`
+}
+
+func newBulkReport(operation string, ids []uint) *msBulkReport {
+	report := &msBulkReport{Operation: operation, Items: make([]msBulkItemResult, len(ids))}
+	for i, id := range ids {
+		report.Items[i] = msBulkItemResult{ID: id, Status: BulkItemSkipped}
+	}
+	return report
+}
+
+func (r *msBulkReport) fail(i int, format string, args ...interface{}) {
+	if r.Items[i].Status == BulkItemFailed {
+		return
+	}
+	r.Items[i].Status = BulkItemFailed
+	r.Items[i].Error = fmt.Sprintf(format, args...)
+}
+
+func (r *msBulkReport) failed() bool {
+	for _, item := range r.Items {
+		if item.Status == BulkItemFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// failDuplicates marca los identificadores repetidos en el lote
+func (r *msBulkReport) failDuplicates() {
+	seen := make(map[uint]bool, len(r.Items))
+	for i, item := range r.Items {
+		if seen[item.ID] {
+			r.fail(i, "duplicated id %d", item.ID)
+		}
+		seen[item.ID] = true
+	}
+}
+
+// applyBulk aplica los cambios solo si todos los elementos son válidos
+func (s *service) applyBulk(report *msBulkReport, changes *rEvents.EventsBulkChanges) error {
+	if report.failed() {
+		return nil
+	}
+	if err := s.repositoryEven.EventsApplyBulk(changes); err != nil {
+		if errors.Is(err, rEvents.ErrEventChanged) {
+			return fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return err
+	}
+
+	report.Applied = true
+	for i := range report.Items {
+		report.Items[i].Status = BulkItemApplied
+	}
+	s.logger.Info("Bulk event operation applied", zap.String("operation", report.Operation), zap.Int("items", len(report.Items)))
+	return nil
+}
+
+func (s *service) rawEventsByID(ids []uint) (map[uint]rModels.MrRawEvents, error) {
+	rawEvents, err := s.repositoryEven.EventsRawByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]rModels.MrRawEvents, len(rawEvents))
+	for _, raw := range rawEvents {
+		byID[raw.ID] = raw
+	}
+	return byID, nil
+}
+
+func (s *service) commitEventsByID(ids []uint) (map[uint]rModels.MrCommitEvents, error) {
+	events, err := s.repositoryEven.EventsCommitByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]rModels.MrCommitEvents, len(events))
+	for _, event := range events {
+		byID[event.ID] = event
+	}
+	return byID, nil
+}
+
+func rawToCommitEvent(raw rModels.MrRawEvents, eventType string, eventCategory string) *rModels.MrCommitEvents {
+	return &rModels.MrCommitEvents{
+		EventTime:     raw.Time,
+		EventType:     eventType,
+		EventCategory: eventCategory,
+		Factory:       raw.EL_Lv0,
+		ProdLine:      raw.Name,
+		System:        raw.EL_Lv1,
+		Machine:       raw.EL_Lv2,
+		Part:          raw.EL_Lv3,
+	}
+}
+
+// EventsRawBulkCommit confirma los eventos brutos con la clasificación indicada por el operador
+func (s *service) EventsRawBulkCommit(ctx context.Context, req BulkRawCommitRequest) (*msBulkReport, error) {
+	ids := make([]uint, len(req.Items))
+	for i, item := range req.Items {
+		ids[i] = item.ID
+	}
+	report := newBulkReport(BulkCommit, ids)
+	report.failDuplicates()
+
+	rawEvents, err := s.rawEventsByID(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &rEvents.EventsBulkChanges{Audit: bulkAudit(ctx, report.Operation, req.Reason)}
+	for i, item := range req.Items {
+		raw, ok := rawEvents[item.ID]
+		if !ok {
+			report.fail(i, "raw event not found")
+			continue
+		}
+		eventType := item.Type
+		if eventType == "" {
+			eventType = strconv.Itoa(raw.ESW)
+		}
+		changes.CommitRaw = append(changes.CommitRaw, rEvents.RawCommit{RawID: raw.ID, Event: rawToCommitEvent(raw, eventType, item.Category)})
+	}
+
+	return report, s.finishRawCommit(report, changes, rawEvents)
+}
+
+// EventsRawBulkClassify confirma los eventos brutos con la clasificación de las reglas activas. A
+// diferencia del clasificador automático no exige confianza mínima, pero sí una regla sin ambigüedad.
+func (s *service) EventsRawBulkClassify(ctx context.Context, req BulkIDsRequest) (*msBulkReport, error) {
+	report := newBulkReport(BulkClassify, req.IDs)
+	report.failDuplicates()
+
+	rawEvents, err := s.rawEventsByID(req.IDs)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.repositoryEven.GetActiveClassificationRules()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &rEvents.EventsBulkChanges{Audit: bulkAudit(ctx, report.Operation, req.Reason)}
+	for i, id := range req.IDs {
+		raw, ok := rawEvents[id]
+		if !ok {
+			report.fail(i, "raw event not found")
+			continue
+		}
+		rule, unambiguous := matchClassificationRule(rules, raw)
+		if rule == nil {
+			report.fail(i, "no classification rule matches ESW %d", raw.ESW)
+			continue
+		}
+		if !unambiguous {
+			report.fail(i, "classification rules disagree on the event type for ESW %d", raw.ESW)
+			continue
+		}
+		event := rawToCommitEvent(raw, rule.EventType.Name, rule.EventType.Category.Name)
+		changes.CommitRaw = append(changes.CommitRaw, rEvents.RawCommit{RawID: raw.ID, Event: event})
+	}
+
+	return report, s.finishRawCommit(report, changes, rawEvents)
+}
+
+// finishRawCommit aplica la confirmación y publica los eventos creados
+func (s *service) finishRawCommit(report *msBulkReport, changes *rEvents.EventsBulkChanges, rawEvents map[uint]rModels.MrRawEvents) error {
+	if err := s.applyBulk(report, changes); err != nil || !report.Applied {
+		return err
+	}
+	for i, item := range changes.CommitRaw {
+		report.Items[i].EventID = item.Event.ID
+		s.publishRaw(sLive.ActionDeleted, rawEvents[item.RawID])
+		s.publishCommit(sLive.ActionCreated, *item.Event)
+	}
+	return nil
+}
+
+// EventsRawBulkDiscard retira los eventos brutos de la cola sin confirmarlos
+func (s *service) EventsRawBulkDiscard(ctx context.Context, req BulkIDsRequest) (*msBulkReport, error) {
+	report := newBulkReport(BulkDiscard, req.IDs)
+	report.failDuplicates()
+
+	rawEvents, err := s.rawEventsByID(req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &rEvents.EventsBulkChanges{Audit: bulkAudit(ctx, report.Operation, req.Reason)}
+	for i, id := range req.IDs {
+		if _, ok := rawEvents[id]; !ok {
+			report.fail(i, "raw event not found")
+			continue
+		}
+		changes.DiscardRaw = append(changes.DiscardRaw, id)
+	}
+
+	if err := s.applyBulk(report, changes); err != nil || !report.Applied {
+		return report, err
+	}
+	for _, id := range changes.DiscardRaw {
+		s.publishRaw(sLive.ActionDeleted, rawEvents[id])
+	}
+	return report, nil
+}
+
+// EventsCommitBulkMerge agrupa microparadas consecutivas de una línea en un único evento: conserva el
+// primero, con su clasificación, desde su inicio hasta el fin del último, y borra el resto
+func (s *service) EventsCommitBulkMerge(ctx context.Context, req BulkIDsRequest) (*msBulkReport, error) {
+	if len(req.IDs) < 2 {
+		return nil, fmt.Errorf("%w: merge needs at least two events", ErrInvalidRequest)
+	}
+	report := newBulkReport(BulkMerge, req.IDs)
+	report.failDuplicates()
+
+	byID, err := s.commitEventsByID(req.IDs)
+	if err != nil {
+		return nil, err
+	}
+	var events []rModels.MrCommitEvents
+	for i, id := range req.IDs {
+		event, ok := byID[id]
+		if !ok {
+			report.fail(i, "event not found")
+			continue
+		}
+		events = append(events, event)
+	}
+	if report.failed() {
+		return report, nil
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].EventTime.Before(events[j].EventTime) })
+	first, last := events[0], events[len(events)-1]
+	for i, id := range req.IDs {
+		event := byID[id]
+		if event.Factory != first.Factory || event.ProdLine != first.ProdLine {
+			report.fail(i, "event is on %s/%s, not on %s/%s", event.Factory, event.ProdLine, first.Factory, first.ProdLine)
+		}
+	}
+	if report.failed() {
+		return report, nil
+	}
+
+	// Solo se agrupan eventos consecutivos: ningún otro evento de la línea puede empezar entre ellos
+	lineEvents, err := s.repositoryEven.EventsCommitInRange(first.Factory, first.ProdLine, first.EventTime, last.EventTime.Add(time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range lineEvents {
+		if _, merged := byID[other.ID]; merged || other.ProdLine != first.ProdLine || other.EventTime.Before(first.EventTime) {
+			continue
+		}
+		for i, id := range req.IDs {
+			if byID[id].EventTime.After(other.EventTime) {
+				report.fail(i, "event %d lies between the merged events", other.ID)
+			}
+		}
+	}
+
+	// El evento agrupado sigue abierto si alguno de ellos lo está
+	merged := first
+	open := first.EndTime == nil
+	for _, event := range events[1:] {
+		if event.EndTime == nil {
+			open = true
+		} else if merged.EndTime == nil || event.EndTime.After(*merged.EndTime) {
+			merged.EndTime = event.EndTime
+		}
+		if merged.WorkOrderID == nil {
+			merged.WorkOrderID = event.WorkOrderID
+		}
+	}
+	if open {
+		merged.EndTime = nil
+	}
+
+	changes := &rEvents.EventsBulkChanges{
+		UpdateCommit: []*rModels.MrCommitEvents{&merged},
+		Audit:        bulkAudit(ctx, report.Operation, req.Reason),
+	}
+	for _, event := range events[1:] {
+		changes.DeleteCommit = append(changes.DeleteCommit, event.ID)
+	}
+	if err := s.applyBulk(report, changes); err != nil || !report.Applied {
+		return report, err
+	}
+	for i := range report.Items {
+		report.Items[i].EventID = merged.ID
+	}
+	s.publishCommit(sLive.ActionUpdated, merged)
+	s.publishCommit(sLive.ActionDeleted, events[1:]...)
+	return report, nil
+}
+
+// EventsCommitBulkSplit corta cada evento en los instantes indicados. El evento original termina en el
+// primer corte y cada corte abre un evento nuevo con la misma clasificación hasta el siguiente o el fin.
+func (s *service) EventsCommitBulkSplit(ctx context.Context, req BulkSplitRequest) (*msBulkReport, error) {
+	ids := make([]uint, len(req.Items))
+	for i, item := range req.Items {
+		ids[i] = item.ID
+	}
+	report := newBulkReport(BulkSplit, ids)
+
+	byID, err := s.commitEventsByID(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cuts := make(map[uint][]int) // Posiciones de los cortes de cada evento
+	var order []uint
+	for i, item := range req.Items {
+		event, ok := byID[item.ID]
+		if !ok {
+			report.fail(i, "event not found")
+			continue
+		}
+		end := now
+		if event.EndTime != nil {
+			end = *event.EndTime
+		}
+		if !item.At.After(event.EventTime) || !item.At.Before(end) {
+			report.fail(i, "split time must be after the event start and before its end")
+			continue
+		}
+		for _, j := range cuts[item.ID] {
+			if req.Items[j].At.Equal(item.At) {
+				report.fail(i, "duplicated split time for event %d", item.ID)
+			}
+		}
+		if len(cuts[item.ID]) == 0 {
+			order = append(order, item.ID)
+		}
+		cuts[item.ID] = append(cuts[item.ID], i)
+	}
+	if report.failed() {
+		return report, nil
+	}
+
+	changes := &rEvents.EventsBulkChanges{Audit: bulkAudit(ctx, report.Operation, req.Reason)}
+	created := make(map[int]*rModels.MrCommitEvents, len(req.Items))
+	for _, id := range order {
+		positions := cuts[id]
+		sort.Slice(positions, func(a, b int) bool { return req.Items[positions[a]].At.Before(req.Items[positions[b]].At) })
+
+		original := byID[id]
+		end := original.EndTime
+		first := req.Items[positions[0]].At
+		original.EndTime = &first
+		changes.UpdateCommit = append(changes.UpdateCommit, &original)
+
+		for k, pos := range positions {
+			segment := original
+			segment.ID = 0
+			segment.WorkOrderID = nil
+			segment.MaintProcessedAt, segment.MaintAttempts, segment.MaintError = nil, 0, "" // Pasa por las reglas de mantenimiento
+			segment.CreatedAt, segment.UpdatedAt = time.Time{}, time.Time{}
+			segment.EventTime = req.Items[pos].At
+			segment.EndTime = end
+			if k+1 < len(positions) {
+				next := req.Items[positions[k+1]].At
+				segment.EndTime = &next
+			}
+			changes.CreateCommit = append(changes.CreateCommit, &segment)
+			created[pos] = &segment
+		}
+	}
+
+	if err := s.applyBulk(report, changes); err != nil || !report.Applied {
+		return report, err
+	}
+	for _, event := range changes.UpdateCommit {
+		s.publishCommit(sLive.ActionUpdated, *event)
+	}
+	for pos, event := range created {
+		report.Items[pos].EventID = event.ID
+		s.publishCommit(sLive.ActionCreated, *event)
+	}
+	return report, nil
+}
+
+// EventsCommitBulkDiscard borra los eventos confirmados
+func (s *service) EventsCommitBulkDiscard(ctx context.Context, req BulkIDsRequest) (*msBulkReport, error) {
+	report := newBulkReport(BulkDiscard, req.IDs)
+	report.failDuplicates()
+
+	byID, err := s.commitEventsByID(req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &rEvents.EventsBulkChanges{Audit: bulkAudit(ctx, report.Operation, req.Reason)}
+	for i, id := range req.IDs {
+		if _, ok := byID[id]; !ok {
+			report.fail(i, "event not found")
+			continue
+		}
+		changes.DeleteCommit = append(changes.DeleteCommit, id)
+	}
+
+	if err := s.applyBulk(report, changes); err != nil || !report.Applied {
+		return report, err
+	}
+	for _, id := range changes.DeleteCommit {
+		s.publishCommit(sLive.ActionDeleted, byID[id])
+	}
+	return report, nil
+}
@@ -0,0 +1,254 @@
+package sEvents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultClassifyInterval      = time.Minute
+	defaultClassifyMinConfidence = 80
+	// classifyBatchSize eventos brutos revisados en cada ciclo del clasificador
+	classifyBatchSize = 500
+)
+
+type ClassificationRuleRequest struct {
+	ESW         int    `json:"esw" validate:"min=0"`
+	Factory     string `json:"factory" validate:"max=32"`
+	ProdLine    string `json:"prod_line" validate:"max=32"`
+	System      string `json:"system" validate:"max=255"`
+	Machine     string `json:"machine" validate:"max=255"`
+	EventTypeID uint   `json:"event_type_id" validate:"required,min=1"`
+	Priority    int    `json:"priority"`
+	Confidence  *int   `json:"confidence" validate:"omitempty,min=0,max=100"`
+	Active      *bool  `json:"active"`
+}
+
+func (s *service) GetClassificationRules() ([]rModels.MrEventClassificationRule, error) {
+	return s.repositoryEven.GetClassificationRules()
+}
+
+func (s *service) CreateClassificationRule(req ClassificationRuleRequest) (*rModels.MrEventClassificationRule, error) {
+	rule := &rModels.MrEventClassificationRule{Confidence: 100, Active: true}
+	if err := s.applyClassificationRule(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repositoryEven.CreateClassificationRule(rule); err != nil {
+		return nil, err
+	}
+
+	return s.repositoryEven.GetClassificationRuleByID(rule.ID)
+}
+
+func (s *service) UpdateClassificationRule(id uint, req ClassificationRuleRequest) (*rModels.MrEventClassificationRule, error) {
+	rule, err := s.repositoryEven.GetClassificationRuleByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if err := s.applyClassificationRule(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repositoryEven.UpdateClassificationRule(rule); err != nil {
+		return nil, err
+	}
+
+	return s.repositoryEven.GetClassificationRuleByID(id)
+}
+
+func (s *service) DeleteClassificationRule(id uint) error {
+	if _, err := s.repositoryEven.GetClassificationRuleByID(id); err != nil {
+		return notFound(err)
+	}
+	return s.repositoryEven.DeleteClassificationRule(id)
+}
+
+// applyClassificationRule comprueba que el tipo de evento existe y copia la petición en la regla
+func (s *service) applyClassificationRule(rule *rModels.MrEventClassificationRule, req ClassificationRuleRequest) error {
+	if _, err := s.repositoryEven.GetEventTypeByID(req.EventTypeID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: event type %d does not exist", ErrInvalidRequest, req.EventTypeID)
+		}
+		return err
+	}
+
+	rule.ESW = req.ESW
+	rule.Factory = req.Factory
+	rule.ProdLine = req.ProdLine
+	rule.System = req.System
+	rule.Machine = req.Machine
+	rule.EventTypeID = req.EventTypeID
+	rule.EventType = rModels.MrEventType{}
+	rule.Priority = req.Priority
+	if req.Confidence != nil {
+		rule.Confidence = *req.Confidence
+	}
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	return nil
+}
+
+// StartRawEventClassifier confirma periódicamente los eventos brutos que encajan con una regla de
+// confianza suficiente, hasta que se cancela el contexto. El resto queda en la cola para el operador.
+func (s *service) StartRawEventClassifier(ctx context.Context) {
+	cfg := s.config.Events
+	if !cfg.ClassifyEnabled {
+		s.logger.Info("Clasificador de eventos brutos deshabilitado")
+		return
+	}
+
+	interval := cfg.ClassifyInterval
+	if interval <= 0 {
+		interval = defaultClassifyInterval
+	}
+
+	s.logger.Info("Iniciando clasificador de eventos brutos", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Deteniendo clasificador de eventos brutos")
+			return
+		case <-ticker.C:
+			s.runRawEventClassifier()
+		}
+	}
+}
+
+func (s *service) runRawEventClassifier() {
+	rules, err := s.repositoryEven.GetActiveClassificationRules()
+	if err != nil {
+		s.logger.Error("Error getting event classification rules", zap.Error(err))
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	var eswCodes []int
+	seen := make(map[int]bool)
+	for _, rule := range rules {
+		if !seen[rule.ESW] {
+			seen[rule.ESW] = true
+			eswCodes = append(eswCodes, rule.ESW)
+		}
+	}
+
+	rawEvents, err := s.repositoryEven.EventsRawPending(eswCodes, s.classifyCursor, classifyBatchSize)
+	if err != nil {
+		s.logger.Error("Error getting pending raw events", zap.Error(err))
+		return
+	}
+	// Los eventos que ninguna regla confirma no bloquean la cola: el siguiente ciclo sigue tras el último
+	// revisado y, al llegar al final, vuelve al principio para revisarlos con las reglas vigentes
+	if len(rawEvents) < classifyBatchSize {
+		s.classifyCursor = 0
+	} else {
+		s.classifyCursor = rawEvents[len(rawEvents)-1].ID
+	}
+
+	minConfidence := s.config.Events.ClassifyMinConfidence
+	if minConfidence <= 0 {
+		minConfidence = defaultClassifyMinConfidence
+	}
+
+	for _, raw := range rawEvents {
+		rule, unambiguous := matchClassificationRule(rules, raw)
+		if rule == nil || !unambiguous || rule.Confidence < minConfidence {
+			continue
+		}
+
+		commitEvent := rawToCommitEvent(raw, rule.EventType.Name, rule.EventType.Category.Name)
+		committed, err := s.repositoryEven.EventsRawCommit(raw.ID, commitEvent, systemAudit(fmt.Sprintf("Classified by rule %d", rule.ID)))
+		if err != nil {
+			s.logger.Error("Error committing classified raw event", zap.Uint("raw_event_id", raw.ID), zap.Error(err))
+			continue
+		}
+		if !committed {
+			continue
+		}
+
+		s.logger.Info("Raw event classified",
+			zap.Uint("raw_event_id", raw.ID),
+			zap.Uint("event_id", commitEvent.ID),
+			zap.Uint("rule_id", rule.ID),
+			zap.String("type", commitEvent.EventType),
+			zap.String("category", commitEvent.EventCategory),
+		)
+		s.publishRaw(sLive.ActionDeleted, raw)
+		s.publishCommit(sLive.ActionCreated, *commitEvent)
+	}
+}
+
+// matchClassificationRule busca la regla que clasifica el evento: mayor prioridad y, a igualdad, la
+// ruta más concreta. unambiguous es false si otra regla igual de preferente asigna un tipo distinto.
+func matchClassificationRule(rules []rModels.MrEventClassificationRule, raw rModels.MrRawEvents) (rule *rModels.MrEventClassificationRule, unambiguous bool) {
+	var candidates []*rModels.MrEventClassificationRule
+	for i := range rules {
+		r := &rules[i]
+		if r.ESW != raw.ESW ||
+			!matchesLevel(r.Factory, raw.EL_Lv0) ||
+			!matchesLevel(r.ProdLine, raw.Name) ||
+			!matchesLevel(r.System, raw.EL_Lv1) ||
+			!matchesLevel(r.Machine, raw.EL_Lv2) {
+			continue
+		}
+		candidates = append(candidates, r)
+	}
+	if len(candidates) == 0 {
+		return nil, false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority > candidates[j].Priority
+		}
+		return ruleSpecificity(candidates[i]) > ruleSpecificity(candidates[j])
+	})
+
+	best := candidates[0]
+	for _, other := range candidates[1:] {
+		if other.Priority != best.Priority || ruleSpecificity(other) != ruleSpecificity(best) {
+			break
+		}
+		if other.EventTypeID != best.EventTypeID {
+			return best, false
+		}
+	}
+	return best, true
+}
+
+func matchesLevel(ruleLevel string, eventLevel string) bool {
+	return ruleLevel == "" || ruleLevel == eventLevel
+}
+
+// ruleSpecificity número de niveles de la ruta que fija la regla
+func ruleSpecificity(rule *rModels.MrEventClassificationRule) int {
+	specificity := 0
+	for _, level := range []string{rule.Factory, rule.ProdLine, rule.System, rule.Machine} {
+		if level != "" {
+			specificity++
+		}
+	}
+	return specificity
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -1,24 +1,33 @@
 package sEvents
 
 import (
+	"context"
 	"log"
 	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
 )
 
 type msCommitEvents struct {
-	EventTime time.Time `json:"EventTime"`
-	Type      string    `json:"Type"`
-	Category  string    `json:"Category"`
-	Factory   string    `json:"Factory"`
-	ProdLine  string    `json:"ProdLine"`
-	System    string    `json:"System"`
-	Machine   string    `json:"Machine"`
-	Part      string    `json:"Part"`
-	ID        uint      `json:"ID"`
+	EventTime   time.Time  `json:"EventTime"`
+	EndTime     *time.Time `json:"EndTime"`  // Nulo mientras la línea sigue parada
+	Duration    float64    `json:"Duration"` // Segundos; los eventos abiertos se miden hasta ahora
+	Type        string     `json:"Type"`
+	Category    string     `json:"Category"`
+	Factory     string     `json:"Factory"`
+	ProdLine    string     `json:"ProdLine"`
+	System      string     `json:"System"`
+	Machine     string     `json:"Machine"`
+	Part        string     `json:"Part"`
+	ID          uint       `json:"ID"`
+	WorkOrderID *uint      `json:"WorkOrderID"`          // OT correctiva generada por las reglas de mantenimiento
+	MaintError  string     `json:"MaintError,omitempty"` // Último fallo al aplicar las reglas de mantenimiento
 }
 
 func (s *service) EventsCommitByLineList(factory string, lineNumber string) ([]msCommitEvents, error) {
 	var data = []msCommitEvents{}
+	now := time.Now()
 
 	// Leemos el listado de eventos registrados automáticamente por la línea desde POSTGRES
 	commitEventData, err := s.repositoryEven.EventsCommitByLineList(factory, lineNumber)
@@ -28,60 +37,82 @@ func (s *service) EventsCommitByLineList(factory string, lineNumber string) ([]m
 	}
 	for _, p := range commitEventData {
 		//log.Println("EventTime:", p.EventTime)
-		data = append(data, msCommitEvents{
-			EventTime: p.EventTime,
-			Type:      p.EventType,
-			Category:  p.EventCategory,
-			Factory:   p.Factory,
-			ProdLine:  p.ProdLine,
-			System:    p.System,
-			Machine:   p.Machine,
-			Part:      p.Part,
-			ID:        p.ID,
-		})
+		data = append(data, toMsCommitEvent(p, now))
 	}
 
 	return data, nil
 }
 
-func (s *service) EventsCommitByLineAdd(eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, eventCategory string) ([]msCommitEvents, error) {
+func (s *service) EventsCommitByLineAdd(ctx context.Context, eventTime time.Time, endTime *time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, eventCategory string, reason string) ([]msCommitEvents, error) {
 	var data = []msCommitEvents{}
+	if err := checkEventInterval(eventTime, endTime); err != nil {
+		return data, err
+	}
 
-	eventsCommit, err := s.repositoryEven.EventsCommitByLineAdd(eventTime, factory, prodline, system, machine, part, eventTypt, eventCategory)
+	eventsCommit, err := s.repositoryEven.EventsCommitByLineAdd(eventTime, endTime, factory, prodline, system, machine, part, eventTypt, eventCategory, auditFrom(ctx, reason))
 	if err != nil {
 		log.Println("Error añadir Service EventsCommitByLineAdd:", err)
 		return data, err
 	}
 
 	log.Println("Events Commit:", eventsCommit)
+	s.publishCommit(sLive.ActionCreated, eventsCommit...)
 
 	return data, nil
 }
 
-func (s *service) EventsCommitByLineUpdate(id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, eventCategory string) ([]msCommitEvents, error) {
+func (s *service) EventsCommitByLineUpdate(ctx context.Context, id uint, eventTime time.Time, endTime *time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, eventCategory string, reason string) ([]msCommitEvents, error) {
 	var data = []msCommitEvents{}
+	if err := checkEventInterval(eventTime, endTime); err != nil {
+		return data, err
+	}
 
-	eventsCommit, err := s.repositoryEven.EventsCommitByLineUpdate(id, eventTime, factory, prodline, system, machine, part, eventTypt, eventCategory)
+	eventsCommit, err := s.repositoryEven.EventsCommitByLineUpdate(id, eventTime, endTime, factory, prodline, system, machine, part, eventTypt, eventCategory, auditFrom(ctx, reason))
 	if err != nil {
 		log.Println("Error actualizar Service EventsCommitByLineUpdate:", err)
 		return data, err
 	}
 
 	log.Println("Events Commit:", eventsCommit)
+	s.publishCommitByID(sLive.ActionUpdated, id)
 
 	return data, nil
 }
 
-func (s *service) EventsCommitByLineDel(id uint) ([]msCommitEvents, error) {
+func (s *service) EventsCommitByLineDel(ctx context.Context, id uint, reason string) ([]msCommitEvents, error) {
 	var data = []msCommitEvents{}
 
-	eventsCommit, err := s.repositoryEven.EventsCommitByLineDel(id)
+	deleted, err := s.repositoryEven.EventsCommitByIDs([]uint{id})
+	if err != nil {
+		log.Println("Error lectura evento Service EventsCommitByLineDel:", err)
+		return data, err
+	}
+	eventsCommit, err := s.repositoryEven.EventsCommitByLineDel(id, auditFrom(ctx, reason))
 	if err != nil {
 		log.Println("Error borrar Service EventsCommitByLineDel:", err)
 		return data, err
 	}
 
 	log.Println("Events Commit:", eventsCommit)
+	s.publishCommit(sLive.ActionDeleted, deleted...)
 
 	return data, nil
 }
+
+func toMsCommitEvent(p rModels.MrCommitEvents, now time.Time) msCommitEvents {
+	return msCommitEvents{
+		EventTime:   p.EventTime,
+		EndTime:     p.EndTime,
+		Duration:    eventDuration(p, now).Seconds(),
+		Type:        p.EventType,
+		Category:    p.EventCategory,
+		Factory:     p.Factory,
+		ProdLine:    p.ProdLine,
+		System:      p.System,
+		Machine:     p.Machine,
+		Part:        p.Part,
+		ID:          p.ID,
+		WorkOrderID: p.WorkOrderID,
+		MaintError:  p.MaintError,
+	}
+}
@@ -0,0 +1,449 @@
+package sEvents
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCloseInterval = time.Minute
+	defaultCloseLookback = 24 * time.Hour
+	// throughputWindow agregación de la señal de throughput al buscar el rearranque de la línea
+	throughputWindow = time.Minute
+	unassignedKey    = "Unassigned"
+)
+
+// Agrupaciones del informe de paradas
+const (
+	DowntimeByLine     = "line"
+	DowntimeByShift    = "shift"
+	DowntimeByCategory = "category"
+	DowntimeByOrder    = "order"
+	DowntimeByType     = "type"
+	DowntimeBySystem   = "system"
+	DowntimeByMachine  = "machine"
+	DowntimeByPart     = "part"
+)
+
+type msDowntimeTotal struct {
+	Key     string  `json:"Key"`     // Línea, turno, categoría u orden de fabricación
+	Events  int     `json:"Events"`  // Eventos que aportan tiempo a la clave
+	Open    int     `json:"Open"`    // De ellos, los que siguen abiertos
+	Seconds float64 `json:"Seconds"` // Tiempo de parada dentro del intervalo consultado
+}
+
+type msDowntimeReport struct {
+	Factory  string            `json:"Factory"`
+	ProdLine string            `json:"ProdLine"`
+	From     time.Time         `json:"From"`
+	To       time.Time         `json:"To"`
+	GroupBy  string            `json:"GroupBy"`
+	Events   int               `json:"Events"`
+	Seconds  float64           `json:"Seconds"`
+	Totals   []msDowntimeTotal `json:"Totals"` // Ordenados de mayor a menor tiempo de parada
+}
+
+// downtimeSlice parte del tiempo de un evento atribuida a una clave
+type downtimeSlice struct {
+	key      string
+	duration time.Duration
+}
+
+// splitFunc reparte el tramo [start, end) de un evento entre las claves de una agrupación
+type splitFunc func(event rModels.MrCommitEvents, start time.Time, end time.Time) ([]downtimeSlice, error)
+
+// EventsCommitByLineClose cierra manualmente un evento abierto
+func (s *service) EventsCommitByLineClose(ctx context.Context, id uint, endTime time.Time, reason string) error {
+	closed, err := s.repositoryEven.EventsCommitClose(id, endTime, auditFrom(ctx, reason))
+	if err != nil {
+		return err
+	}
+	if !closed {
+		return fmt.Errorf("%w: event %d does not exist, is already closed or starts after %s", ErrNotFound, id, endTime.Format(time.RFC3339))
+	}
+	s.publishCommitByID(sLive.ActionClosed, id)
+	return nil
+}
+
+// GetDowntimeTotals suma el tiempo de parada de la fábrica en el intervalo agrupado por línea, turno,
+// categoría u orden de fabricación. Los eventos se recortan al intervalo y los abiertos cuentan hasta ahora.
+func (s *service) GetDowntimeTotals(factory string, prodLine string, from time.Time, to time.Time, groupBy string) (*msDowntimeReport, error) {
+	now := time.Now()
+	from, to, err := checkDowntimeRange(factory, from, to, now)
+	if err != nil {
+		return nil, err
+	}
+
+	switch groupBy {
+	case DowntimeByLine, DowntimeByShift, DowntimeByCategory, DowntimeByOrder:
+	default:
+		return nil, fmt.Errorf("%w: group_by must be one of line, shift, category, order", ErrInvalidRequest)
+	}
+	split, err := s.downtimeSplit(factory, groupBy, now)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repositoryEven.EventsCommitInRange(factory, prodLine, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, count, total, err := aggregateDowntime(events, from, to, now, split)
+	if err != nil {
+		return nil, err
+	}
+	sortByDowntime(totals)
+
+	return &msDowntimeReport{
+		Factory:  factory,
+		ProdLine: prodLine,
+		From:     from,
+		To:       to,
+		GroupBy:  groupBy,
+		Events:   count,
+		Seconds:  total.Seconds(),
+		Totals:   totals,
+	}, nil
+}
+
+// checkDowntimeRange valida los filtros comunes de los informes de paradas y limita el fin a now
+func checkDowntimeRange(factory string, from time.Time, to time.Time, now time.Time) (time.Time, time.Time, error) {
+	if factory == "" {
+		return from, to, fmt.Errorf("%w: factory is required", ErrInvalidRequest)
+	}
+	if !to.After(from) {
+		return from, to, fmt.Errorf("%w: to must be after from", ErrInvalidRequest)
+	}
+	if to.After(now) {
+		to = now
+	}
+	return from, to, nil
+}
+
+// downtimeSplit devuelve la función que reparte el tiempo de un evento entre las claves de la agrupación
+func (s *service) downtimeSplit(factory string, groupBy string, now time.Time) (splitFunc, error) {
+	byField := func(field func(event rModels.MrCommitEvents) string, empty string) splitFunc {
+		return func(event rModels.MrCommitEvents, start time.Time, end time.Time) ([]downtimeSlice, error) {
+			key := field(event)
+			if key == "" {
+				key = empty
+			}
+			return []downtimeSlice{{key: key, duration: end.Sub(start)}}, nil
+		}
+	}
+
+	switch groupBy {
+	case DowntimeByLine:
+		return byField(func(event rModels.MrCommitEvents) string { return event.ProdLine }, unassignedKey), nil
+	case DowntimeByCategory:
+		return byField(func(event rModels.MrCommitEvents) string { return event.EventCategory }, "Uncategorized"), nil
+	case DowntimeByType:
+		return byField(func(event rModels.MrCommitEvents) string { return event.EventType }, unassignedKey), nil
+	case DowntimeBySystem:
+		return byField(func(event rModels.MrCommitEvents) string { return event.System }, unassignedKey), nil
+	case DowntimeByMachine:
+		return byField(func(event rModels.MrCommitEvents) string { return event.Machine }, unassignedKey), nil
+	case DowntimeByPart:
+		return byField(func(event rModels.MrCommitEvents) string { return event.Part }, unassignedKey), nil
+	case DowntimeByShift:
+		shifts, err := s.repositoryLabor.GetAllShifts()
+		if err != nil {
+			return nil, err
+		}
+		return func(event rModels.MrCommitEvents, start time.Time, end time.Time) ([]downtimeSlice, error) {
+			return splitByShift(shifts, start, end), nil
+		}, nil
+	case DowntimeByOrder:
+		orders := make(map[string][]rModels.MrProductionOrder)
+		return func(event rModels.MrCommitEvents, start time.Time, end time.Time) ([]downtimeSlice, error) {
+			lineOrders, ok := orders[event.ProdLine]
+			if !ok {
+				var err error
+				if lineOrders, err = s.repositoryOrd.LineOrdersFind(factory, event.ProdLine); err != nil {
+					return nil, err
+				}
+				orders[event.ProdLine] = lineOrders
+			}
+			return splitByOrder(lineOrders, start, end, now), nil
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown downtime grouping %q", ErrInvalidRequest, groupBy)
+}
+
+// aggregateDowntime recorta los eventos al intervalo y acumula su tiempo por clave. Devuelve también
+// el número de eventos con tiempo en el intervalo y el tiempo total de parada.
+func aggregateDowntime(events []rModels.MrCommitEvents, from time.Time, to time.Time, now time.Time, split splitFunc) ([]msDowntimeTotal, int, time.Duration, error) {
+	totals := make(map[string]*msDowntimeTotal)
+	var keys []string
+	var count int
+	var total time.Duration
+	for _, event := range events {
+		start, end := event.EventTime, now
+		if event.EndTime != nil {
+			end = *event.EndTime
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		slices, err := split(event, start, end)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		count++
+		total += end.Sub(start)
+		for _, slice := range slices {
+			if slice.duration <= 0 {
+				continue
+			}
+			item, ok := totals[slice.key]
+			if !ok {
+				item = &msDowntimeTotal{Key: slice.key}
+				totals[slice.key] = item
+				keys = append(keys, slice.key)
+			}
+			item.Events++
+			if event.EndTime == nil {
+				item.Open++
+			}
+			item.Seconds += slice.duration.Seconds()
+		}
+	}
+
+	result := make([]msDowntimeTotal, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *totals[key])
+	}
+	return result, count, total, nil
+}
+
+// sortByDowntime ordena de mayor a menor tiempo de parada
+func sortByDowntime(totals []msDowntimeTotal) {
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Seconds != totals[j].Seconds {
+			return totals[i].Seconds > totals[j].Seconds
+		}
+		return totals[i].Key < totals[j].Key
+	})
+}
+
+// StartStopEventCloser cierra periódicamente los eventos abiertos de las líneas que han vuelto a
+// producir, hasta que se cancela el contexto
+func (s *service) StartStopEventCloser(ctx context.Context) {
+	cfg := s.config.Events
+	if !cfg.CloseEnabled {
+		s.logger.Info("Cierre automático de paradas deshabilitado")
+		return
+	}
+
+	interval := cfg.CloseInterval
+	if interval <= 0 {
+		interval = defaultCloseInterval
+	}
+
+	s.logger.Info("Iniciando cierre automático de paradas", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Deteniendo cierre automático de paradas")
+			return
+		case <-ticker.C:
+			s.runStopEventCloser(time.Now())
+		}
+	}
+}
+
+func (s *service) runStopEventCloser(now time.Time) {
+	lookback := s.config.Events.CloseLookback
+	if lookback <= 0 {
+		lookback = defaultCloseLookback
+	}
+
+	// Las paradas más antiguas que la ventana se cierran a mano
+	events, err := s.repositoryEven.EventsCommitOpen(now.Add(-lookback))
+	if err != nil {
+		s.logger.Error("Error getting open stop events", zap.Error(err))
+		return
+	}
+
+	lines := make(map[[2]string][]rModels.MrCommitEvents)
+	for _, event := range events {
+		key := [2]string{event.Factory, event.ProdLine}
+		lines[key] = append(lines[key], event)
+	}
+
+	for line, lineEvents := range lines {
+		if err := s.closeLineEvents(line[0], line[1], lineEvents, now); err != nil {
+			s.logger.Warn("Cannot close stop events",
+				zap.String("factory", line[0]),
+				zap.String("prod_line", line[1]),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// closeLineEvents cierra los eventos abiertos de la línea si vuelve a producir según la misma señal que
+// GetLineStatus: datos recientes y throughput positivo. El fin de cada evento es el rearranque posterior
+// a su inicio que muestra el histórico de throughput.
+func (s *service) closeLineEvents(factory string, prodLine string, events []rModels.MrCommitEvents, now time.Time) error {
+	status, throughput, err := s.repositoryInflux.GetLineStatus(factory, prodLine)
+	if err != nil {
+		return err
+	}
+	if status != "operativa" || throughput <= 0 {
+		return nil
+	}
+
+	// Los eventos llegan ordenados por inicio, así que el primero marca el inicio del histórico
+	series, err := s.repositoryInflux.GetLineThroughput(factory, prodLine, events[0].EventTime, now, throughputWindow.String())
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		end := recoveryTime(series, event.EventTime, now)
+		closed, err := s.repositoryEven.EventsCommitClose(event.ID, end, systemAudit("Closed automatically when the line resumed production"))
+		if err != nil {
+			return err
+		}
+		if closed {
+			s.logger.Info("Stop event closed",
+				zap.Uint("event_id", event.ID),
+				zap.String("factory", factory),
+				zap.String("prod_line", prodLine),
+				zap.Duration("duration", end.Sub(event.EventTime)),
+			)
+			event.EndTime = &end
+			s.publishCommit(sLive.ActionClosed, event)
+		}
+	}
+	return nil
+}
+
+// recoveryTime busca el primer rearranque de la línea posterior al inicio del evento: la primera lectura
+// con throughput tras una lectura a cero o un hueco sin datos. Las lecturas positivas justo después del
+// inicio son la producción que se estaba deteniendo. Sin rearranque en el histórico se usa now.
+func recoveryTime(series []riInfluxdb.ThroughputData, start time.Time, now time.Time) time.Time {
+	const gap = 2 * throughputWindow
+
+	stopped := false
+	previous := start
+	for _, point := range series {
+		if !point.Time.After(start) {
+			continue
+		}
+		if point.Value <= 0 {
+			stopped = true
+		} else if stopped || point.Time.Sub(previous) > gap {
+			if point.Time.After(now) {
+				break
+			}
+			return point.Time
+		}
+		previous = point.Time
+	}
+	return now
+}
+
+// eventDuration tiempo de parada del evento; los abiertos se miden hasta now
+func eventDuration(event rModels.MrCommitEvents, now time.Time) time.Duration {
+	end := now
+	if event.EndTime != nil {
+		end = *event.EndTime
+	}
+	if end.Before(event.EventTime) {
+		return 0
+	}
+	return end.Sub(event.EventTime)
+}
+
+func checkEventInterval(eventTime time.Time, endTime *time.Time) error {
+	if endTime != nil && endTime.Before(eventTime) {
+		return fmt.Errorf("%w: end time is before event time", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// splitByShift reparte el intervalo entre las ventanas diarias de los turnos; el tiempo fuera de todos
+// ellos queda sin asignar. Si los turnos se solapan, el tiempo común cuenta solo para el primero.
+func splitByShift(shifts []rModels.MrShift, start time.Time, end time.Time) []downtimeSlice {
+	var slices []downtimeSlice
+	remaining := end.Sub(start)
+	for i := range shifts {
+		inside := shifts[i].Overlap(start, end, 0)
+		if inside > remaining {
+			inside = remaining
+		}
+		if inside <= 0 {
+			continue
+		}
+		slices = append(slices, downtimeSlice{key: shifts[i].Name, duration: inside})
+		remaining -= inside
+	}
+	if remaining > 0 {
+		slices = append(slices, downtimeSlice{key: unassignedKey, duration: remaining})
+	}
+	return slices
+}
+
+// splitByOrder reparte el intervalo entre las órdenes de fabricación de la línea en curso en cada
+// momento; las órdenes sin fin siguen en curso hasta now
+func splitByOrder(orders []rModels.MrProductionOrder, start time.Time, end time.Time, now time.Time) []downtimeSlice {
+	var slices []downtimeSlice
+	remaining := end.Sub(start)
+	for _, order := range orders {
+		if order.StarteddAt.IsZero() {
+			continue
+		}
+		orderEnd := order.FinishedAt
+		if orderEnd.IsZero() || orderEnd.Before(order.StarteddAt) {
+			orderEnd = now
+		}
+		inside := overlap(start, end, order.StarteddAt, orderEnd)
+		if inside > remaining {
+			inside = remaining
+		}
+		if inside <= 0 {
+			continue
+		}
+		slices = append(slices, downtimeSlice{key: strings.TrimSpace(order.OrderNumber), duration: inside})
+		remaining -= inside
+	}
+	if remaining > 0 {
+		slices = append(slices, downtimeSlice{key: unassignedKey, duration: remaining})
+	}
+	return slices
+}
+
+func overlap(start time.Time, end time.Time, windowStart time.Time, windowEnd time.Time) time.Duration {
+	if windowStart.After(start) {
+		start = windowStart
+	}
+	if windowEnd.Before(end) {
+		end = windowEnd
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
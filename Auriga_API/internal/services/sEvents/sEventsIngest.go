@@ -0,0 +1,151 @@
+package sEvents
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultIngestInterval = 30 * time.Second
+	defaultIngestLookback = 10 * time.Minute
+)
+
+var defaultStatusFields = []string{"ESW"}
+
+// StartRawEventIngestion arranca un proceso de ingesta por fábrica que vigila las palabras de estado en
+// InfluxDB y registra en MrRawEvents las entradas en parada o fallo, hasta que se cancela el contexto
+func (s *service) StartRawEventIngestion(ctx context.Context) {
+	cfg := s.config.Events
+	if !cfg.IngestEnabled {
+		s.logger.Info("Ingesta de eventos brutos deshabilitada")
+		return
+	}
+
+	interval := cfg.IngestInterval
+	if interval <= 0 {
+		interval = defaultIngestInterval
+	}
+	// Las ventanas consecutivas se solapan para no perder transiciones en el borde; la deduplicación
+	// descarta las que ya se registraron
+	lookback := cfg.IngestLookback
+	if lookback <= 0 {
+		lookback = defaultIngestLookback
+	}
+	if lookback < 2*interval {
+		lookback = 2 * interval
+	}
+
+	factories := cfg.IngestFactories
+	if len(factories) == 0 {
+		for factory := range s.config.InfluxDBs {
+			factories = append(factories, factory)
+		}
+		sort.Strings(factories)
+	}
+
+	for _, factory := range factories {
+		go s.ingestFactoryEvents(ctx, factory, interval, lookback)
+	}
+}
+
+func (s *service) ingestFactoryEvents(ctx context.Context, factory string, interval time.Duration, lookback time.Duration) {
+	logger := s.logger.With(zap.String("factory", factory))
+	logger.Info("Iniciando ingesta de eventos brutos",
+		zap.Duration("interval", interval),
+		zap.Duration("lookback", lookback),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Deteniendo ingesta de eventos brutos")
+			return
+		case <-ticker.C:
+			s.runRawEventIngestion(factory, time.Now(), lookback)
+		}
+	}
+}
+
+func (s *service) runRawEventIngestion(factory string, now time.Time, lookback time.Duration) {
+	fields := s.config.Events.StatusFields
+	if len(fields) == 0 {
+		fields = defaultStatusFields
+	}
+
+	words, err := s.repositoryInflux.GetStatusWords(factory, fields, now.Add(-lookback), now)
+	if err != nil {
+		s.logger.Warn("Cannot read status words", zap.String("factory", factory), zap.Error(err))
+		return
+	}
+
+	events := detectStopTransitions(factory, words, s.config.Events.StopCodes)
+	if len(events) == 0 {
+		return
+	}
+
+	inserted, err := s.repositoryEven.EventsRawCreate(events, systemAudit("Raw event ingestion"))
+	if err != nil {
+		s.logger.Error("Error storing raw events", zap.String("factory", factory), zap.Error(err))
+		return
+	}
+	if len(inserted) > 0 {
+		s.logger.Info("Raw events ingested",
+			zap.String("factory", factory),
+			zap.Int("detected", len(events)),
+			zap.Int("inserted", len(inserted)),
+		)
+		s.publishRaw(sLive.ActionCreated, inserted...)
+	}
+}
+
+// detectStopTransitions genera un evento por cada cambio de una señal a un estado de parada o fallo.
+// Las lecturas llegan agrupadas por señal y ordenadas por tiempo; la primera de cada señal solo fija
+// el estado de partida. Sin códigos configurados, cualquier valor distinto de 0 es parada.
+func detectStopTransitions(factory string, words []riInfluxdb.StatusWord, stopCodes []int) []rModels.MrRawEvents {
+	isStop := func(value int) bool {
+		if len(stopCodes) == 0 {
+			return value != 0
+		}
+		for _, code := range stopCodes {
+			if value == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	var events []rModels.MrRawEvents
+	previous := make(map[string]int)
+	for _, word := range words {
+		key := strings.Join([]string{word.Name, word.EL_Lv1, word.EL_Lv2, word.EL_Lv3, word.Field}, "/")
+		last, seen := previous[key]
+		previous[key] = word.Value
+		if !seen || last == word.Value || !isStop(word.Value) {
+			continue
+		}
+
+		events = append(events, rModels.MrRawEvents{
+			Time:    word.Time.Truncate(time.Microsecond), // Precisión de PostgreSQL, para que la deduplicación compare igual
+			ESW:     word.Value,
+			EL_Lv0:  factory,
+			Name:    word.Name,
+			EL_Lv1:  word.EL_Lv1,
+			EL_Lv2:  word.EL_Lv2,
+			EL_Lv3:  word.EL_Lv3,
+			Host:    word.Host,
+			SlaveID: word.SlaveID,
+			Type:    word.Type,
+		})
+	}
+	return events
+}
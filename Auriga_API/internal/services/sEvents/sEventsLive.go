@@ -0,0 +1,57 @@
+package sEvents
+
+import (
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"go.uber.org/zap"
+)
+
+// publishRaw difunde los cambios de la cola de eventos brutos a los clientes suscritos a la línea
+func (s *service) publishRaw(action string, events ...rModels.MrRawEvents) {
+	if len(events) == 0 {
+		return
+	}
+	var rules []rModels.MrEventClassificationRule
+	if action == sLive.ActionCreated {
+		var err error
+		if rules, err = s.repositoryEven.GetActiveClassificationRules(); err != nil {
+			s.logger.Warn("Cannot get classification rules for live raw events", zap.Error(err))
+		}
+	}
+	for _, event := range events {
+		s.serviceLive.Publish(sLive.Message{
+			Type:     sLive.TypeRawEvent,
+			Action:   action,
+			Factory:  event.EL_Lv0,
+			ProdLine: event.Name,
+			Data:     toMsRawEvent(event, rules),
+		})
+	}
+}
+
+// publishCommit difunde los cambios de los eventos confirmados; en los borrados envía el evento tal
+// como estaba antes de borrarlo
+func (s *service) publishCommit(action string, events ...rModels.MrCommitEvents) {
+	now := time.Now()
+	for _, event := range events {
+		s.serviceLive.Publish(sLive.Message{
+			Type:     sLive.TypeEventCommit,
+			Action:   action,
+			Factory:  event.Factory,
+			ProdLine: event.ProdLine,
+			Data:     toMsCommitEvent(event, now),
+		})
+	}
+}
+
+// publishCommitByID relee los eventos tras modificarlos y los difunde
+func (s *service) publishCommitByID(action string, ids ...uint) {
+	events, err := s.repositoryEven.EventsCommitByIDs(ids)
+	if err != nil {
+		s.logger.Warn("Cannot read events for live subscribers", zap.Error(err))
+		return
+	}
+	s.publishCommit(action, events...)
+}
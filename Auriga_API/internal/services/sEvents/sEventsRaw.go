@@ -1,8 +1,12 @@
 package sEvents
 
 import (
+	"context"
 	"log"
 	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
 )
 
 type msRawEvents struct {
@@ -14,6 +18,9 @@ type msRawEvents struct {
 	Machine   string    `json:"Machine"`
 	Part      string    `json:"Part"`
 	ID        uint      `json:"ID"`
+	// Clasificación propuesta por las reglas para los eventos que el clasificador no confirma solo
+	SuggestedType     string `json:"SuggestedType"`
+	SuggestedCategory string `json:"SuggestedCategory"`
 }
 
 func (s *service) EventsRawByLineList(factory string, lineNumber string) ([]msRawEvents, error) {
@@ -25,46 +32,75 @@ func (s *service) EventsRawByLineList(factory string, lineNumber string) ([]msRa
 		log.Println("Error lectura listado de Eventos Raw Service EventsRawByLineList:", err)
 		return data, err
 	}
+	rules, err := s.repositoryEven.GetActiveClassificationRules()
+	if err != nil {
+		log.Println("Error lectura reglas de clasificación Service EventsRawByLineList:", err)
+	}
 	for _, p := range rawEventData {
-		data = append(data, msRawEvents{
-			EventTime: p.Time,
-			Event:     p.ESW,
-			Factory:   p.EL_Lv0,
-			ProdLine:  p.Name,
-			System:    p.EL_Lv1,
-			Machine:   p.EL_Lv2,
-			Part:      p.EL_Lv3,
-			ID:        p.ID,
-		})
+		data = append(data, toMsRawEvent(p, rules))
 	}
 
 	return data, nil
 }
 
-func (s *service) EventsRawToCommitLine(id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string) ([]msCommitEvents, error) {
+func (s *service) EventsRawToCommitLine(ctx context.Context, id uint, eventTime time.Time, factory string, prodline string, system string, machine string, part string, eventTypt string, reason string) ([]msCommitEvents, error) {
 	var data = []msCommitEvents{}
 
-	eventsCommit, err := s.repositoryEven.EventsRawToCommitLine(id, eventTime, factory, prodline, system, machine, part, eventTypt)
+	rawEvents, err := s.repositoryEven.EventsRawByIDs([]uint{id})
+	if err != nil {
+		log.Println("Error lectura evento Raw Service EventsRawToCommitLine:", err)
+		return data, err
+	}
+	eventsCommit, err := s.repositoryEven.EventsRawToCommitLine(id, eventTime, factory, prodline, system, machine, part, eventTypt, auditFrom(ctx, reason))
 	if err != nil {
 		log.Println("Error actualizar Service EventsCommitByLineUpdate:", err)
 		return data, err
 	}
 
 	log.Println("Events Commit:", eventsCommit)
+	if len(eventsCommit) > 0 {
+		s.publishRaw(sLive.ActionDeleted, rawEvents...)
+		s.publishCommit(sLive.ActionCreated, eventsCommit...)
+	}
 
 	return data, nil
 }
 
-func (s *service) EventsRawByLineDel(id uint) ([]msRawEvents, error) {
+func (s *service) EventsRawByLineDel(ctx context.Context, id uint, reason string) ([]msRawEvents, error) {
 	var data = []msRawEvents{}
 
-	eventsRaw, err := s.repositoryEven.EventsRawByLineDel(id)
+	rawEvents, err := s.repositoryEven.EventsRawByIDs([]uint{id})
+	if err != nil {
+		log.Println("Error lectura evento Raw Service EventsRawByLineDel:", err)
+		return data, err
+	}
+	eventsRaw, err := s.repositoryEven.EventsRawByLineDel(id, auditFrom(ctx, reason))
 	if err != nil {
 		log.Println("Error borrar Service EventsCommitByLineDel:", err)
 		return data, err
 	}
 
 	log.Println("Events Commit:", eventsRaw)
+	s.publishRaw(sLive.ActionDeleted, rawEvents...)
 
 	return data, nil
 }
+
+// toMsRawEvent incluye la clasificación que proponen las reglas, si alguna encaja
+func toMsRawEvent(p rModels.MrRawEvents, rules []rModels.MrEventClassificationRule) msRawEvents {
+	event := msRawEvents{
+		EventTime: p.Time,
+		Event:     p.ESW,
+		Factory:   p.EL_Lv0,
+		ProdLine:  p.Name,
+		System:    p.EL_Lv1,
+		Machine:   p.EL_Lv2,
+		Part:      p.EL_Lv3,
+		ID:        p.ID,
+	}
+	if rule, _ := matchClassificationRule(rules, p); rule != nil {
+		event.SuggestedType = rule.EventType.Name
+		event.SuggestedCategory = rule.EventType.Category.Name
+	}
+	return event
+}
@@ -1,19 +1,35 @@
 package sEvents
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rEvents"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
 )
 
-func (s *service) EventsSapByLineDel(id uint) ([]msCommitEvents, error) {
+// EventsSapByLineDel retira el evento confirmado y encola su envío a SAP; la entrega la hace
+// StartSapOutboxDelivery con reintentos. El operario es el usuario autenticado, que debe tener WorkdayID.
+func (s *service) EventsSapByLineDel(ctx context.Context, id uint) ([]msCommitEvents, error) {
 	var data = []msCommitEvents{}
 
+	operator, err := s.serviceLabor.ResolveOperator(ctx, time.Now())
+	if err != nil {
+		log.Println("Operario no identificado Service EventsSapByLineDel:", err)
+		return data, err
+	}
+
 	// Leo el evento por ID de la base de datos
 	eventCommit, orderNumber, err := s.repositoryEven.EventsCommitByLineFind(id)
 	if err != nil {
 		log.Println("Error borrar Service EventsCommitByLineDel:", err)
 		return data, err
 	}
+
 	fmt.Println("Events Commit Time:", eventCommit.EventTime)
 	fmt.Println("Events Commit Factory:", eventCommit.Factory)
 	fmt.Println("Events Commit ProdLine:", eventCommit.ProdLine)
@@ -30,25 +46,42 @@ func (s *service) EventsSapByLineDel(id uint) ([]msCommitEvents, error) {
 		log.Println("Activo no detectado Service DosingConsumptionList:", err)
 		return data, err
 	}
-
 	fmt.Println("El código SAP de la línea es:", assetOrder.SapCode)
 
-	// Aquí implemento la función de envío a SAP
-	err = s.repositorySap.RsLineStopEvent(assetOrder.SapCode, eventCommit.EventTime, eventCommit.EventType, eventCommit.EventCategory, orderNumber, "operario")
-	if err != nil {
-		log.Println("Error lectura listado de ordenes SAP LineOrderList:", err)
-		return data, err
+	key := idempotencyKey(id)
+	message := &rModels.MrSapOutbox{
+		IdempotencyKey: key,
+		EventID:        id,
+		Factory:        eventCommit.Factory,
+		ProdLine:       eventCommit.ProdLine,
+		Maquina:        assetOrder.SapCode,
+		EventTime:      eventCommit.EventTime,
+		Estado:         eventCommit.EventType,
+		Motivo:         eventCommit.EventCategory,
+		OrderNumber:    orderNumber,
+		Operario:       operator.WorkdayID,
+		Status:         rModels.SapOutboxPending,
+		NextAttemptAt:  time.Now(),
 	}
 
-	// Borro el evento de la base de datos
-
-	eventsCommitReturn, err := s.repositoryEven.EventsCommitByLineDel(id)
-	if err != nil {
-		log.Println("Error borrar Service EventsCommitByLineDel:", err)
+	// Retiro el evento y encolo el envío en la misma transacción
+	if err := s.repositoryEven.EventsCommitToSapOutbox(id, message, auditFrom(ctx, "Sent to SAP")); err != nil {
+		log.Println("Error encolar envío SAP Service EventsSapByLineDel:", err)
+		if errors.Is(err, rEvents.ErrEventChanged) {
+			return data, fmt.Errorf("%w: event %d was already sent or deleted", ErrConflict, id)
+		}
+		if errors.Is(err, rEvents.ErrSentToSap) {
+			return data, fmt.Errorf("%w: event %d already has a pending or delivered SAP message", ErrConflict, id)
+		}
 		return data, err
 	}
-
-	log.Println("Events Commit:", eventsCommitReturn)
-
+	log.Println("Evento", id, "encolado para SAP con clave", key)
+	s.publishCommit(sLive.ActionDeleted, eventCommit)
 	return data, nil
 }
+
+// idempotencyKey clave del envío del evento; es la misma en todos los reintentos y reenvíos, así que SAP
+// puede reconocer los duplicados
+func idempotencyKey(eventID uint) string {
+	return fmt.Sprintf("auriga-stop-%d", eventID)
+}
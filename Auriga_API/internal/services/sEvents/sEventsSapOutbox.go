@@ -0,0 +1,222 @@
+package sEvents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rEvents"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rsSap"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultOutboxInterval    = 15 * time.Second
+	defaultOutboxBatchSize   = 20
+	defaultOutboxMaxAttempts = 10
+	defaultOutboxBackoffBase = 30 * time.Second
+	defaultOutboxBackoffMax  = time.Hour
+	defaultOutboxTimeout     = 30 * time.Second
+	// outboxResponseLimit bytes de la respuesta de SAP que se guardan por intento
+	outboxResponseLimit = 16 << 10
+	outboxListLimit     = 500
+)
+
+// DiscardSapOutboxRequest descartar un mensaje exige indicar el motivo
+type DiscardSapOutboxRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ListSapOutbox mensajes en cola (pending), entregados, muertos (dead) o descartados
+func (s *service) ListSapOutbox(status string, factory string) ([]rModels.MrSapOutbox, error) {
+	switch rModels.SapOutboxStatus(status) {
+	case rModels.SapOutboxPending, rModels.SapOutboxDelivered, rModels.SapOutboxDead, rModels.SapOutboxDiscarded:
+	default:
+		return nil, fmt.Errorf("%w: unknown outbox status %q", ErrInvalidRequest, status)
+	}
+	return s.repositoryEven.SapOutboxList(rModels.SapOutboxStatus(status), factory, outboxListLimit)
+}
+
+// GetSapOutboxMessage devuelve el mensaje con todos sus intentos y las respuestas de SAP
+func (s *service) GetSapOutboxMessage(id uint) (*rModels.MrSapOutbox, error) {
+	message, err := s.repositoryEven.SapOutboxByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &message, nil
+}
+
+// RetrySapOutboxMessage reenvía en el siguiente ciclo un mensaje muerto o en espera de reintento
+func (s *service) RetrySapOutboxMessage(id uint) (*rModels.MrSapOutbox, error) {
+	message, err := s.repositoryEven.SapOutboxRetry(id, time.Now())
+	if err != nil {
+		return nil, outboxError(err, id)
+	}
+	s.logger.Info("SAP outbox message queued for retry", zap.Uint("outbox_id", id), zap.Uint("event_id", message.EventID))
+	return &message, nil
+}
+
+// DiscardSapOutboxMessage retira el mensaje sin enviarlo
+func (s *service) DiscardSapOutboxMessage(ctx context.Context, id uint, reason string) (*rModels.MrSapOutbox, error) {
+	audit := auditFrom(ctx, reason)
+	discardedBy := audit.UserName
+	if discardedBy == "" {
+		discardedBy = audit.UserID
+	}
+	message, err := s.repositoryEven.SapOutboxDiscard(id, discardedBy, reason)
+	if err != nil {
+		return nil, outboxError(err, id)
+	}
+	s.logger.Info("SAP outbox message discarded", zap.Uint("outbox_id", id), zap.Uint("event_id", message.EventID), zap.String("by", discardedBy))
+	return &message, nil
+}
+
+func outboxError(err error, id uint) error {
+	if errors.Is(err, rEvents.ErrOutboxState) {
+		return fmt.Errorf("%w: outbox message %d was already delivered or discarded", ErrConflict, id)
+	}
+	return notFound(err)
+}
+
+// StartSapOutboxDelivery entrega periódicamente a SAP las paradas encoladas, hasta que se cancela el
+// contexto. Cada fallo transitorio aplaza el mensaje con espera exponencial; un rechazo de SAP o agotar
+// los intentos lo pasa a dead letter.
+func (s *service) StartSapOutboxDelivery(ctx context.Context) {
+	cfg := s.config.SapOutbox
+	if !cfg.Enabled {
+		s.logger.Info("Envío de paradas a SAP deshabilitado")
+		return
+	}
+
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultOutboxInterval
+	}
+
+	s.logger.Info("Iniciando envío de paradas a SAP", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Deteniendo envío de paradas a SAP")
+			return
+		case <-ticker.C:
+			s.runSapOutboxDelivery(ctx)
+		}
+	}
+}
+
+func (s *service) runSapOutboxDelivery(ctx context.Context) {
+	cfg := s.config.SapOutbox
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultOutboxBatchSize
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultOutboxTimeout
+	}
+
+	// La reserva cubre el envío de todo el lote, uno detrás de otro
+	now := time.Now()
+	messages, err := s.repositoryEven.SapOutboxClaim(now, now.Add(timeout*time.Duration(batchSize+1)), batchSize)
+	if err != nil {
+		s.logger.Error("Error claiming SAP outbox messages", zap.Error(err))
+		return
+	}
+
+	for i := range messages {
+		if ctx.Err() != nil {
+			return
+		}
+		s.deliverSapOutbox(ctx, &messages[i], timeout)
+	}
+}
+
+func (s *service) deliverSapOutbox(ctx context.Context, message *rModels.MrSapOutbox, timeout time.Duration) {
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	response, err := s.repositorySap.RsLineStopEvent(callCtx, message.Maquina, message.EventTime, message.Estado, message.Motivo, message.OrderNumber, message.Operario, message.IdempotencyKey)
+	now := time.Now()
+
+	message.Attempts++
+	attempt := &rModels.MrSapOutboxAttempt{
+		OutboxID:     message.ID,
+		Attempt:      message.Attempts,
+		StatusCode:   response.StatusCode,
+		ResponseBody: truncate(response.Body, outboxResponseLimit),
+		DurationMs:   now.Sub(start).Milliseconds(),
+	}
+
+	fields := []zap.Field{
+		zap.Uint("outbox_id", message.ID),
+		zap.Uint("event_id", message.EventID),
+		zap.String("idempotency_key", message.IdempotencyKey),
+		zap.Int("attempt", message.Attempts),
+		zap.Int("status_code", response.StatusCode),
+	}
+	if err == nil {
+		message.Status = rModels.SapOutboxDelivered
+		message.DeliveredAt = &now
+		message.LastError = ""
+		s.logger.Info("Stop event delivered to SAP", fields...)
+	} else {
+		attempt.Error = err.Error()
+		message.LastError = err.Error()
+		// Un rechazo de SAP (fallo SOAP, RETURN de tipo E o un 4xx) se repetiría igual en cada intento
+		if !rsSap.IsTransient(err) || message.Attempts >= s.outboxMaxAttempts() {
+			message.Status = rModels.SapOutboxDead
+			s.logger.Error("SAP outbox message moved to dead letter", append(fields, zap.Bool("transient", rsSap.IsTransient(err)), zap.Error(err))...)
+		} else {
+			message.NextAttemptAt = now.Add(s.outboxBackoff(message.Attempts))
+			s.logger.Warn("SAP delivery failed, retry scheduled", append(fields, zap.Time("next_attempt_at", message.NextAttemptAt), zap.Error(err))...)
+		}
+	}
+
+	if err := s.repositoryEven.SapOutboxRecordAttempt(message, attempt); err != nil {
+		s.logger.Error("Error recording SAP outbox attempt", zap.Uint("outbox_id", message.ID), zap.Error(err))
+	}
+}
+
+func (s *service) outboxMaxAttempts() int {
+	if s.config.SapOutbox.MaxAttempts > 0 {
+		return s.config.SapOutbox.MaxAttempts
+	}
+	return defaultOutboxMaxAttempts
+}
+
+// outboxBackoff espera tras el intento n: base, 2·base, 4·base... hasta el máximo
+func (s *service) outboxBackoff(attempts int) time.Duration {
+	base := s.config.SapOutbox.BackoffBase
+	if base <= 0 {
+		base = defaultOutboxBackoffBase
+	}
+	max := s.config.SapOutbox.BackoffMax
+	if max <= 0 {
+		max = defaultOutboxBackoffMax
+	}
+	wait := base
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	// Sin cortar un carácter multibyte, PostgreSQL rechaza UTF-8 inválido
+	return strings.ToValidUTF8(value[:limit], "")
+}
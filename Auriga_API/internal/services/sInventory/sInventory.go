@@ -0,0 +1,184 @@
+package sInventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/config"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rInventory"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	GetStocks(req StockFilterRequest) ([]rModels.MrSparePartStock, error)
+	GetStockByID(id uint) (*rModels.MrSparePartStock, error)
+	GetMovements(req MovementFilterRequest) ([]rModels.MrAssetRegisterMovement, error)
+	GetExpiringStocks(req ExpiringStockRequest) ([]ExpiringStockReport, error)
+
+	ReceiveStock(req ReceiptRequest, createdBy uint) (*rModels.MrAssetRegisterMovement, error)
+	IssueStock(req IssueRequest, createdBy uint) ([]rModels.MrAssetRegisterMovement, error)
+	ReturnStock(req ReturnRequest, createdBy uint) (*rModels.MrAssetRegisterMovement, error)
+	TransferStock(req TransferRequest, createdBy uint) ([]rModels.MrAssetRegisterMovement, error)
+	AdjustStock(req AdjustmentRequest, createdBy uint) (*rModels.MrAssetRegisterMovement, error)
+
+	GetReorderProposals() ([]ReorderProposal, error)
+	GenerateReorderPurchaseOrders(createdBy uint) ([]rModels.MrPurchaseOrder, error)
+
+	GetPurchaseOrders(req PurchaseOrderFilterRequest) ([]rModels.MrPurchaseOrder, error)
+	GetPurchaseOrderByID(id uint) (*rModels.MrPurchaseOrder, error)
+	CreatePurchaseOrder(req CreatePurchaseOrderRequest, createdBy uint) (*rModels.MrPurchaseOrder, error)
+	ApprovePurchaseOrder(id uint, approvedBy uint) (*rModels.MrPurchaseOrder, error)
+	SendPurchaseOrder(id uint, req SendPurchaseOrderRequest) (*rModels.MrPurchaseOrder, error)
+	ReceivePurchaseOrder(id uint, req ReceivePurchaseOrderRequest, createdBy uint) (*rModels.MrPurchaseOrder, error)
+	CancelPurchaseOrder(id uint, req CancelPurchaseOrderRequest) (*rModels.MrPurchaseOrder, error)
+
+	StartReorderJob(ctx context.Context)
+}
+
+// Errores de dominio que los handlers traducen a códigos HTTP
+var (
+	ErrNotFound          = errors.New("record not found")
+	ErrInvalidRequest    = errors.New("invalid request")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidStatus     = errors.New("invalid purchase order status")
+	ErrExpiredBatch      = errors.New("expired batch")
+)
+
+// service implementación
+type service struct {
+	repository            rInventory.Repository
+	repositoryMaintenance rMaintenance.Repository
+	config                *config.Settings
+	logger                *zap.Logger
+}
+
+func New(repository rInventory.Repository, repositoryMaintenance rMaintenance.Repository, config *config.Settings, logger *zap.Logger) Service {
+	return &service{
+		repository:            repository,
+		repositoryMaintenance: repositoryMaintenance,
+		config:                config,
+		logger:                logger,
+	}
+}
+
+// FILTER DTOs
+type StockFilterRequest struct {
+	WarehouseAssetID uint `query:"warehouse_asset_id"`
+	ProductID        uint `query:"product_id"`
+}
+
+type ExpiringStockRequest struct {
+	WarehouseAssetID uint `query:"warehouse_asset_id"`
+	Days             *int `query:"days" validate:"omitempty,min=0"` // Horizonte; por defecto el configurado
+}
+
+type MovementFilterRequest struct {
+	ProductID        uint      `query:"product_id"`
+	WarehouseAssetID uint      `query:"warehouse_asset_id"`
+	WorkOrderID      uint      `query:"work_order_id"`
+	StockID          uint      `query:"stock_id"`
+	From             time.Time `query:"from"`
+	To               time.Time `query:"to"`
+}
+
+type PurchaseOrderFilterRequest struct {
+	WarehouseAssetID uint     `query:"warehouse_asset_id"`
+	Status           []string `query:"status"`
+	Supplier         string   `query:"supplier"`
+	AutoGenerated    *bool    `query:"auto_generated"`
+}
+
+// StockRef identifica el stock por ID o por almacén+producto+lote
+type StockRef struct {
+	StockID          *uint  `json:"stock_id"`
+	WarehouseAssetID uint   `json:"warehouse_asset_id" validate:"required_without=StockID"`
+	ProductID        uint   `json:"product_id" validate:"required_without=StockID"`
+	BatchNumber      string `json:"batch_number" validate:"max=100"`
+}
+
+// MOVEMENT DTOs
+type ReceiptRequest struct {
+	StockRef
+	ExpiryDate      *time.Time `json:"expiry_date"`
+	Location        string     `json:"location" validate:"max=100"`
+	Quantity        float64    `json:"quantity" validate:"required,gt=0"`
+	UnitCost        *float64   `json:"unit_cost" validate:"omitempty,min=0"`
+	ReferenceNumber string     `json:"reference_number" validate:"max=100"`
+	Reason          string     `json:"reason"`
+}
+
+type IssueRequest struct {
+	StockRef
+	WorkOrderID      uint    `json:"work_order_id" validate:"required,min=1"`
+	SparePartAssetID *uint   `json:"spare_part_asset_id"`
+	Quantity         float64 `json:"quantity" validate:"required,gt=0"`
+	ReferenceNumber  string  `json:"reference_number" validate:"max=100"`
+	Reason           string  `json:"reason"`
+}
+
+type ReturnRequest struct {
+	StockRef
+	WorkOrderID      uint    `json:"work_order_id" validate:"required,min=1"`
+	SparePartAssetID *uint   `json:"spare_part_asset_id"`
+	Quantity         float64 `json:"quantity" validate:"required,gt=0"`
+	ReferenceNumber  string  `json:"reference_number" validate:"max=100"`
+	Reason           string  `json:"reason"`
+}
+
+type TransferRequest struct {
+	StockRef
+	ToWarehouseAssetID uint    `json:"to_warehouse_asset_id" validate:"required,min=1"`
+	ToLocation         string  `json:"to_location" validate:"max=100"`
+	Quantity           float64 `json:"quantity" validate:"required,gt=0"`
+	ReferenceNumber    string  `json:"reference_number" validate:"max=100"`
+	Reason             string  `json:"reason"`
+}
+
+type AdjustmentRequest struct {
+	StockRef
+	Quantity        float64  `json:"quantity" validate:"required,ne=0"` // Variación con signo
+	UnitCost        *float64 `json:"unit_cost" validate:"omitempty,min=0"`
+	ReferenceNumber string   `json:"reference_number" validate:"max=100"`
+	Reason          string   `json:"reason" validate:"required,min=1"`
+}
+
+// PURCHASE ORDER DTOs
+type PurchaseOrderItemRequest struct {
+	ProductID uint    `json:"product_id" validate:"required,min=1"`
+	Quantity  float64 `json:"quantity" validate:"required,gt=0"`
+	UnitPrice float64 `json:"unit_price" validate:"min=0"`
+}
+
+type CreatePurchaseOrderRequest struct {
+	WarehouseAssetID uint                       `json:"warehouse_asset_id" validate:"required,min=1"`
+	OrderNumber      string                     `json:"order_number" validate:"max=100"`
+	Supplier         string                     `json:"supplier" validate:"required,min=1,max=255"`
+	ExpectedDate     time.Time                  `json:"expected_date"`
+	Items            []PurchaseOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type SendPurchaseOrderRequest struct {
+	ExpectedDate time.Time `json:"expected_date"`
+}
+
+type PurchaseReceiptItemRequest struct {
+	ItemID      uint       `json:"item_id" validate:"required,min=1"`
+	Quantity    float64    `json:"quantity" validate:"required,gt=0"`
+	UnitPrice   *float64   `json:"unit_price" validate:"omitempty,min=0"`
+	BatchNumber string     `json:"batch_number" validate:"max=100"`
+	ExpiryDate  *time.Time `json:"expiry_date"`
+	Location    string     `json:"location" validate:"max=100"`
+}
+
+type ReceivePurchaseOrderRequest struct {
+	ReferenceNumber string                       `json:"reference_number" validate:"max=100"` // Albarán del proveedor
+	Reason          string                       `json:"reason"`
+	Items           []PurchaseReceiptItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type CancelPurchaseOrderRequest struct {
+	Reason string `json:"reason" validate:"required,min=1"`
+}
@@ -0,0 +1,101 @@
+package sInventory
+
+import (
+	"fmt"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rInventory"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+func (s *service) ReceiveStock(req ReceiptRequest, createdBy uint) (*rModels.MrAssetRegisterMovement, error) {
+	in := movementInput(req.StockRef, req.Quantity, req.ReferenceNumber, req.Reason, createdBy)
+	in.ExpiryDate = req.ExpiryDate
+	in.Location = req.Location
+	in.UnitCost = req.UnitCost
+
+	movement, err := s.repository.Receive(in)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return movement, nil
+}
+
+// IssueStock entrega material a una OT. Sin stock_id ni lote se reparte entre lotes por caducidad (FEFO).
+func (s *service) IssueStock(req IssueRequest, createdBy uint) ([]rModels.MrAssetRegisterMovement, error) {
+	if err := s.checkWorkOrderOpen(req.WorkOrderID); err != nil {
+		return nil, err
+	}
+
+	in := movementInput(req.StockRef, req.Quantity, req.ReferenceNumber, req.Reason, createdBy)
+	in.WorkOrderID = &req.WorkOrderID
+	in.AssetID = req.SparePartAssetID
+
+	movements, err := s.repository.IssueToWorkOrder(in)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return movements, nil
+}
+
+func (s *service) ReturnStock(req ReturnRequest, createdBy uint) (*rModels.MrAssetRegisterMovement, error) {
+	if err := s.checkWorkOrderOpen(req.WorkOrderID); err != nil {
+		return nil, err
+	}
+
+	in := movementInput(req.StockRef, req.Quantity, req.ReferenceNumber, req.Reason, createdBy)
+	in.WorkOrderID = &req.WorkOrderID
+	in.AssetID = req.SparePartAssetID
+
+	movement, err := s.repository.ReturnFromWorkOrder(in)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return movement, nil
+}
+
+func (s *service) TransferStock(req TransferRequest, createdBy uint) ([]rModels.MrAssetRegisterMovement, error) {
+	in := movementInput(req.StockRef, req.Quantity, req.ReferenceNumber, req.Reason, createdBy)
+
+	movements, err := s.repository.Transfer(in, req.ToWarehouseAssetID, req.ToLocation)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return movements, nil
+}
+
+func (s *service) AdjustStock(req AdjustmentRequest, createdBy uint) (*rModels.MrAssetRegisterMovement, error) {
+	in := movementInput(req.StockRef, req.Quantity, req.ReferenceNumber, req.Reason, createdBy)
+	in.UnitCost = req.UnitCost
+
+	movement, err := s.repository.Adjust(in)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return movement, nil
+}
+
+func movementInput(ref StockRef, quantity float64, referenceNumber string, reason string, createdBy uint) rInventory.MovementInput {
+	return rInventory.MovementInput{
+		StockID:          ref.StockID,
+		WarehouseAssetID: ref.WarehouseAssetID,
+		ProductID:        ref.ProductID,
+		BatchNumber:      ref.BatchNumber,
+		Quantity:         quantity,
+		ReferenceNumber:  referenceNumber,
+		Reason:           reason,
+		CreatedBy:        createdBy,
+	}
+}
+
+// checkWorkOrderOpen impide mover material de OT verificadas, cerradas o canceladas
+func (s *service) checkWorkOrderOpen(workOrderID uint) error {
+	workOrder, err := s.repositoryMaintenance.GetWorkOrderByID(workOrderID)
+	if err != nil {
+		return domainError(err)
+	}
+
+	if workOrder.Status.IsFinal() {
+		return fmt.Errorf("%w: work order %d is %s", ErrInvalidRequest, workOrderID, workOrder.Status)
+	}
+	return nil
+}
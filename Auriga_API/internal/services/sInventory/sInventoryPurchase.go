@@ -0,0 +1,182 @@
+package sInventory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rInventory"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/utils"
+)
+
+// cancellablePurchaseOrderStatuses son los estados desde los que se puede anular una orden
+var cancellablePurchaseOrderStatuses = []rModels.PurchaseOrderStatus{
+	rModels.PurchaseOrderDraft,
+	rModels.PurchaseOrderApproved,
+	rModels.PurchaseOrderOrdered,
+	rModels.PurchaseOrderPartial,
+}
+
+func (s *service) GetPurchaseOrders(req PurchaseOrderFilterRequest) ([]rModels.MrPurchaseOrder, error) {
+	filter := rInventory.PurchaseOrderFilter{
+		WarehouseAssetID: req.WarehouseAssetID,
+		Supplier:         req.Supplier,
+		AutoGenerated:    req.AutoGenerated,
+	}
+	for _, status := range utils.SplitValues(req.Status) {
+		filter.Status = append(filter.Status, rModels.PurchaseOrderStatus(status))
+	}
+
+	return s.repository.GetPurchaseOrders(filter)
+}
+
+func (s *service) GetPurchaseOrderByID(id uint) (*rModels.MrPurchaseOrder, error) {
+	order, err := s.repository.GetPurchaseOrderByID(id)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return order, nil
+}
+
+// CreatePurchaseOrder crea una orden manual en borrador
+func (s *service) CreatePurchaseOrder(req CreatePurchaseOrderRequest, createdBy uint) (*rModels.MrPurchaseOrder, error) {
+	now := time.Now()
+	order := &rModels.MrPurchaseOrder{
+		WarehouseAssetID: req.WarehouseAssetID,
+		OrderNumber:      req.OrderNumber,
+		Supplier:         req.Supplier,
+		Status:           rModels.PurchaseOrderDraft,
+		OrderDate:        now,
+		ExpectedDate:     req.ExpectedDate,
+		CreatedBy:        createdBy,
+	}
+	if order.OrderNumber == "" {
+		order.OrderNumber = fmt.Sprintf("PO-%d-%s", req.WarehouseAssetID, now.Format("20060102150405.000"))
+	}
+
+	for _, item := range req.Items {
+		totalPrice := rInventory.RoundAmount(item.Quantity * item.UnitPrice)
+		order.OrderItems = append(order.OrderItems, rModels.MrPurchaseOrderItem{
+			ProductID:  item.ProductID,
+			Quantity:   item.Quantity,
+			UnitPrice:  item.UnitPrice,
+			TotalPrice: totalPrice,
+		})
+		order.TotalAmount += totalPrice
+	}
+	order.TotalAmount = rInventory.RoundAmount(order.TotalAmount)
+
+	if err := s.repository.CreatePurchaseOrder(order); err != nil {
+		if rMaintenance.IsDuplicateKey(err) {
+			return nil, fmt.Errorf("%w: order number %s already exists", ErrInvalidRequest, order.OrderNumber)
+		}
+		return nil, err
+	}
+
+	return s.repository.GetPurchaseOrderByID(order.ID)
+}
+
+// ApprovePurchaseOrder aprueba un borrador, que queda listo para enviarse al proveedor
+func (s *service) ApprovePurchaseOrder(id uint, approvedBy uint) (*rModels.MrPurchaseOrder, error) {
+	order, err := s.GetPurchaseOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != rModels.PurchaseOrderDraft {
+		return nil, fmt.Errorf("%w: purchase order %s is %s, only drafts can be approved", ErrInvalidStatus, order.OrderNumber, order.Status)
+	}
+	if len(order.OrderItems) == 0 {
+		return nil, fmt.Errorf("%w: purchase order %s has no items", ErrInvalidRequest, order.OrderNumber)
+	}
+
+	return s.transitionPurchaseOrder(order, []rModels.PurchaseOrderStatus{rModels.PurchaseOrderDraft}, rModels.PurchaseOrderApproved, map[string]interface{}{
+		"approved_by": approvedBy,
+		"approved_at": time.Now(),
+	})
+}
+
+// SendPurchaseOrder marca como enviada al proveedor una orden aprobada
+func (s *service) SendPurchaseOrder(id uint, req SendPurchaseOrderRequest) (*rModels.MrPurchaseOrder, error) {
+	order, err := s.GetPurchaseOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != rModels.PurchaseOrderApproved {
+		return nil, fmt.Errorf("%w: purchase order %s is %s, only approved orders can be sent", ErrInvalidStatus, order.OrderNumber, order.Status)
+	}
+
+	now := time.Now()
+	fields := map[string]interface{}{
+		"sent_at":    now,
+		"order_date": now,
+	}
+	if !req.ExpectedDate.IsZero() {
+		fields["expected_date"] = req.ExpectedDate
+	}
+
+	return s.transitionPurchaseOrder(order, []rModels.PurchaseOrderStatus{rModels.PurchaseOrderApproved}, rModels.PurchaseOrderOrdered, fields)
+}
+
+// ReceivePurchaseOrder registra la entrada de mercancía, total o parcial, de una orden enviada
+func (s *service) ReceivePurchaseOrder(id uint, req ReceivePurchaseOrderRequest, createdBy uint) (*rModels.MrPurchaseOrder, error) {
+	if _, err := s.GetPurchaseOrderByID(id); err != nil {
+		return nil, err
+	}
+
+	lines := make([]rInventory.PurchaseReceiptLine, 0, len(req.Items))
+	for _, item := range req.Items {
+		lines = append(lines, rInventory.PurchaseReceiptLine{
+			ItemID:      item.ItemID,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			BatchNumber: item.BatchNumber,
+			ExpiryDate:  item.ExpiryDate,
+			Location:    item.Location,
+		})
+	}
+
+	in := rInventory.MovementInput{
+		ReferenceNumber: req.ReferenceNumber,
+		Reason:          req.Reason,
+		CreatedBy:       createdBy,
+	}
+	if _, err := s.repository.ReceivePurchaseOrder(id, lines, in); err != nil {
+		return nil, domainError(err)
+	}
+
+	return s.repository.GetPurchaseOrderByID(id)
+}
+
+// CancelPurchaseOrder anula una orden pendiente; lo ya recibido se mantiene en stock
+func (s *service) CancelPurchaseOrder(id uint, req CancelPurchaseOrderRequest) (*rModels.MrPurchaseOrder, error) {
+	order, err := s.GetPurchaseOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == rModels.PurchaseOrderReceived || order.Status == rModels.PurchaseOrderCancelled {
+		return nil, fmt.Errorf("%w: purchase order %s is already %s", ErrInvalidStatus, order.OrderNumber, order.Status)
+	}
+
+	cancelled, err := s.repository.CancelPurchaseOrder(id, cancellablePurchaseOrderStatuses, req.Reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("%w: purchase order %s was modified concurrently", ErrInvalidStatus, order.OrderNumber)
+	}
+
+	return s.repository.GetPurchaseOrderByID(id)
+}
+
+func (s *service) transitionPurchaseOrder(order *rModels.MrPurchaseOrder, from []rModels.PurchaseOrderStatus, to rModels.PurchaseOrderStatus, fields map[string]interface{}) (*rModels.MrPurchaseOrder, error) {
+	changed, err := s.repository.TransitionPurchaseOrder(order.ID, from, to, fields)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, fmt.Errorf("%w: purchase order %s was modified concurrently", ErrInvalidStatus, order.OrderNumber)
+	}
+
+	return s.repository.GetPurchaseOrderByID(order.ID)
+}
@@ -0,0 +1,209 @@
+package sInventory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rInventory"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReorderInterval = time.Hour
+	defaultReorderLeadTime = 7 * 24 * time.Hour
+)
+
+// ReorderItem línea propuesta para reponer un producto en un almacén
+type ReorderItem struct {
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	OnHand      float64 `json:"on_hand"`
+	OnOrder     float64 `json:"on_order"`
+	Reserved    float64 `json:"reserved"`
+	Projected   float64 `json:"projected"` // Disponible + pedido - reservado
+	MinStock    float64 `json:"min_stock"`
+	MaxStock    float64 `json:"max_stock"`
+	Quantity    float64 `json:"quantity"` // Cantidad a pedir para volver a MaxStock
+	UnitPrice   float64 `json:"unit_price"`
+	TotalPrice  float64 `json:"total_price"`
+}
+
+// ReorderProposal agrupa por almacén y proveedor las líneas que se convertirán en una orden de compra
+type ReorderProposal struct {
+	WarehouseAssetID uint          `json:"warehouse_asset_id"`
+	Supplier         string        `json:"supplier"`
+	Items            []ReorderItem `json:"items"`
+	TotalAmount      float64       `json:"total_amount"`
+}
+
+// GetReorderProposals calcula, sin persistir, qué productos están por debajo de MinStock
+func (s *service) GetReorderProposals() ([]ReorderProposal, error) {
+	lines, err := s.repository.GetReorderLines()
+	if err != nil {
+		return nil, err
+	}
+
+	type proposalKey struct {
+		warehouseAssetID uint
+		supplier         string
+	}
+	proposals := make(map[proposalKey]*ReorderProposal)
+	var keys []proposalKey
+
+	for _, line := range lines {
+		projected := line.OnHand + line.OnOrder - line.Reserved
+		if projected >= line.MinStock {
+			continue
+		}
+
+		// Sin MaxStock se repone hasta el mínimo
+		target := math.Max(line.MaxStock, line.MinStock)
+		quantity := target - projected
+		if quantity <= 0 {
+			continue
+		}
+
+		unitPrice := line.LastUnitPrice
+		if unitPrice <= 0 {
+			unitPrice = line.UnitCost
+		}
+
+		key := proposalKey{warehouseAssetID: line.WarehouseAssetID, supplier: line.Supplier}
+		proposal, ok := proposals[key]
+		if !ok {
+			proposal = &ReorderProposal{WarehouseAssetID: line.WarehouseAssetID, Supplier: line.Supplier}
+			proposals[key] = proposal
+			keys = append(keys, key)
+		}
+
+		item := ReorderItem{
+			ProductID:   line.ProductID,
+			ProductName: line.ProductName,
+			OnHand:      line.OnHand,
+			OnOrder:     line.OnOrder,
+			Reserved:    line.Reserved,
+			Projected:   projected,
+			MinStock:    line.MinStock,
+			MaxStock:    line.MaxStock,
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			TotalPrice:  rInventory.RoundAmount(quantity * unitPrice),
+		}
+		proposal.Items = append(proposal.Items, item)
+		proposal.TotalAmount += item.TotalPrice
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].warehouseAssetID != keys[j].warehouseAssetID {
+			return keys[i].warehouseAssetID < keys[j].warehouseAssetID
+		}
+		return keys[i].supplier < keys[j].supplier
+	})
+
+	result := make([]ReorderProposal, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *proposals[key])
+	}
+	return result, nil
+}
+
+// GenerateReorderPurchaseOrders convierte las propuestas en órdenes de compra en borrador
+// pendientes de aprobación por un planificador
+func (s *service) GenerateReorderPurchaseOrders(createdBy uint) ([]rModels.MrPurchaseOrder, error) {
+	proposals, err := s.GetReorderProposals()
+	if err != nil {
+		return nil, err
+	}
+	if len(proposals) == 0 {
+		return []rModels.MrPurchaseOrder{}, nil
+	}
+
+	leadTime := s.config.Inventory.ReorderLeadTime
+	if leadTime <= 0 {
+		leadTime = defaultReorderLeadTime
+	}
+
+	now := time.Now()
+	orders := make([]*rModels.MrPurchaseOrder, 0, len(proposals))
+	for i, proposal := range proposals {
+		order := &rModels.MrPurchaseOrder{
+			WarehouseAssetID: proposal.WarehouseAssetID,
+			OrderNumber:      fmt.Sprintf("RP-%d-%s-%02d", proposal.WarehouseAssetID, now.Format("20060102150405"), i+1),
+			Supplier:         proposal.Supplier,
+			Status:           rModels.PurchaseOrderDraft,
+			OrderDate:        now,
+			ExpectedDate:     now.Add(leadTime),
+			AutoGenerated:    true,
+			CreatedBy:        createdBy,
+		}
+		for _, item := range proposal.Items {
+			order.OrderItems = append(order.OrderItems, rModels.MrPurchaseOrderItem{
+				ProductID:  item.ProductID,
+				Quantity:   item.Quantity,
+				UnitPrice:  item.UnitPrice,
+				TotalPrice: item.TotalPrice,
+			})
+		}
+		orders = append(orders, order)
+	}
+
+	created, err := s.repository.CreateReorderPurchaseOrders(orders)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		created = []rModels.MrPurchaseOrder{}
+	}
+	return created, nil
+}
+
+// StartReorderJob genera periódicamente las propuestas de reaprovisionamiento hasta que se cancela el contexto
+func (s *service) StartReorderJob(ctx context.Context) {
+	cfg := s.config.Inventory
+	if !cfg.ReorderEnabled {
+		s.logger.Info("Reaprovisionamiento automático de repuestos deshabilitado")
+		return
+	}
+
+	interval := cfg.ReorderInterval
+	if interval <= 0 {
+		interval = defaultReorderInterval
+	}
+
+	s.logger.Info("Iniciando reaprovisionamiento automático de repuestos", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.runReorderJob()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Deteniendo reaprovisionamiento automático de repuestos")
+			return
+		case <-ticker.C:
+			s.runReorderJob()
+		}
+	}
+}
+
+func (s *service) runReorderJob() {
+	orders, err := s.GenerateReorderPurchaseOrders(s.config.Maintenance.SystemEmployeeID)
+	if err != nil {
+		s.logger.Error("Error generating reorder purchase orders", zap.Error(err))
+		return
+	}
+
+	for _, order := range orders {
+		s.logger.Info("Propuesta de compra generada",
+			zap.Uint("purchase_order_id", order.ID),
+			zap.String("order_number", order.OrderNumber),
+			zap.String("supplier", order.Supplier),
+			zap.Int("items", len(order.OrderItems)),
+		)
+	}
+}
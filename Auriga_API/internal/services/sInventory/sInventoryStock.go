@@ -0,0 +1,134 @@
+package sInventory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rInventory"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"gorm.io/gorm"
+)
+
+const defaultExpiryHorizon = 30 * 24 * time.Hour
+
+// ExpiringStock lote próximo a caducar o ya caducado
+type ExpiringStock struct {
+	StockID      uint      `json:"stock_id"`
+	ProductID    uint      `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	BatchNumber  string    `json:"batch_number"`
+	Location     string    `json:"location"`
+	ExpiryDate   time.Time `json:"expiry_date"`
+	DaysToExpiry int       `json:"days_to_expiry"` // Negativo si ya ha caducado
+	Expired      bool      `json:"expired"`
+	Quantity     float64   `json:"quantity"`
+	TotalValue   float64   `json:"total_value"`
+}
+
+// ExpiringStockReport lotes por caducar de un almacén
+type ExpiringStockReport struct {
+	WarehouseAssetID uint            `json:"warehouse_asset_id"`
+	WarehouseCode    string          `json:"warehouse_code"`
+	Until            time.Time       `json:"until"`
+	Items            []ExpiringStock `json:"items"`
+	TotalValue       float64         `json:"total_value"`
+	ExpiredValue     float64         `json:"expired_value"`
+}
+
+func (s *service) GetStocks(req StockFilterRequest) ([]rModels.MrSparePartStock, error) {
+	return s.repository.GetStocks(rInventory.StockFilter{
+		WarehouseAssetID: req.WarehouseAssetID,
+		ProductID:        req.ProductID,
+	})
+}
+
+func (s *service) GetStockByID(id uint) (*rModels.MrSparePartStock, error) {
+	stock, err := s.repository.GetStockByID(id)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return stock, nil
+}
+
+func (s *service) GetMovements(req MovementFilterRequest) ([]rModels.MrAssetRegisterMovement, error) {
+	if !req.From.IsZero() && !req.To.IsZero() && req.To.Before(req.From) {
+		return nil, fmt.Errorf("%w: 'to' date is before 'from' date", ErrInvalidRequest)
+	}
+
+	return s.repository.GetMovements(rInventory.MovementFilter{
+		ProductID:        req.ProductID,
+		WarehouseAssetID: req.WarehouseAssetID,
+		WorkOrderID:      req.WorkOrderID,
+		StockID:          req.StockID,
+		From:             req.From,
+		To:               req.To,
+	})
+}
+
+// GetExpiringStocks agrupa por almacén los lotes caducados o que caducan dentro del horizonte
+func (s *service) GetExpiringStocks(req ExpiringStockRequest) ([]ExpiringStockReport, error) {
+	horizon := s.config.Inventory.ExpiryHorizon
+	if horizon <= 0 {
+		horizon = defaultExpiryHorizon
+	}
+	if req.Days != nil {
+		horizon = time.Duration(*req.Days) * 24 * time.Hour
+	}
+
+	now := time.Now()
+	stocks, err := s.repository.GetExpiringStocks(req.WarehouseAssetID, now.Add(horizon))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []ExpiringStockReport{}
+	for _, stock := range stocks {
+		if len(reports) == 0 || reports[len(reports)-1].WarehouseAssetID != stock.WarehouseAssetID {
+			reports = append(reports, ExpiringStockReport{
+				WarehouseAssetID: stock.WarehouseAssetID,
+				WarehouseCode:    stock.WarehouseAsset.Code,
+				Until:            now.Add(horizon),
+			})
+		}
+		report := &reports[len(reports)-1]
+
+		expired := !stock.ExpiryDate.After(now)
+		item := ExpiringStock{
+			StockID:      stock.ID,
+			ProductID:    stock.ProductID,
+			ProductName:  stock.Product.Name,
+			BatchNumber:  stock.BatchNumber,
+			Location:     stock.Location,
+			ExpiryDate:   *stock.ExpiryDate,
+			DaysToExpiry: int(math.Floor(stock.ExpiryDate.Sub(now).Hours() / 24)),
+			Expired:      expired,
+			Quantity:     stock.Quantity,
+			TotalValue:   stock.TotalValue,
+		}
+		report.Items = append(report.Items, item)
+		report.TotalValue += stock.TotalValue
+		if expired {
+			report.ExpiredValue += stock.TotalValue
+		}
+	}
+	return reports, nil
+}
+
+// domainError traduce los errores de repositorio a errores de dominio
+func domainError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, rInventory.ErrInsufficientStock):
+		return fmt.Errorf("%w: %v", ErrInsufficientStock, err)
+	case errors.Is(err, rInventory.ErrExpiredBatch):
+		return fmt.Errorf("%w: %v", ErrExpiredBatch, err)
+	case errors.Is(err, rInventory.ErrInvalidOrderStatus):
+		return fmt.Errorf("%w: %v", ErrInvalidStatus, err)
+	case errors.Is(err, rInventory.ErrInvalidMovement):
+		return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	return err
+}
@@ -1,8 +1,10 @@
 package sLabor
 
 import (
+	"context"
 	"time"
 
+	"github.com/remrafvil/Auriga_API/internal/repositories/rAuth"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rLabor"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
 	"go.uber.org/zap"
@@ -32,18 +34,22 @@ type Service interface {
 	UpdateAssignment(id uint, req CreateShiftAssignmentRequest) (*rModels.MrShiftAssignment, error)
 	DeleteAssignment(id uint) error
 	GetCurrentEmployeeAssignment(employeeID uint, date time.Time) (*rModels.MrShiftAssignment, error)
+
+	ResolveOperator(ctx context.Context, at time.Time) (*Operator, error)
 }
 
 // service implementación
 type service struct {
-	repository rLabor.Repository
-	logger     *zap.Logger
+	repository     rLabor.Repository
+	repositoryAuth rAuth.Repository
+	logger         *zap.Logger
 }
 
-func New(repository rLabor.Repository, logger *zap.Logger) Service {
+func New(repository rLabor.Repository, repositoryAuth rAuth.Repository, logger *zap.Logger) Service {
 	return &service{
-		repository: repository,
-		logger:     logger,
+		repository:     repository,
+		repositoryAuth: repositoryAuth,
+		logger:         logger,
 	}
 }
 
@@ -0,0 +1,77 @@
+package sLabor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Errores de la identificación del operario que firma los envíos a SAP
+var (
+	ErrNoOperator  = errors.New("no authenticated user")
+	ErrNoWorkdayID = errors.New("authenticated user has no WorkdayID")
+)
+
+// Operator operario tal como se informa a SAP en IV_OPERARIO e IV_TURNO
+type Operator struct {
+	EmployeeID uint   // 0 si el usuario no está sincronizado en MrEmployee
+	WorkdayID  string // IV_OPERARIO
+	Name       string
+	Shift      string // IV_TURNO: nombre del turno asignado en la fecha; vacío si no tiene
+}
+
+// ResolveOperator identifica al usuario autenticado en el contexto. El WorkdayID sale de su registro
+// MrEmployee y, si no lo tiene, del claim organization.workday_id de Authentik; el turno, de su
+// asignación vigente en la fecha indicada.
+func (s *service) ResolveOperator(ctx context.Context, at time.Time) (*Operator, error) {
+	authentikID, _ := utils.GetUserID(ctx)
+	if authentikID == "" {
+		return nil, ErrNoOperator
+	}
+
+	operator := &Operator{}
+	operator.Name, _ = utils.GetUserName(ctx)
+
+	employee, err := s.repositoryAuth.FindEmployeeByAuthentikID(authentikID, ctx)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if employee != nil {
+		operator.EmployeeID = employee.ID
+		operator.WorkdayID = strings.TrimSpace(employee.WorkdayID)
+		if name := strings.TrimSpace(employee.FirstName + " " + employee.LastName); name != "" {
+			operator.Name = name
+		}
+	}
+	if operator.WorkdayID == "" {
+		if organization, ok := utils.GetUserOrganization(ctx); ok {
+			workdayID, _ := organization["workday_id"].(string)
+			operator.WorkdayID = strings.TrimSpace(workdayID)
+		}
+	}
+	if operator.WorkdayID == "" {
+		return nil, fmt.Errorf("%w: user %s", ErrNoWorkdayID, authentikID)
+	}
+
+	if operator.EmployeeID > 0 {
+		if at.IsZero() {
+			at = time.Now()
+		}
+		assignment, err := s.repository.GetCurrentAssignment(operator.EmployeeID, at)
+		switch {
+		case err == nil:
+			operator.Shift = assignment.Shift.Name
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			s.logger.Debug("Operator has no shift assignment", zap.Uint("employee_id", operator.EmployeeID), zap.Time("at", at))
+		default:
+			return nil, err
+		}
+	}
+	return operator, nil
+}
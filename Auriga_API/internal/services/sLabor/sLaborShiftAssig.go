@@ -20,7 +20,7 @@ func (s *service) CreateIndividualAssignment(req CreateShiftAssignmentRequest) (
 		EmployeeID: req.EmployeeID,
 		ShiftID:    req.ShiftID,
 		StartDate:  req.StartDate,
-		EndDate:    req.EndDate,
+		EndDate:    optionalDate(req.EndDate),
 		Active:     true,
 	}
 
@@ -44,7 +44,7 @@ func (s *service) CreateTeamAssignment(req CreateShiftAssignmentRequest) ([]rMod
 			EmployeeID: member.EmployeeID,
 			ShiftID:    req.ShiftID,
 			StartDate:  req.StartDate,
-			EndDate:    req.EndDate,
+			EndDate:    optionalDate(req.EndDate),
 			Active:     true,
 		}
 
@@ -73,7 +73,7 @@ func (s *service) CreateBulkAssignments(req BulkShiftAssignmentRequest) ([]rMode
 				EmployeeID: member.EmployeeID,
 				ShiftID:    req.ShiftID,
 				StartDate:  req.StartDate,
-				EndDate:    req.EndDate,
+				EndDate:    optionalDate(req.EndDate),
 				Active:     true,
 			}
 
@@ -91,7 +91,7 @@ func (s *service) CreateBulkAssignments(req BulkShiftAssignmentRequest) ([]rMode
 				EmployeeID: employeeID,
 				ShiftID:    req.ShiftID,
 				StartDate:  req.StartDate,
-				EndDate:    req.EndDate,
+				EndDate:    optionalDate(req.EndDate),
 				Active:     true,
 			}
 
@@ -134,7 +134,7 @@ func (s *service) UpdateAssignment(id uint, req CreateShiftAssignmentRequest) (*
 	if !req.StartDate.IsZero() {
 		targetAssignment.StartDate = req.StartDate
 	}
-	targetAssignment.EndDate = req.EndDate
+	targetAssignment.EndDate = optionalDate(req.EndDate)
 
 	if err := s.repository.UpdateAssignment(targetAssignment); err != nil {
 		return nil, err
@@ -143,6 +143,14 @@ func (s *service) UpdateAssignment(id uint, req CreateShiftAssignmentRequest) (*
 	return targetAssignment, nil
 }
 
+// optionalDate convierte la fecha de fin opcional de la petición: sin fecha la asignación no caduca
+func optionalDate(date time.Time) *time.Time {
+	if date.IsZero() {
+		return nil
+	}
+	return &date
+}
+
 func (s *service) DeleteAssignment(id uint) error {
 	return s.repository.DeleteAssignment(id)
 }
@@ -0,0 +1,309 @@
+package sLive
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/config"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rLive"
+	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
+	"go.uber.org/zap"
+)
+
+// Tipos de mensaje que se difunden a los clientes
+const (
+	TypeRawEvent    = "raw_event"
+	TypeEventCommit = "event_commit"
+	TypeLineStatus  = "line_status"
+	TypeThroughput  = "throughput"
+)
+
+// Acciones sobre los eventos brutos y confirmados
+const (
+	ActionCreated  = "created"
+	ActionUpdated  = "updated"
+	ActionClosed   = "closed"
+	ActionDeleted  = "deleted"
+	ActionRestored = "restored"
+)
+
+const (
+	defaultPollInterval = 10 * time.Second
+	defaultBufferSize   = 256
+)
+
+// Message cambio difundido a los suscriptores de la fábrica y línea
+type Message struct {
+	ID       uint64      `json:"ID"`
+	Type     string      `json:"Type"`
+	Action   string      `json:"Action,omitempty"`
+	Factory  string      `json:"Factory"`
+	ProdLine string      `json:"ProdLine"`
+	Time     time.Time   `json:"Time"`
+	Data     interface{} `json:"Data"`
+}
+
+// Subscription recibe los mensajes de una fábrica, filtrados por línea y tipo si se indican
+type Subscription struct {
+	Messages <-chan Message
+
+	ch      chan Message
+	factory string
+	lines   map[string]bool
+	types   map[string]bool
+}
+
+type Service interface {
+	Subscribe(factory string, lines []string, types []string) *Subscription
+	Unsubscribe(sub *Subscription)
+	Publish(msg Message)
+	StartLinePoller(ctx context.Context)
+	StartListener(ctx context.Context)
+}
+
+type service struct {
+	repositoryInflux riInfluxdb.Repository
+	repositoryLive   rLive.Repository
+	config           *config.Settings
+	logger           *zap.Logger
+
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	lastID atomic.Uint64
+	// Con el canal de PostgreSQL escuchando, los mensajes llegan a los suscriptores de todas las
+	// instancias a través de él; si no, solo a los de esta
+	listening atomic.Bool
+
+	// Último estado publicado de cada línea vigilada, para detectar cambios y enviarlo al suscribirse
+	stateMu sync.Mutex
+	state   map[lineKey]Message
+}
+
+type lineKey struct {
+	factory  string
+	prodLine string
+}
+
+func New(repositoryInflux riInfluxdb.Repository, repositoryLive rLive.Repository, config *config.Settings, logger *zap.Logger) Service {
+	return &service{
+		repositoryInflux: repositoryInflux,
+		repositoryLive:   repositoryLive,
+		config:           config,
+		logger:           logger,
+		subs:             make(map[*Subscription]struct{}),
+		state:            make(map[lineKey]Message),
+	}
+}
+
+func (s *service) Subscribe(factory string, lines []string, types []string) *Subscription {
+	size := s.config.Live.BufferSize
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	ch := make(chan Message, size)
+	sub := &Subscription{Messages: ch, ch: ch, factory: factory, lines: toSet(lines), types: toSet(types)}
+
+	s.mu.Lock()
+	s.subs[sub] = struct{}{}
+	// El estado conocido de las líneas se envía al suscribirse para no esperar al siguiente cambio
+	s.stateMu.Lock()
+	for _, msg := range s.state {
+		if !sub.matches(msg) {
+			continue
+		}
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+	s.stateMu.Unlock()
+	s.mu.Unlock()
+
+	s.logger.Info("Live subscriber connected", zap.String("factory", factory), zap.Strings("lines", lines), zap.Strings("types", types))
+	return sub
+}
+
+func (s *service) Unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[sub]; !ok {
+		return
+	}
+	delete(s.subs, sub)
+	close(sub.ch)
+	s.logger.Info("Live subscriber disconnected", zap.String("factory", sub.factory))
+}
+
+// Publish difunde el mensaje a los suscriptores de todas las instancias de la API a través de
+// PostgreSQL. Si el canal no está disponible o el mensaje no cabe en una notificación, se entrega solo
+// a los suscriptores de esta instancia.
+func (s *service) Publish(msg Message) {
+	if msg.Time.IsZero() {
+		msg.Time = time.Now()
+	}
+
+	if s.listening.Load() {
+		payload, err := json.Marshal(msg)
+		if err == nil {
+			err = s.repositoryLive.Notify(payload)
+		}
+		if err == nil {
+			return
+		}
+		s.logger.Warn("Cannot broadcast live message, delivering locally", zap.String("type", msg.Type), zap.Error(err))
+	}
+	s.deliver(msg)
+}
+
+// StartListener recibe los mensajes publicados por cualquier instancia y los entrega a los suscriptores
+// de esta, hasta que se cancela el contexto
+func (s *service) StartListener(ctx context.Context) {
+	s.logger.Info("Iniciando escucha de mensajes en vivo")
+
+	err := s.repositoryLive.Listen(ctx, func(payload []byte) {
+		var msg struct {
+			Message
+			Data json.RawMessage `json:"Data"`
+		}
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			s.logger.Warn("Invalid live message received", zap.Error(err))
+			return
+		}
+		msg.Message.Data = msg.Data
+		s.deliver(msg.Message)
+	}, s.listening.Store)
+	if err != nil {
+		s.logger.Error("Error listening for live messages, delivering locally only", zap.Error(err))
+		return
+	}
+	s.logger.Info("Deteniendo escucha de mensajes en vivo")
+}
+
+// deliver entrega el mensaje sin bloquear; si la cola de un suscriptor está llena el mensaje se descarta
+// para ese suscriptor, que puede recuperar el estado con las consultas habituales
+func (s *service) deliver(msg Message) {
+	msg.ID = s.lastID.Add(1)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for sub := range s.subs {
+		if !sub.matches(msg) {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			s.logger.Debug("Live subscriber queue full, message dropped", zap.String("factory", sub.factory), zap.String("type", msg.Type))
+		}
+	}
+}
+
+func (sub *Subscription) matches(msg Message) bool {
+	if msg.Factory != sub.factory {
+		return false
+	}
+	if len(sub.lines) > 0 && !sub.lines[msg.ProdLine] {
+		return false
+	}
+	return len(sub.types) == 0 || sub.types[msg.Type]
+}
+
+// StartLinePoller consulta periódicamente el estado y el throughput de las líneas que algún cliente
+// sigue expresamente, hasta que se cancela el contexto. Cada instancia consulta las líneas de sus propios
+// suscriptores, así que estos mensajes no se difunden a las demás.
+func (s *service) StartLinePoller(ctx context.Context) {
+	interval := s.config.Live.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	s.logger.Info("Iniciando difusión del estado de líneas", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Deteniendo difusión del estado de líneas")
+			return
+		case <-ticker.C:
+			s.runLinePoller(time.Now())
+		}
+	}
+}
+
+func (s *service) runLinePoller(now time.Time) {
+	watched := s.watchedLines()
+
+	s.stateMu.Lock()
+	for key := range s.state {
+		if !watched[key] {
+			delete(s.state, key)
+		}
+	}
+	s.stateMu.Unlock()
+
+	for key := range watched {
+		status, throughput, err := s.repositoryInflux.GetLineStatus(key.factory, key.prodLine)
+		if err != nil {
+			s.logger.Debug("Cannot get line status for live subscribers", zap.String("factory", key.factory), zap.String("prod_line", key.prodLine), zap.Error(err))
+			status = "unknown"
+		}
+
+		msg := Message{
+			Type:     TypeLineStatus,
+			Factory:  key.factory,
+			ProdLine: key.prodLine,
+			Time:     now,
+			Data:     riInfluxdb.LineStatusResponse{LineCode: key.prodLine, Status: status, LastSeen: now, Throughput: throughput},
+		}
+		s.stateMu.Lock()
+		previous, known := s.state[key]
+		changed := !known || previous.Data.(riInfluxdb.LineStatusResponse).Status != status
+		if changed {
+			s.state[key] = msg
+		}
+		s.stateMu.Unlock()
+		if changed {
+			s.deliver(msg)
+		}
+
+		if err == nil {
+			s.deliver(Message{
+				Type:     TypeThroughput,
+				Factory:  key.factory,
+				ProdLine: key.prodLine,
+				Time:     now,
+				Data:     riInfluxdb.ThroughputData{Time: now, Value: throughput, LineCode: key.prodLine},
+			})
+		}
+	}
+}
+
+// watchedLines líneas indicadas por los suscriptores que reciben estado o throughput
+func (s *service) watchedLines() map[lineKey]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	watched := make(map[lineKey]bool)
+	for sub := range s.subs {
+		if len(sub.types) > 0 && !sub.types[TypeLineStatus] && !sub.types[TypeThroughput] {
+			continue
+		}
+		for line := range sub.lines {
+			watched[lineKey{factory: sub.factory, prodLine: line}] = true
+		}
+	}
+	return watched
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,208 @@
+package sMaintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultConditionInterval = time.Minute
+	// conditionLookback amplía la consulta para disponer de la lectura anterior al inicio de la ventana
+	conditionLookback = 15 * time.Minute
+)
+
+// StartConditionMonitor evalúa periódicamente los planes por condición hasta que se cancela el contexto
+func (s *service) StartConditionMonitor(ctx context.Context) {
+	cfg := s.config.Maintenance
+	if !cfg.ConditionEnabled {
+		s.logger.Info("Monitor de mantenimiento por condición deshabilitado")
+		return
+	}
+
+	interval := cfg.ConditionInterval
+	if interval <= 0 {
+		interval = defaultConditionInterval
+	}
+
+	s.logger.Info("Iniciando monitor de mantenimiento por condición", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Deteniendo monitor de mantenimiento por condición")
+			return
+		case <-ticker.C:
+			s.runConditionMonitor(time.Now())
+		}
+	}
+}
+
+func (s *service) runConditionMonitor(now time.Time) {
+	plans, err := s.repository.GetConditionPlans()
+	if err != nil {
+		s.logger.Error("Error getting condition based maintenance plans", zap.Error(err))
+		return
+	}
+
+	for _, plan := range plans {
+		if err := s.evaluateConditionPlan(plan, now); err != nil {
+			s.logger.Warn("Cannot evaluate maintenance plan condition",
+				zap.Uint("plan_id", plan.ID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// evaluateConditionPlan dispara una OT predictiva si la condición se mantiene el tiempo mínimo.
+// Tras el disparo el plan queda enclavado hasta que la señal vuelve más allá de la histéresis.
+func (s *service) evaluateConditionPlan(plan rModels.MrMaintenancePlan, now time.Time) error {
+	minDuration := time.Duration(plan.ConditionMinDuration) * time.Second
+	values, err := s.readConditionValues(plan, now.Add(-minDuration-conditionLookback), now)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return errors.New("no measurements in the evaluation window")
+	}
+
+	comparator := plan.ConditionComparator
+	if comparator == "" {
+		comparator = rModels.ConditionGreater
+	}
+	threshold := *plan.ThresholdValue
+	last := values[len(values)-1]
+
+	if plan.ConditionLatched {
+		if conditionCleared(comparator, last.Value, threshold, plan.ConditionHysteresis) {
+			if _, err := s.repository.SetPlanConditionLatched(plan.ID, false); err != nil {
+				return err
+			}
+			s.logger.Info("Condición de mantenimiento normalizada",
+				zap.Uint("plan_id", plan.ID),
+				zap.Float64("value", last.Value),
+			)
+		}
+		return nil
+	}
+
+	trigger, ok := conditionHeld(values, comparator, threshold, now.Add(-minDuration))
+	if !ok {
+		return nil
+	}
+
+	open, err := s.repository.HasOpenPlanWorkOrder(plan.ID)
+	if err != nil {
+		return err
+	}
+	if open {
+		return nil
+	}
+
+	// Solo la réplica que consigue enclavar el plan crea la OT
+	latched, err := s.repository.SetPlanConditionLatched(plan.ID, true)
+	if err != nil || !latched {
+		return err
+	}
+
+	workOrder := buildPlanWorkOrder(plan, rModels.WorkOrderTypePredictive, trigger.Time, s.config.Maintenance.SystemEmployeeID)
+	workOrder.ScheduledDate = now
+	workOrder.TriggerParameter = plan.ConditionParameter
+	workOrder.TriggerValue = &trigger.Value
+	workOrder.TriggeredAt = &trigger.Time
+
+	history := &rModels.MrMaintWorkOrderStatusHistory{
+		ToStatus:  workOrder.Status,
+		ChangedBy: workOrder.CreatedBy,
+		ChangedAt: now,
+		Reason: fmt.Sprintf("Generated by condition monitor: %s = %.4f (%s %.4f)",
+			*plan.ConditionParameter, trigger.Value, comparator, threshold),
+	}
+
+	created, err := s.repository.CreatePlanWorkOrder(workOrder, history)
+	if err != nil {
+		// Se desenclava para reintentar en la siguiente evaluación
+		if _, unlatchErr := s.repository.SetPlanConditionLatched(plan.ID, false); unlatchErr != nil {
+			s.logger.Error("Error unlatching maintenance plan", zap.Uint("plan_id", plan.ID), zap.Error(unlatchErr))
+		}
+		return fmt.Errorf("error creating predictive work order: %w", err)
+	}
+	if created {
+		s.logger.Info("Orden de trabajo predictiva generada",
+			zap.Uint("plan_id", plan.ID),
+			zap.Uint("work_order_id", workOrder.ID),
+			zap.Float64("value", trigger.Value),
+		)
+	}
+
+	return nil
+}
+
+// readConditionValues lee la señal del plan en la línea del activo sensor (o del activo del plan)
+func (s *service) readConditionValues(plan rModels.MrMaintenancePlan, start time.Time, stop time.Time) ([]riInfluxdb.FieldValue, error) {
+	asset := plan.Asset
+	if plan.SensorAsset != nil {
+		asset = *plan.SensorAsset
+	}
+
+	// hierarchical_level del activo: [fábrica, línea, ...]
+	levels := asset.HierarchicalLevel
+	if len(levels) < 2 {
+		return nil, fmt.Errorf("asset %d is not located in a production line", asset.ID)
+	}
+
+	var path []string
+	if plan.ConditionPath != nil && *plan.ConditionPath != "" {
+		path = strings.Split(*plan.ConditionPath, "/")
+	}
+
+	return s.repositoryInflux.GetFieldValues(levels[0], levels[1], path, *plan.ConditionParameter, start, stop)
+}
+
+// conditionHeld comprueba que la condición se cumple desde windowStart hasta la última lectura.
+// Exige una lectura anterior o igual a windowStart para asegurar que la ventana está cubierta.
+func conditionHeld(values []riInfluxdb.FieldValue, comparator rModels.ConditionComparator, threshold float64, windowStart time.Time) (riInfluxdb.FieldValue, bool) {
+	last := values[len(values)-1]
+	for i := len(values) - 1; i >= 0; i-- {
+		if !conditionMet(comparator, values[i].Value, threshold) {
+			return last, false
+		}
+		if !values[i].Time.After(windowStart) {
+			return last, true
+		}
+	}
+	return last, false
+}
+
+func conditionMet(comparator rModels.ConditionComparator, value float64, threshold float64) bool {
+	switch comparator {
+	case rModels.ConditionGreaterEqual:
+		return value >= threshold
+	case rModels.ConditionLess:
+		return value < threshold
+	case rModels.ConditionLessEqual:
+		return value <= threshold
+	default:
+		return value > threshold
+	}
+}
+
+// conditionCleared indica si la señal ha vuelto a la zona normal más allá de la histéresis
+func conditionCleared(comparator rModels.ConditionComparator, value float64, threshold float64, hysteresis float64) bool {
+	switch comparator {
+	case rModels.ConditionLess, rModels.ConditionLessEqual:
+		return value > threshold+hysteresis
+	default:
+		return value < threshold-hysteresis
+	}
+}
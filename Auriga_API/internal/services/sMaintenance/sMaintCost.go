@@ -0,0 +1,165 @@
+package sMaintenance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+const defaultCostWindow = 365 * 24 * time.Hour
+
+// CostReportRow coste acumulado del subárbol de un activo para un mes, tipo y prioridad
+type CostReportRow struct {
+	AssetID       uint     `json:"asset_id"`
+	Code          string   `json:"code"`
+	Path          []string `json:"path"`
+	Month         string   `json:"month"` // AAAA-MM de la fecha de coste
+	WorkOrderType string   `json:"work_order_type"`
+	Priority      string   `json:"priority"`
+	WorkOrders    int      `json:"work_orders"`
+	LaborHours    float64  `json:"labor_hours"`
+	LaborCost     float64  `json:"labor_cost"`
+	PartsCost     float64  `json:"parts_cost"`
+	TotalCost     float64  `json:"total_cost"`
+}
+
+// CostReport costes de mantenimiento agregados por la jerarquía de activos
+type CostReport struct {
+	AssetID   uint            `json:"asset_id"`
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	Rows      []CostReportRow `json:"rows"`
+	LaborCost float64         `json:"labor_cost"`
+	PartsCost float64         `json:"parts_cost"`
+	TotalCost float64         `json:"total_cost"`
+}
+
+// GetCostReport agrega el coste de las OT del subárbol en cada activo hasta depth niveles por debajo del indicado
+func (s *service) GetCostReport(assetID uint, req CostReportRequest) (*CostReport, error) {
+	to := req.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.Add(-defaultCostWindow)
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidRequest)
+	}
+
+	nodes, err := s.repository.GetAssetSubtree(assetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, ErrNotFound
+	}
+
+	byID := make(map[uint]rMaintenance.AssetNode, len(nodes))
+	parents := make(map[uint]*uint, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+		parents[node.ID] = node.ParentID
+	}
+	depths := make(map[uint]int, len(nodes))
+	var depthOf func(id uint) int
+	depthOf = func(id uint) int {
+		if depth, ok := depths[id]; ok {
+			return depth
+		}
+		depth := 0
+		if parent := parents[id]; id != assetID && parent != nil {
+			depth = depthOf(*parent) + 1
+		}
+		depths[id] = depth
+		return depth
+	}
+
+	workOrders, err := s.repository.GetCostWorkOrders(assetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	type rowKey struct {
+		assetID       uint
+		month         string
+		workOrderType rModels.WorkOrderType
+		priority      rModels.PriorityLevel
+	}
+	rows := make(map[rowKey]*CostReportRow)
+	report := &CostReport{AssetID: assetID, From: from, To: to}
+
+	for _, workOrder := range workOrders {
+		costDate := workOrder.CreatedAt
+		if workOrder.EndDate != nil {
+			costDate = *workOrder.EndDate
+		}
+		month := costDate.Format("2006-01")
+
+		report.LaborCost += workOrder.LaborCost
+		report.PartsCost += workOrder.PartsCost
+		report.TotalCost += workOrder.Cost
+
+		// El coste se suma al activo de la OT y a cada antecesor dentro del subárbol pedido
+		for id := workOrder.AssetID; ; {
+			if depthOf(id) <= req.Depth {
+				key := rowKey{assetID: id, month: month, workOrderType: workOrder.WorkOrderType, priority: workOrder.Priority}
+				row, ok := rows[key]
+				if !ok {
+					row = &CostReportRow{
+						AssetID:       id,
+						Code:          byID[id].Code,
+						Path:          []string(byID[id].HierarchicalLevel),
+						Month:         month,
+						WorkOrderType: string(workOrder.WorkOrderType),
+						Priority:      string(workOrder.Priority),
+					}
+					rows[key] = row
+				}
+				row.WorkOrders++
+				row.LaborHours += workOrder.ActualHours
+				row.LaborCost += workOrder.LaborCost
+				row.PartsCost += workOrder.PartsCost
+				row.TotalCost += workOrder.Cost
+			}
+
+			parent := parents[id]
+			if id == assetID || parent == nil {
+				break
+			}
+			id = *parent
+		}
+	}
+
+	report.Rows = make([]CostReportRow, 0, len(rows))
+	for _, row := range rows {
+		row.LaborHours = round2(row.LaborHours)
+		row.LaborCost = round2(row.LaborCost)
+		row.PartsCost = round2(row.PartsCost)
+		row.TotalCost = round2(row.TotalCost)
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if pa, pb := strings.Join(a.Path, "/"), strings.Join(b.Path, "/"); pa != pb {
+			return pa < pb
+		}
+		if a.Month != b.Month {
+			return a.Month < b.Month
+		}
+		if a.WorkOrderType != b.WorkOrderType {
+			return a.WorkOrderType < b.WorkOrderType
+		}
+		return a.Priority < b.Priority
+	})
+
+	report.LaborCost = round2(report.LaborCost)
+	report.PartsCost = round2(report.PartsCost)
+	report.TotalCost = round2(report.TotalCost)
+	return report, nil
+}
@@ -0,0 +1,259 @@
+package sMaintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultEventRulesInterval = 30 * time.Second
+	stopEventBatchSize        = 100
+	maxStopEventAttempts      = 5
+)
+
+// openWorkOrderStatuses estados en los que una OT generada por evento agrupa los nuevos eventos del activo
+var openWorkOrderStatuses = []rModels.WorkOrderStatus{
+	rModels.WorkOrderRequested,
+	rModels.WorkOrderPlanned,
+	rModels.WorkOrderInProgress,
+	rModels.WorkOrderOnHold,
+}
+
+func (s *service) GetEventRules() ([]rModels.MrMaintEventRule, error) {
+	return s.repository.GetEventRules()
+}
+
+func (s *service) CreateEventRule(req EventRuleRequest) (*rModels.MrMaintEventRule, error) {
+	rule := &rModels.MrMaintEventRule{Active: true}
+	if err := s.applyEventRule(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateEventRule(rule); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetEventRuleByID(rule.ID)
+}
+
+func (s *service) UpdateEventRule(id uint, req EventRuleRequest) (*rModels.MrMaintEventRule, error) {
+	rule, err := s.repository.GetEventRuleByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if err := s.applyEventRule(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateEventRule(rule); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetEventRuleByID(id)
+}
+
+func (s *service) DeleteEventRule(id uint) error {
+	if _, err := s.repository.GetEventRuleByID(id); err != nil {
+		return notFound(err)
+	}
+	return s.repository.DeleteEventRule(id)
+}
+
+// applyEventRule comprueba que la categoría existe y que el tipo, si se indica, pertenece a ella
+func (s *service) applyEventRule(rule *rModels.MrMaintEventRule, req EventRuleRequest) error {
+	if _, err := s.repository.GetEventCategory(req.EventCategoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: event category %d does not exist", ErrInvalidRequest, req.EventCategoryID)
+		}
+		return err
+	}
+	if req.EventTypeID != nil {
+		eventType, err := s.repository.GetEventType(*req.EventTypeID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: event type %d does not exist", ErrInvalidRequest, *req.EventTypeID)
+		}
+		if err != nil {
+			return err
+		}
+		if eventType.EventCategoryID != req.EventCategoryID {
+			return fmt.Errorf("%w: event type %d does not belong to category %d", ErrInvalidRequest, *req.EventTypeID, req.EventCategoryID)
+		}
+	}
+
+	rule.Name = req.Name
+	rule.EventCategoryID = req.EventCategoryID
+	rule.EventTypeID = req.EventTypeID
+	rule.Priority = rModels.PriorityLevel(req.Priority)
+	rule.Title = req.Title
+	rule.AssignedTeamID = req.AssignedTeamID
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	return nil
+}
+
+// ProcessStopEvent aplica las reglas al evento confirmado y lo saca de la cola de reglas.
+// Devuelve nil si el evento ya estaba vinculado o ninguna regla aplica.
+func (s *service) ProcessStopEvent(eventID uint) (*rModels.MrMaintWorkOrder, error) {
+	event, err := s.repository.GetCommitEvent(eventID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	workOrder, err := s.applyEventRules(event)
+	if errors.Is(err, rMaintenance.ErrEventChanged) {
+		return nil, fmt.Errorf("%w: event %d changed while applying the rules, retry", ErrInvalidTransition, eventID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.RecordStopEventResult(event, true, ""); err != nil {
+		return nil, err
+	}
+	return workOrder, nil
+}
+
+// StartStopEventProcessor aplica periódicamente las reglas de mantenimiento a los eventos confirmados
+// pendientes hasta que se cancela el contexto. Los eventos se confirman sin esperar a las reglas.
+func (s *service) StartStopEventProcessor(ctx context.Context) {
+	cfg := s.config.Maintenance
+	if !cfg.EventRulesEnabled {
+		s.logger.Info("Reglas de mantenimiento por evento deshabilitadas")
+		return
+	}
+
+	interval := cfg.EventRulesInterval
+	if interval <= 0 {
+		interval = defaultEventRulesInterval
+	}
+
+	s.logger.Info("Iniciando reglas de mantenimiento por evento", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.runStopEventProcessor()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Deteniendo reglas de mantenimiento por evento")
+			return
+		case <-ticker.C:
+			s.runStopEventProcessor()
+		}
+	}
+}
+
+// runStopEventProcessor procesa un lote de eventos pendientes. Un error se guarda en el evento: si es
+// definitivo (p. ej. la ruta no corresponde a ningún activo) o se agotan los intentos, el evento sale de
+// la cola con el error; si no, se reintenta en el siguiente ciclo.
+func (s *service) runStopEventProcessor() {
+	events, err := s.repository.GetPendingStopEvents(stopEventBatchSize)
+	if err != nil {
+		s.logger.Error("Error getting pending stop events", zap.Error(err))
+		return
+	}
+
+	for i := range events {
+		event := &events[i]
+		_, err := s.applyEventRules(event)
+		if errors.Is(err, rMaintenance.ErrEventChanged) {
+			continue // Se reclasificó mientras tanto: sigue pendiente con los datos nuevos
+		}
+
+		processed, errMsg := true, ""
+		if err != nil {
+			errMsg = err.Error()
+			processed = errors.Is(err, ErrInvalidRequest) || event.MaintAttempts+1 >= maxStopEventAttempts
+			s.logger.Warn("Cannot apply maintenance rules to stop event",
+				zap.Uint("event_id", event.ID),
+				zap.Int("attempt", event.MaintAttempts+1),
+				zap.Bool("giving_up", processed),
+				zap.Error(err),
+			)
+		}
+		if err := s.repository.RecordStopEventResult(event, processed, errMsg); err != nil {
+			s.logger.Error("Error recording stop event result", zap.Uint("event_id", event.ID), zap.Error(err))
+		}
+	}
+}
+
+// applyEventRules crea una OT correctiva sobre el activo de la ruta del evento, o lo vincula a la OT que
+// la misma regla tenga abierta en ese activo
+func (s *service) applyEventRules(event *rModels.MrCommitEvents) (*rModels.MrMaintWorkOrder, error) {
+	if event.WorkOrderID != nil || event.EventCategory == "" {
+		return nil, nil
+	}
+
+	rules, err := s.repository.GetActiveEventRules(event.EventCategory)
+	if err != nil {
+		return nil, err
+	}
+	var rule *rModels.MrMaintEventRule
+	for i := range rules {
+		if rules[i].EventType == nil || rules[i].EventType.Name == event.EventType {
+			rule = &rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		return nil, nil
+	}
+
+	path := []string{event.Factory, event.ProdLine, event.System, event.Machine, event.Part}
+	for len(path) > 0 && path[len(path)-1] == "" {
+		path = path[:len(path)-1]
+	}
+	asset, err := s.repository.FindAssetByPath(path)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: no asset matches event path %s", ErrInvalidRequest, strings.Join(path, "/"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	title := rule.Title
+	if title == "" {
+		title = fmt.Sprintf("%s: %s on %s", event.EventCategory, event.EventType, asset.Code)
+	}
+
+	now := time.Now()
+	createdBy := s.config.Maintenance.SystemEmployeeID
+	workOrder := &rModels.MrMaintWorkOrder{
+		AssetID:        asset.ID,
+		WorkOrderType:  rModels.WorkOrderTypeCorrective,
+		Priority:       rule.Priority,
+		Status:         rModels.WorkOrderRequested,
+		Title:          title,
+		Description:    fmt.Sprintf("Stop event %d at %s on %s (rule %q)", event.ID, event.EventTime.Format(time.RFC3339), strings.Join(path, "/"), rule.Name),
+		ScheduledDate:  now,
+		CreatedBy:      createdBy,
+		AssignedTeamID: rule.AssignedTeamID,
+		EventRuleID:    &rule.ID,
+	}
+	history := &rModels.MrMaintWorkOrderStatusHistory{
+		ToStatus:  rModels.WorkOrderRequested,
+		ChangedBy: createdBy,
+		ChangedAt: now,
+		Reason:    fmt.Sprintf("Created by event rule %q", rule.Name),
+	}
+
+	workOrderID, created, err := s.repository.CreateEventWorkOrder(workOrder, history, event, openWorkOrderStatuses)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		s.logger.Info("Corrective work order created from stop event",
+			zap.Uint("work_order_id", workOrderID), zap.Uint("event_id", event.ID), zap.Uint("rule_id", rule.ID))
+	}
+
+	return s.repository.GetWorkOrderByID(workOrderID)
+}
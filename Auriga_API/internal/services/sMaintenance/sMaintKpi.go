@@ -0,0 +1,347 @@
+package sMaintenance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+const defaultKpiWindow = 30 * 24 * time.Hour
+
+// AssetKpi indicadores de fiabilidad de un activo agregados sobre todo su subárbol
+type AssetKpi struct {
+	AssetID             uint       `json:"asset_id"`
+	ParentID            *uint      `json:"parent_id"`
+	Code                string     `json:"code"`
+	Path                []string   `json:"path"`
+	Failures            int        `json:"failures"`              // OT correctivas con el fallo dentro de la ventana
+	StopEvents          int        `json:"stop_events"`           // Paradas confirmadas en la ruta del activo iniciadas en la ventana
+	PlannedWorkOrders   int        `json:"planned_work_orders"`   // Preventivas, predictivas e inspecciones
+	UnplannedWorkOrders int        `json:"unplanned_work_orders"` // Correctivas
+	PlannedRatio        *float64   `json:"planned_ratio"`
+	DowntimeHours       float64    `json:"downtime_hours"` // Duración de las paradas confirmadas, sin contar dos veces los solapes
+	UptimeHours         float64    `json:"uptime_hours"`
+	MTBFHours           *float64   `json:"mtbf_hours"`
+	MTTRHours           *float64   `json:"mttr_hours"` // Del fallo al fin de la reparación
+	Availability        float64    `json:"availability"`
+	Children            []AssetKpi `json:"children,omitempty"`
+}
+
+// AssetKpiReport indicadores de un activo y, hasta la profundidad pedida, de sus descendientes
+type AssetKpiReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	AssetKpi
+}
+
+// AssetKpiRanking activos ordenados de peor a mejor según el criterio pedido
+type AssetKpiRanking struct {
+	From   time.Time  `json:"from"`
+	To     time.Time  `json:"to"`
+	By     string     `json:"by"`
+	Assets []AssetKpi `json:"assets"`
+}
+
+// kpiInterval periodo de indisponibilidad recortado a la ventana
+type kpiInterval struct {
+	start time.Time
+	end   time.Time
+}
+
+// kpiTree subárbol de activos con las OT y paradas ya asignadas a cada nodo
+type kpiTree struct {
+	from       time.Time
+	to         time.Time
+	nodes      map[uint]rMaintenance.AssetNode
+	children   map[uint][]uint
+	workOrders map[uint][]rMaintenance.KpiWorkOrder
+	stops      map[uint][]rMaintenance.KpiStop
+}
+
+// GetAssetKpis calcula los indicadores del activo y los de sus descendientes hasta depth niveles
+func (s *service) GetAssetKpis(assetID uint, req AssetKpiRequest) (*AssetKpiReport, error) {
+	tree, err := s.loadKpiTree(assetID, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := req.Depth
+	if depth < 0 {
+		depth = 0
+	}
+
+	return &AssetKpiReport{
+		From:     tree.from,
+		To:       tree.to,
+		AssetKpi: tree.kpi(assetID, depth),
+	}, nil
+}
+
+// GetAssetKpiRanking ordena los descendientes del activo de peor a mejor
+func (s *service) GetAssetKpiRanking(assetID uint, req AssetKpiRankingRequest) (*AssetKpiRanking, error) {
+	tree, err := s.loadKpiTree(assetID, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	by := req.By
+	if by == "" {
+		by = "downtime"
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var kpis []AssetKpi
+	for id, node := range tree.nodes {
+		if id == assetID {
+			continue
+		}
+		if req.Level > 0 && len(node.HierarchicalLevel) != req.Level {
+			continue
+		}
+		kpis = append(kpis, tree.kpi(id, 0))
+	}
+
+	sort.SliceStable(kpis, func(i, j int) bool {
+		a, b := kpis[i], kpis[j]
+		switch by {
+		case "failures":
+			if a.Failures != b.Failures {
+				return a.Failures > b.Failures
+			}
+		case "mttr":
+			if x, y := valueOr(a.MTTRHours, -1), valueOr(b.MTTRHours, -1); x != y {
+				return x > y
+			}
+		case "mtbf":
+			// Sin fallos el MTBF no está definido y el activo va al final
+			if x, y := valueOr(a.MTBFHours, math.Inf(1)), valueOr(b.MTBFHours, math.Inf(1)); x != y {
+				return x < y
+			}
+		case "availability":
+			if a.Availability != b.Availability {
+				return a.Availability < b.Availability
+			}
+		}
+		if a.DowntimeHours != b.DowntimeHours {
+			return a.DowntimeHours > b.DowntimeHours
+		}
+		return a.AssetID < b.AssetID
+	})
+	if len(kpis) > limit {
+		kpis = kpis[:limit]
+	}
+	if kpis == nil {
+		kpis = []AssetKpi{}
+	}
+
+	return &AssetKpiRanking{From: tree.from, To: tree.to, By: by, Assets: kpis}, nil
+}
+
+// loadKpiTree carga el subárbol y asigna cada OT a su activo y cada parada al activo más profundo de su ruta
+func (s *service) loadKpiTree(assetID uint, from time.Time, to time.Time) (*kpiTree, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultKpiWindow)
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidRequest)
+	}
+
+	nodes, err := s.repository.GetAssetSubtree(assetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, ErrNotFound
+	}
+
+	tree := &kpiTree{
+		from:       from,
+		to:         to,
+		nodes:      make(map[uint]rMaintenance.AssetNode, len(nodes)),
+		children:   make(map[uint][]uint),
+		workOrders: make(map[uint][]rMaintenance.KpiWorkOrder),
+		stops:      make(map[uint][]rMaintenance.KpiStop),
+	}
+	byPath := make(map[string]uint, len(nodes))
+	for _, node := range nodes {
+		tree.nodes[node.ID] = node
+		if node.ParentID != nil && node.ID != assetID {
+			tree.children[*node.ParentID] = append(tree.children[*node.ParentID], node.ID)
+		}
+		byPath[pathKey(node.HierarchicalLevel)] = node.ID
+	}
+
+	workOrders, err := s.repository.GetKpiWorkOrders(assetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, workOrder := range workOrders {
+		tree.workOrders[workOrder.AssetID] = append(tree.workOrders[workOrder.AssetID], workOrder)
+	}
+
+	stops, err := s.repository.GetKpiStops(tree.nodes[assetID].HierarchicalLevel, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, stop := range stops {
+		path := []string{stop.Factory, stop.ProdLine, stop.System, stop.Machine, stop.Part}
+		for len(path) > 0 && path[len(path)-1] == "" {
+			path = path[:len(path)-1]
+		}
+		for n := len(path); n > 0; n-- {
+			if id, ok := byPath[pathKey(path[:n])]; ok {
+				tree.stops[id] = append(tree.stops[id], stop)
+				break
+			}
+		}
+	}
+
+	return tree, nil
+}
+
+// kpi agrega las OT y paradas de todo el subárbol del nodo
+func (t *kpiTree) kpi(id uint, depth int) AssetKpi {
+	node := t.nodes[id]
+	result := AssetKpi{
+		AssetID:  node.ID,
+		ParentID: node.ParentID,
+		Code:     node.Code,
+		Path:     []string(node.HierarchicalLevel),
+	}
+
+	var intervals []kpiInterval
+	var repairHours float64
+	var repaired int
+
+	// Una parada sin fin sigue en curso: cuenta hasta el final de la ventana o hasta ahora
+	openEnd := t.to
+	if now := time.Now(); now.Before(openEnd) {
+		openEnd = now
+	}
+
+	t.walk(id, func(nodeID uint) {
+		for _, stop := range t.stops[nodeID] {
+			if !stop.EventTime.Before(t.from) {
+				result.StopEvents++
+			}
+
+			start, end := stop.EventTime, openEnd
+			if stop.EndTime != nil && stop.EndTime.Before(end) {
+				end = *stop.EndTime
+			}
+			if start.Before(t.from) {
+				start = t.from
+			}
+			if end.After(start) {
+				intervals = append(intervals, kpiInterval{start: start, end: end})
+			}
+		}
+
+		for _, workOrder := range t.workOrders[nodeID] {
+			if workOrder.WorkOrderType != rModels.WorkOrderTypeCorrective {
+				if !workOrder.CreatedAt.Before(t.from) {
+					result.PlannedWorkOrders++
+				}
+				continue
+			}
+
+			if workOrder.FailureAt.Before(t.from) || workOrder.FailureAt.After(t.to) {
+				continue
+			}
+			result.UnplannedWorkOrders++
+			result.Failures++
+			if workOrder.EndDate != nil && workOrder.EndDate.After(workOrder.FailureAt) {
+				repairHours += workOrder.EndDate.Sub(workOrder.FailureAt).Hours()
+				repaired++
+			}
+		}
+	})
+
+	windowHours := t.to.Sub(t.from).Hours()
+	result.DowntimeHours = round2(mergedHours(intervals))
+	result.UptimeHours = round2(windowHours - result.DowntimeHours)
+	result.Availability = round4(result.UptimeHours / windowHours)
+
+	if total := result.PlannedWorkOrders + result.UnplannedWorkOrders; total > 0 {
+		ratio := round4(float64(result.PlannedWorkOrders) / float64(total))
+		result.PlannedRatio = &ratio
+	}
+	if result.Failures > 0 {
+		mtbf := round2(result.UptimeHours / float64(result.Failures))
+		result.MTBFHours = &mtbf
+	}
+	if repaired > 0 {
+		mttr := round2(repairHours / float64(repaired))
+		result.MTTRHours = &mttr
+	}
+
+	if depth > 0 {
+		for _, childID := range t.children[id] {
+			result.Children = append(result.Children, t.kpi(childID, depth-1))
+		}
+	}
+
+	return result
+}
+
+// walk recorre el nodo y todos sus descendientes
+func (t *kpiTree) walk(id uint, visit func(nodeID uint)) {
+	visit(id)
+	for _, childID := range t.children[id] {
+		t.walk(childID, visit)
+	}
+}
+
+// mergedHours suma las horas de los intervalos sin contar dos veces los solapes
+func mergedHours(intervals []kpiInterval) float64 {
+	if len(intervals) == 0 {
+		return 0
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+
+	var total time.Duration
+	current := intervals[0]
+	for _, interval := range intervals[1:] {
+		if interval.start.After(current.end) {
+			total += current.end.Sub(current.start)
+			current = interval
+			continue
+		}
+		if interval.end.After(current.end) {
+			current.end = interval.end
+		}
+	}
+	total += current.end.Sub(current.start)
+	return total.Hours()
+}
+
+// pathKey clave de mapa para una ruta jerárquica
+func pathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+func valueOr(value *float64, fallback float64) float64 {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func round4(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
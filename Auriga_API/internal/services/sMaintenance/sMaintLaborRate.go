@@ -0,0 +1,64 @@
+package sMaintenance
+
+import (
+	"fmt"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+func (s *service) GetLaborRates() ([]rModels.MrMaintLaborRate, error) {
+	return s.repository.GetLaborRates()
+}
+
+func (s *service) CreateLaborRate(req LaborRateRequest) (*rModels.MrMaintLaborRate, error) {
+	rate := &rModels.MrMaintLaborRate{
+		EmployeeID: req.EmployeeID,
+		Role:       req.Role,
+		HourlyRate: req.HourlyRate,
+	}
+	if rate.EmployeeID != nil {
+		rate.Role = ""
+	}
+
+	if err := s.repository.CreateLaborRate(rate); err != nil {
+		return nil, laborRateError(err)
+	}
+
+	return s.repository.GetLaborRateByID(rate.ID)
+}
+
+func (s *service) UpdateLaborRate(id uint, req LaborRateRequest) (*rModels.MrMaintLaborRate, error) {
+	rate, err := s.repository.GetLaborRateByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	rate.EmployeeID = req.EmployeeID
+	rate.Role = req.Role
+	rate.HourlyRate = req.HourlyRate
+	if rate.EmployeeID != nil {
+		rate.Role = ""
+	}
+
+	if err := s.repository.UpdateLaborRate(rate); err != nil {
+		return nil, laborRateError(err)
+	}
+
+	return s.repository.GetLaborRateByID(id)
+}
+
+func (s *service) DeleteLaborRate(id uint) error {
+	if _, err := s.repository.GetLaborRateByID(id); err != nil {
+		return notFound(err)
+	}
+	return s.repository.DeleteLaborRate(id)
+}
+
+// laborRateError traduce la violación de unicidad a un error de petición
+func laborRateError(err error) error {
+	if rMaintenance.IsDuplicateKey(err) {
+		return fmt.Errorf("%w: a labor rate already exists for this employee or role", ErrInvalidRequest)
+	}
+	return err
+}
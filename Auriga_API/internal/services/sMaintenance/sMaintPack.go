@@ -0,0 +1,238 @@
+package sMaintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+// PackAsset identificación del activo sobre el que se trabaja
+type PackAsset struct {
+	ID       uint   `json:"id"`
+	Code     string `json:"code"`
+	TechCode uint   `json:"tech_code"`
+	Sn       string `json:"sn"`
+	Location string `json:"location"`
+	Path     string `json:"path"` // Ruta jerárquica fábrica/línea/.../activo
+}
+
+// PackStep paso de la lista de comprobación
+type PackStep struct {
+	Number           int    `json:"number"`
+	Title            string `json:"title"`
+	Description      string `json:"description"`
+	ExpectedDuration int    `json:"expected_duration"` // en minutos
+	SafetyNotes      string `json:"safety_notes"`
+	Done             bool   `json:"done"` // La tarea correspondiente ya está completada
+}
+
+// PackStockLocation lote disponible del que recoger un repuesto
+type PackStockLocation struct {
+	Warehouse   string     `json:"warehouse"`
+	Location    string     `json:"location"`
+	BatchNumber string     `json:"batch_number"`
+	ExpiryDate  *time.Time `json:"expiry_date"`
+	Quantity    float64    `json:"quantity"`
+}
+
+// PackSparePart repuesto pendiente de recoger o ya entregado a la orden
+type PackSparePart struct {
+	ProductID uint                `json:"product_id"`
+	Product   string              `json:"product"`
+	Quantity  float64             `json:"quantity"`
+	Unit      string              `json:"unit"`
+	Issued    bool                `json:"issued"`
+	Locations []PackStockLocation `json:"locations"`
+}
+
+type PackDocument struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+// WorkOrderPack contenido del dossier imprimible de una orden de trabajo
+type WorkOrderPack struct {
+	WorkOrderID    uint            `json:"work_order_id"`
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	WorkOrderType  string          `json:"work_order_type"`
+	Priority       string          `json:"priority"`
+	Status         string          `json:"status"`
+	ScheduledDate  time.Time       `json:"scheduled_date"`
+	EstimatedHours float64         `json:"estimated_hours"`
+	PlanName       string          `json:"plan_name"`
+	Team           string          `json:"team"`
+	Technicians    []string        `json:"technicians"`
+	Asset          PackAsset       `json:"asset"`
+	Steps          []PackStep      `json:"steps"`
+	SafetyNotes    []string        `json:"safety_notes"`
+	SpareParts     []PackSparePart `json:"spare_parts"`
+	Documents      []PackDocument  `json:"documents"`
+	URL            string          `json:"url"` // Enlace a la orden codificado en el QR
+	GeneratedAt    time.Time       `json:"generated_at"`
+}
+
+func (s *service) GetWorkOrderPack(id uint) (*WorkOrderPack, error) {
+	workOrder, err := s.repository.GetWorkOrderPack(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	pack := &WorkOrderPack{
+		WorkOrderID:    workOrder.ID,
+		Title:          workOrder.Title,
+		Description:    workOrder.Description,
+		WorkOrderType:  string(workOrder.WorkOrderType),
+		Priority:       string(workOrder.Priority),
+		Status:         string(workOrder.Status),
+		ScheduledDate:  workOrder.ScheduledDate,
+		EstimatedHours: workOrder.EstimatedHours,
+		Asset: PackAsset{
+			ID:       workOrder.Asset.ID,
+			Code:     workOrder.Asset.Code,
+			TechCode: workOrder.Asset.TechCode,
+			Sn:       workOrder.Asset.Sn,
+			Location: workOrder.Asset.Location,
+			Path:     strings.Join(workOrder.Asset.HierarchicalLevel, " / "),
+		},
+		GeneratedAt: time.Now(),
+	}
+	if workOrder.MaintenancePlan != nil {
+		pack.PlanName = workOrder.MaintenancePlan.Name
+	}
+	if workOrder.AssignedTeam != nil {
+		pack.Team = workOrder.AssignedTeam.Name
+	}
+	for _, member := range workOrder.AssignedMembers {
+		pack.Technicians = append(pack.Technicians,
+			fmt.Sprintf("%s %s (%s)", member.Employee.FirstName, member.Employee.LastName, member.Role))
+	}
+	pack.URL = fmt.Sprintf("%s/maintenance/work-orders/%d", strings.TrimRight(s.config.Maintenance.PublicURL, "/"), workOrder.ID)
+
+	pack.Steps, pack.SafetyNotes = packSteps(workOrder)
+	if pack.SpareParts, err = s.packSpareParts(workOrder); err != nil {
+		return nil, err
+	}
+	pack.Documents = packDocuments(workOrder)
+
+	return pack, nil
+}
+
+// packSteps usa los procedimientos del plan y, en las órdenes sin plan, sus tareas
+func packSteps(workOrder *rModels.MrMaintWorkOrder) ([]PackStep, []string) {
+	completed := make(map[int]bool)
+	for _, task := range workOrder.Tasks {
+		if task.Status == rModels.TaskCompleted {
+			completed[task.TaskNumber] = true
+		}
+	}
+
+	var steps []PackStep
+	var safetyNotes []string
+	if workOrder.MaintenancePlan != nil && len(workOrder.MaintenancePlan.Procedures) > 0 {
+		for _, procedure := range workOrder.MaintenancePlan.Procedures {
+			steps = append(steps, PackStep{
+				Number:           procedure.StepNumber,
+				Title:            procedure.Title,
+				Description:      procedure.Description,
+				ExpectedDuration: procedure.ExpectedDuration,
+				SafetyNotes:      procedure.SafetyNotes,
+				Done:             completed[procedure.StepNumber],
+			})
+			if note := strings.TrimSpace(procedure.SafetyNotes); note != "" {
+				safetyNotes = append(safetyNotes, fmt.Sprintf("%d. %s", procedure.StepNumber, note))
+			}
+		}
+		return steps, safetyNotes
+	}
+
+	for _, task := range workOrder.Tasks {
+		steps = append(steps, PackStep{
+			Number: task.TaskNumber,
+			Title:  task.Description,
+			Done:   task.Status == rModels.TaskCompleted,
+		})
+	}
+	return steps, safetyNotes
+}
+
+// packSpareParts lista las reservas pendientes con los lotes de los que recogerlas y los repuestos ya entregados
+func (s *service) packSpareParts(workOrder *rModels.MrMaintWorkOrder) ([]PackSparePart, error) {
+	var productIDs []uint
+	for _, reservation := range workOrder.Reservations {
+		if reservation.Status == rModels.ReservationReserved {
+			productIDs = append(productIDs, reservation.ProductID)
+		}
+	}
+
+	stocks, err := s.repository.GetAvailableStocks(productIDs)
+	if err != nil {
+		return nil, err
+	}
+	locations := make(map[uint][]PackStockLocation)
+	for _, stock := range stocks {
+		locations[stock.ProductID] = append(locations[stock.ProductID], packStockLocation(stock))
+	}
+
+	var spareParts []PackSparePart
+	for _, reservation := range workOrder.Reservations {
+		if reservation.Status != rModels.ReservationReserved {
+			continue
+		}
+		spareParts = append(spareParts, PackSparePart{
+			ProductID: reservation.ProductID,
+			Product:   reservation.Product.Name,
+			Quantity:  reservation.Quantity,
+			Unit:      reservation.Unit,
+			Locations: locations[reservation.ProductID],
+		})
+	}
+	for _, used := range workOrder.UsedSpareParts {
+		sparePart := PackSparePart{
+			ProductID: used.ProductID,
+			Product:   used.Product.Name,
+			Quantity:  used.Quantity,
+			Issued:    true,
+		}
+		if used.Stock != nil {
+			sparePart.Locations = []PackStockLocation{packStockLocation(*used.Stock)}
+		}
+		spareParts = append(spareParts, sparePart)
+	}
+	return spareParts, nil
+}
+
+func packStockLocation(stock rModels.MrSparePartStock) PackStockLocation {
+	return PackStockLocation{
+		Warehouse:   stock.WarehouseAsset.Code,
+		Location:    stock.Location,
+		BatchNumber: stock.BatchNumber,
+		ExpiryDate:  stock.ExpiryDate,
+		Quantity:    stock.Quantity,
+	}
+}
+
+// packDocuments une los documentos de la orden y los del activo sin repetirlos
+func packDocuments(workOrder *rModels.MrMaintWorkOrder) []PackDocument {
+	var documents []PackDocument
+	seen := make(map[uint]bool)
+	for _, group := range [][]rModels.MrDocuments{workOrder.Documents, workOrder.Asset.Documents} {
+		for _, document := range group {
+			if seen[document.ID] {
+				continue
+			}
+			seen[document.ID] = true
+			documents = append(documents, PackDocument{
+				ID:          document.ID,
+				Name:        document.Nombre,
+				Description: document.Descripcion,
+				URL:         document.URL,
+			})
+		}
+	}
+	return documents
+}
@@ -0,0 +1,206 @@
+package sMaintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"go.uber.org/zap"
+)
+
+const defaultSchedulerInterval = 5 * time.Minute
+
+// calendarFrequencies son los tipos de frecuencia que se planifican por fecha
+var calendarFrequencies = []rModels.FrequencyType{
+	rModels.FrequencyDays,
+	rModels.FrequencyWeeks,
+	rModels.FrequencyMonths,
+	rModels.FrequencyHours,
+}
+
+// StartPreventiveScheduler genera periódicamente las OT de los planes vencidos hasta que se cancela el contexto
+func (s *service) StartPreventiveScheduler(ctx context.Context) {
+	cfg := s.config.Maintenance
+	if !cfg.SchedulerEnabled {
+		s.logger.Info("Planificador de mantenimiento preventivo deshabilitado")
+		return
+	}
+
+	interval := cfg.SchedulerInterval
+	if interval <= 0 {
+		interval = defaultSchedulerInterval
+	}
+
+	s.logger.Info("Iniciando planificador de mantenimiento preventivo", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.runPreventiveScheduler(time.Now())
+	s.runUsageScheduler(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Deteniendo planificador de mantenimiento preventivo")
+			return
+		case <-ticker.C:
+			s.runPreventiveScheduler(time.Now())
+			s.runUsageScheduler(time.Now())
+		}
+	}
+}
+
+// runPreventiveScheduler crea una OT por cada plan vencido que aún no la tenga
+func (s *service) runPreventiveScheduler(now time.Time) {
+	plans, err := s.repository.GetDuePlans(now, calendarFrequencies)
+	if err != nil {
+		s.logger.Error("Error getting due maintenance plans", zap.Error(err))
+		return
+	}
+
+	for _, plan := range plans {
+		workOrder := buildPlanWorkOrder(plan, rModels.WorkOrderTypePreventive, plan.NextScheduled, s.config.Maintenance.SystemEmployeeID)
+		history := &rModels.MrMaintWorkOrderStatusHistory{
+			ToStatus:  workOrder.Status,
+			ChangedBy: workOrder.CreatedBy,
+			ChangedAt: now,
+			Reason:    "Generated by preventive scheduler",
+		}
+
+		created, err := s.repository.CreatePlanWorkOrder(workOrder, history)
+		if err != nil {
+			s.logger.Error("Error creating preventive work order",
+				zap.Uint("plan_id", plan.ID),
+				zap.Time("occurrence", plan.NextScheduled),
+				zap.Error(err),
+			)
+			continue
+		}
+		if created {
+			s.logger.Info("Orden de trabajo preventiva generada",
+				zap.Uint("plan_id", plan.ID),
+				zap.Uint("work_order_id", workOrder.ID),
+				zap.Time("occurrence", plan.NextScheduled),
+			)
+		}
+	}
+}
+
+// buildPlanWorkOrder prepara la OT de una ocurrencia del plan con tareas y reservas de repuestos
+func buildPlanWorkOrder(plan rModels.MrMaintenancePlan, workOrderType rModels.WorkOrderType, occurrence time.Time, createdBy uint) *rModels.MrMaintWorkOrder {
+	planID := plan.ID
+	workOrder := &rModels.MrMaintWorkOrder{
+		MaintenancePlanID: &planID,
+		PlanOccurrence:    &occurrence,
+		AssetID:           plan.AssetID,
+		WorkOrderType:     workOrderType,
+		Priority:          plan.Priority,
+		Status:            rModels.WorkOrderPlanned,
+		Title:             plan.Name,
+		Description:       plan.Description,
+		ScheduledDate:     occurrence,
+		EstimatedHours:    float64(plan.DurationMinutes) / 60,
+		CreatedBy:         createdBy,
+	}
+
+	for _, procedure := range plan.Procedures {
+		workOrder.Tasks = append(workOrder.Tasks, rModels.MrMaintWorkOrderTask{
+			TaskNumber:  procedure.StepNumber,
+			Description: procedureTaskDescription(procedure),
+			Status:      rModels.TaskPending,
+		})
+	}
+
+	for _, sparePart := range plan.RequiredSpareParts {
+		workOrder.Reservations = append(workOrder.Reservations, rModels.MrMaintSparePartReservation{
+			ProductID: sparePart.ProductID,
+			Quantity:  sparePart.QuantityRequired,
+			Unit:      sparePart.Unit,
+			Status:    rModels.ReservationReserved,
+		})
+	}
+
+	return workOrder
+}
+
+func procedureTaskDescription(procedure rModels.MrMaintenanceProcedure) string {
+	parts := []string{procedure.Title}
+	if procedure.Description != "" {
+		parts = append(parts, procedure.Description)
+	}
+	if procedure.SafetyNotes != "" {
+		parts = append(parts, fmt.Sprintf("Safety notes: %s", procedure.SafetyNotes))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// nextOccurrence calcula el siguiente vencimiento de un plan por calendario
+func nextOccurrence(frequencyType rModels.FrequencyType, frequencyValue int, from time.Time) (time.Time, bool) {
+	if frequencyValue <= 0 {
+		return time.Time{}, false
+	}
+
+	switch frequencyType {
+	case rModels.FrequencyDays:
+		return from.AddDate(0, 0, frequencyValue), true
+	case rModels.FrequencyWeeks:
+		return from.AddDate(0, 0, 7*frequencyValue), true
+	case rModels.FrequencyMonths:
+		return from.AddDate(0, frequencyValue, 0), true
+	case rModels.FrequencyHours:
+		return from.Add(time.Duration(frequencyValue) * time.Hour), true
+	}
+
+	return time.Time{}, false
+}
+
+// advancePlan prepara el plan de la OT para su siguiente ocurrencia, según sea por calendario o por uso;
+// el cambio se guarda junto con la transición de la orden
+func (s *service) advancePlan(effects *rMaintenance.TransitionEffects, workOrder *rModels.MrMaintWorkOrder, at time.Time, executed bool) error {
+	if workOrder.MaintenancePlan == nil {
+		return nil
+	}
+	if isUsageFrequency(workOrder.MaintenancePlan.FrequencyType) {
+		baseline, err := s.usageBaseline(workOrder, at, executed)
+		if err != nil {
+			return err
+		}
+		effects.UsageBaseline = baseline
+		return nil
+	}
+	effects.PlanRoll = planRoll(workOrder, at, executed)
+	return nil
+}
+
+// planRoll calcula el avance del plan de la OT a la primera ocurrencia posterior a 'until',
+// manteniendo la alineación con el calendario original
+func planRoll(workOrder *rModels.MrMaintWorkOrder, until time.Time, executed bool) *rMaintenance.PlanRoll {
+	if workOrder.MaintenancePlanID == nil || workOrder.PlanOccurrence == nil || workOrder.MaintenancePlan == nil {
+		return nil
+	}
+	plan := workOrder.MaintenancePlan
+	occurrence := *workOrder.PlanOccurrence
+
+	next, ok := nextOccurrence(plan.FrequencyType, plan.FrequencyValue, occurrence)
+	if !ok {
+		return nil
+	}
+	for !next.After(until) {
+		next, _ = nextOccurrence(plan.FrequencyType, plan.FrequencyValue, next)
+	}
+
+	var lastExecuted *time.Time
+	if executed {
+		lastExecuted = &until
+	}
+
+	return &rMaintenance.PlanRoll{
+		PlanID:       plan.ID,
+		Occurrence:   occurrence,
+		LastExecuted: lastExecuted,
+		Next:         next,
+	}
+}
@@ -0,0 +1,239 @@
+package sMaintenance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (s *service) GetWorkOrderTime(workOrderID uint) ([]rModels.MrMaintTimeSegment, error) {
+	if _, err := s.getWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+	return s.repository.GetTimeSegmentsByWorkOrder(workOrderID)
+}
+
+// StartWork ficha el inicio del trabajo del técnico; tras un stop permite volver a empezar
+func (s *service) StartWork(workOrderID uint, employeeID uint) (*rModels.MrMaintTimeSegment, error) {
+	return s.clockOn(workOrderID, employeeID, false)
+}
+
+// ResumeWork reanuda el trabajo del técnico tras una pausa
+func (s *service) ResumeWork(workOrderID uint, employeeID uint) (*rModels.MrMaintTimeSegment, error) {
+	return s.clockOn(workOrderID, employeeID, true)
+}
+
+func (s *service) PauseWork(workOrderID uint, employeeID uint) (*rModels.MrMaintTimeSegment, error) {
+	if _, err := s.getWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+
+	segment, err := s.getOpenSegment(workOrderID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if segment == nil {
+		return nil, fmt.Errorf("%w: employee %d is not clocked on work order %d", ErrInvalidTransition, employeeID, workOrderID)
+	}
+
+	if err := s.closeSegment(segment, false, time.Now()); err != nil {
+		return nil, err
+	}
+	return segment, nil
+}
+
+// StopWork cierra el tramo en curso, si lo hay, y da por terminado el trabajo del técnico en la orden
+func (s *service) StopWork(workOrderID uint, employeeID uint) (*rModels.MrMaintWorkOrderAssignment, error) {
+	if _, err := s.getWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.getEmployeeAssignment(workOrderID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.EndTime != nil {
+		return nil, fmt.Errorf("%w: employee %d has already stopped work on work order %d", ErrInvalidTransition, employeeID, workOrderID)
+	}
+
+	segment, err := s.getOpenSegment(workOrderID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if segment != nil {
+		if err := s.closeSegment(segment, true, now); err != nil {
+			return nil, err
+		}
+		return s.repository.GetAssignmentByID(assignment.ID)
+	}
+
+	// En pausa: solo queda registrar el fin de la asignación
+	count, err := s.repository.CountTimeSegments(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: employee %d has not started work on work order %d", ErrInvalidTransition, employeeID, workOrderID)
+	}
+	assignment.EndTime = &now
+	if err := s.repository.UpdateAssignment(assignment); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetAssignmentByID(assignment.ID)
+}
+
+// clockOn abre un tramo de trabajo comprobando que la orden está en curso, que el técnico está
+// asignado y que no está fichado en otra orden
+func (s *service) clockOn(workOrderID uint, employeeID uint, resume bool) (*rModels.MrMaintTimeSegment, error) {
+	workOrder, err := s.getWorkOrder(workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if workOrder.Status != rModels.WorkOrderInProgress {
+		return nil, fmt.Errorf("%w: work order %d is %s, time can only be recorded while in_progress", ErrInvalidTransition, workOrderID, workOrder.Status)
+	}
+
+	assignment, err := s.getEmployeeAssignment(workOrderID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := s.repository.GetOpenTimeSegment(employeeID)
+	if err == nil {
+		if open.WorkOrderID == workOrderID {
+			return nil, fmt.Errorf("%w: employee %d is already clocked on work order %d", ErrInvalidTransition, employeeID, workOrderID)
+		}
+		return nil, fmt.Errorf("%w: employee %d is clocked on work order %d", ErrInvalidTransition, employeeID, open.WorkOrderID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	count, err := s.repository.CountTimeSegments(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	paused := count > 0 && assignment.EndTime == nil
+	if resume && !paused {
+		return nil, fmt.Errorf("%w: employee %d has no paused work on work order %d, use start", ErrInvalidTransition, employeeID, workOrderID)
+	}
+	if !resume && paused {
+		return nil, fmt.Errorf("%w: employee %d has paused work on work order %d, use resume", ErrInvalidTransition, employeeID, workOrderID)
+	}
+
+	if assignment.EndTime != nil {
+		if err := s.repository.ReopenAssignment(assignment.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	segment := &rModels.MrMaintTimeSegment{
+		AssignmentID: assignment.ID,
+		WorkOrderID:  workOrderID,
+		EmployeeID:   employeeID,
+		StartTime:    now,
+	}
+	if shift := s.currentShift(employeeID, now); shift != nil {
+		segment.ShiftID = &shift.ID
+	}
+
+	if err := s.repository.StartTimeSegment(segment); err != nil {
+		// El índice único parcial detecta dos fichajes simultáneos del mismo empleado
+		if rMaintenance.IsDuplicateKey(err) {
+			return nil, fmt.Errorf("%w: employee %d is already clocked on another work order", ErrInvalidTransition, employeeID)
+		}
+		return nil, err
+	}
+
+	return segment, nil
+}
+
+// closeSegment calcula las horas del tramo y la parte fuera de turno, y lo cierra
+func (s *service) closeSegment(segment *rModels.MrMaintTimeSegment, stop bool, at time.Time) error {
+	s.endSegment(segment, at)
+	if err := s.repository.CloseTimeSegment(segment, stop); err != nil {
+		if errors.Is(err, rMaintenance.ErrSegmentClosed) {
+			return fmt.Errorf("%w: time segment %d was closed by another request", ErrInvalidTransition, segment.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// endSegment fija el fin del tramo con sus horas dentro y fuera del turno del técnico
+func (s *service) endSegment(segment *rModels.MrMaintTimeSegment, at time.Time) {
+	if at.Before(segment.StartTime) {
+		at = segment.StartTime
+	}
+	segment.EndTime = &at
+	segment.Hours = round2(at.Sub(segment.StartTime).Hours())
+	segment.ShiftID = nil
+	segment.OutsideShiftHours = segment.Hours
+	segment.OutsideShift = segment.Hours > 0
+
+	if shift := s.currentShift(segment.EmployeeID, segment.StartTime); shift != nil {
+		inside := shift.Overlap(segment.StartTime, at, time.Duration(shift.Tolerance)*time.Minute)
+		segment.ShiftID = &shift.ID
+		segment.OutsideShiftHours = round2(at.Sub(segment.StartTime).Hours() - inside.Hours())
+		segment.OutsideShift = segment.OutsideShiftHours > 0
+	}
+}
+
+// closeOpenSegments prepara el cierre de los tramos abiertos cuando la orden deja de estar en curso;
+// se guardan junto con la transición de la orden
+func (s *service) closeOpenSegments(effects *rMaintenance.TransitionEffects, workOrderID uint, stop bool, at time.Time) error {
+	segments, err := s.repository.GetOpenTimeSegmentsByWorkOrder(workOrderID)
+	if err != nil {
+		return err
+	}
+	for i := range segments {
+		s.endSegment(&segments[i], at)
+	}
+	effects.CloseSegments = segments
+	effects.StopSegments = stop
+	return nil
+}
+
+// getOpenSegment devuelve el tramo abierto del empleado en la orden, o nil si no está fichado en ella
+func (s *service) getOpenSegment(workOrderID uint, employeeID uint) (*rModels.MrMaintTimeSegment, error) {
+	segment, err := s.repository.GetOpenTimeSegment(employeeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if segment.WorkOrderID != workOrderID {
+		return nil, nil
+	}
+	return segment, nil
+}
+
+func (s *service) getEmployeeAssignment(workOrderID uint, employeeID uint) (*rModels.MrMaintWorkOrderAssignment, error) {
+	assignment, err := s.repository.GetAssignmentByEmployee(workOrderID, employeeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: employee %d is not assigned to work order %d", ErrInvalidRequest, employeeID, workOrderID)
+	}
+	return assignment, err
+}
+
+// currentShift devuelve el turno asignado al empleado en la fecha, o nil si no tiene
+func (s *service) currentShift(employeeID uint, at time.Time) *rModels.MrShift {
+	assignment, err := s.repositoryLabor.GetCurrentAssignment(employeeID, at)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("Error getting shift assignment", zap.Uint("employee_id", employeeID), zap.Error(err))
+		}
+		return nil
+	}
+	return &assignment.Shift
+}
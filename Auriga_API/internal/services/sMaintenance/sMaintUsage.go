@@ -0,0 +1,224 @@
+package sMaintenance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
+	"go.uber.org/zap"
+)
+
+// usageFrequencies son los tipos de frecuencia que se disparan por contador de uso
+var usageFrequencies = []rModels.FrequencyType{
+	rModels.FrequencyOperatingHours,
+	rModels.FrequencyRevolutions,
+	rModels.FrequencyTons,
+	rModels.FrequencyUses,
+}
+
+// PlanUsage uso acumulado de un plan desde su última ejecución
+type PlanUsage struct {
+	PlanID         uint                  `json:"plan_id"`
+	PlanName       string                `json:"plan_name"`
+	AssetID        uint                  `json:"asset_id"`
+	FrequencyType  rModels.FrequencyType `json:"frequency_type"`
+	FrequencyValue int                   `json:"frequency_value"`
+	LastExecuted   *time.Time            `json:"last_executed"`
+	Baseline       *float64              `json:"baseline"`
+	BaselineAt     *time.Time            `json:"baseline_at"`
+	Current        *float64              `json:"current"`
+	ReadAt         *time.Time            `json:"read_at"`
+	Usage          float64               `json:"usage"`
+	Remaining      float64               `json:"remaining"`
+	PercentUsed    float64               `json:"percent_used"`
+	Due            bool                  `json:"due"`
+	Error          string                `json:"error,omitempty"`
+}
+
+func isUsageFrequency(frequencyType rModels.FrequencyType) bool {
+	for _, f := range usageFrequencies {
+		if f == frequencyType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) GetPlansUsage() ([]PlanUsage, error) {
+	plans, err := s.repository.GetActivePlans(usageFrequencies)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PlanUsage, 0, len(plans))
+	for _, plan := range plans {
+		result = append(result, s.planUsage(plan))
+	}
+	return result, nil
+}
+
+func (s *service) GetPlanUsage(id uint) (*PlanUsage, error) {
+	plan, err := s.repository.GetPlanByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if !isUsageFrequency(plan.FrequencyType) {
+		return nil, fmt.Errorf("%w: plan %d is scheduled by %s, not by usage", ErrInvalidRequest, id, plan.FrequencyType)
+	}
+
+	usage := s.planUsage(*plan)
+	return &usage, nil
+}
+
+// planUsage calcula el uso desde la última ejecución; los errores de lectura se informan en el campo Error
+func (s *service) planUsage(plan rModels.MrMaintenancePlan) PlanUsage {
+	usage := PlanUsage{
+		PlanID:         plan.ID,
+		PlanName:       plan.Name,
+		AssetID:        plan.AssetID,
+		FrequencyType:  plan.FrequencyType,
+		FrequencyValue: plan.FrequencyValue,
+		LastExecuted:   plan.LastExecuted,
+		Baseline:       plan.UsageBaseline,
+		BaselineAt:     plan.UsageBaselineAt,
+		Remaining:      float64(plan.FrequencyValue),
+	}
+
+	counter, err := s.readPlanCounter(plan)
+	if err != nil {
+		usage.Error = err.Error()
+		return usage
+	}
+	usage.Current = &counter.Value
+	usage.ReadAt = &counter.Time
+
+	if plan.UsageBaseline == nil {
+		return usage
+	}
+
+	usage.Usage = counter.Value - *plan.UsageBaseline
+	if usage.Usage < 0 {
+		// El contador se ha reiniciado: se cuenta desde cero
+		usage.Usage = counter.Value
+	}
+	usage.Remaining = float64(plan.FrequencyValue) - usage.Usage
+	if usage.Remaining < 0 {
+		usage.Remaining = 0
+	}
+	if plan.FrequencyValue > 0 {
+		usage.PercentUsed = usage.Usage / float64(plan.FrequencyValue) * 100
+		usage.Due = usage.Usage >= float64(plan.FrequencyValue)
+	}
+
+	return usage
+}
+
+// readPlanCounter lee el contador del plan en el InfluxDB de la fábrica de su activo
+func (s *service) readPlanCounter(plan rModels.MrMaintenancePlan) (riInfluxdb.FieldValue, error) {
+	if plan.UsageCounterField == nil || *plan.UsageCounterField == "" {
+		return riInfluxdb.FieldValue{}, errors.New("plan has no usage counter configured")
+	}
+
+	// hierarchical_level del activo: [fábrica, línea, ...]
+	levels := plan.Asset.HierarchicalLevel
+	if len(levels) < 2 {
+		return riInfluxdb.FieldValue{}, fmt.Errorf("asset %d is not located in a production line", plan.AssetID)
+	}
+
+	var path []string
+	if plan.UsageCounterPath != nil && *plan.UsageCounterPath != "" {
+		path = strings.Split(*plan.UsageCounterPath, "/")
+	}
+
+	return s.repositoryInflux.GetCounterValue(levels[0], levels[1], path, *plan.UsageCounterField)
+}
+
+// runUsageScheduler abre una OT para cada plan por uso que ha superado su frecuencia
+func (s *service) runUsageScheduler(now time.Time) {
+	plans, err := s.repository.GetActivePlans(usageFrequencies)
+	if err != nil {
+		s.logger.Error("Error getting usage based maintenance plans", zap.Error(err))
+		return
+	}
+
+	for _, plan := range plans {
+		usage := s.planUsage(plan)
+		if usage.Error != "" {
+			s.logger.Warn("Cannot read usage counter for maintenance plan",
+				zap.Uint("plan_id", plan.ID),
+				zap.String("error", usage.Error),
+			)
+			continue
+		}
+
+		// Primera lectura: el uso se empieza a contar desde ahora
+		if plan.UsageBaselineAt == nil {
+			if err := s.repository.UpdatePlanUsageBaseline(plan.ID, nil, *usage.Current, now, nil); err != nil {
+				s.logger.Error("Error initializing usage baseline", zap.Uint("plan_id", plan.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		if !usage.Due {
+			continue
+		}
+
+		// La ocurrencia es la fecha de la referencia: no cambia hasta cerrar la OT y evita duplicados
+		workOrder := buildPlanWorkOrder(plan, rModels.WorkOrderTypePreventive, *plan.UsageBaselineAt, s.config.Maintenance.SystemEmployeeID)
+		workOrder.ScheduledDate = now
+		history := &rModels.MrMaintWorkOrderStatusHistory{
+			ToStatus:  workOrder.Status,
+			ChangedBy: workOrder.CreatedBy,
+			ChangedAt: now,
+			Reason:    fmt.Sprintf("Generated by usage trigger: %.2f %s since last service", usage.Usage, plan.FrequencyType),
+		}
+
+		created, err := s.repository.CreatePlanWorkOrder(workOrder, history)
+		if err != nil {
+			s.logger.Error("Error creating usage based work order", zap.Uint("plan_id", plan.ID), zap.Error(err))
+			continue
+		}
+		if created {
+			s.logger.Info("Orden de trabajo por uso generada",
+				zap.Uint("plan_id", plan.ID),
+				zap.Uint("work_order_id", workOrder.ID),
+				zap.Float64("usage", usage.Usage),
+			)
+		}
+	}
+}
+
+// usageBaseline toma la lectura actual del contador como nueva referencia del plan. Sin lectura no se
+// puede cerrar la ocurrencia: el plan volvería a vencer de inmediato con la referencia anterior.
+func (s *service) usageBaseline(workOrder *rModels.MrMaintWorkOrder, at time.Time, executed bool) (*rMaintenance.UsageBaseline, error) {
+	if workOrder.MaintenancePlanID == nil || workOrder.PlanOccurrence == nil {
+		return nil, nil
+	}
+
+	plan, err := s.repository.GetPlanByID(*workOrder.MaintenancePlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	counter, err := s.readPlanCounter(*plan)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reset usage baseline of plan %d: %w", plan.ID, err)
+	}
+
+	var lastExecuted *time.Time
+	if executed {
+		lastExecuted = &at
+	}
+
+	return &rMaintenance.UsageBaseline{
+		PlanID:       plan.ID,
+		PreviousAt:   workOrder.PlanOccurrence,
+		Baseline:     counter.Value,
+		BaselineAt:   at,
+		LastExecuted: lastExecuted,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package sMaintenance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/utils"
+	"gorm.io/gorm"
+)
+
+func (s *service) GetWorkOrders(req WorkOrderFilterRequest) ([]rModels.MrMaintWorkOrder, error) {
+	filter := rMaintenance.WorkOrderFilter{
+		AssetID:           req.AssetID,
+		MaintenancePlanID: req.MaintenancePlanID,
+		WorkOrderType:     rModels.WorkOrderType(req.WorkOrderType),
+		From:              req.From,
+		To:                req.To,
+	}
+	for _, status := range utils.SplitValues(req.Status) {
+		filter.Status = append(filter.Status, rModels.WorkOrderStatus(status))
+	}
+	for _, priority := range utils.SplitValues(req.Priority) {
+		filter.Priority = append(filter.Priority, rModels.PriorityLevel(priority))
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, fmt.Errorf("%w: 'to' date is before 'from' date", ErrInvalidRequest)
+	}
+
+	return s.repository.GetWorkOrders(filter)
+}
+
+func (s *service) GetWorkOrderByID(id uint) (*rModels.MrMaintWorkOrder, error) {
+	workOrder, err := s.repository.GetWorkOrderByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return workOrder, nil
+}
+
+func (s *service) CreateWorkOrder(req CreateWorkOrderRequest, createdBy uint) (*rModels.MrMaintWorkOrder, error) {
+	workOrder := &rModels.MrMaintWorkOrder{
+		MaintenancePlanID: req.MaintenancePlanID,
+		AssetID:           req.AssetID,
+		WorkOrderType:     rModels.WorkOrderType(req.WorkOrderType),
+		Priority:          rModels.PriorityLevel(req.Priority),
+		Status:            rModels.WorkOrderRequested,
+		Title:             req.Title,
+		Description:       req.Description,
+		ScheduledDate:     req.ScheduledDate,
+		EstimatedHours:    req.EstimatedHours,
+		CreatedBy:         createdBy,
+		AssignedTeamID:    req.AssignedTeamID,
+	}
+
+	history := &rModels.MrMaintWorkOrderStatusHistory{
+		ToStatus:  rModels.WorkOrderRequested,
+		ChangedBy: createdBy,
+		ChangedAt: time.Now(),
+		Reason:    "Work order created",
+	}
+
+	if err := s.repository.CreateWorkOrder(workOrder, history); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetWorkOrderByID(workOrder.ID)
+}
+
+func (s *service) UpdateWorkOrder(id uint, req UpdateWorkOrderRequest) (*rModels.MrMaintWorkOrder, error) {
+	workOrder, err := s.getEditableWorkOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AssetID != 0 {
+		workOrder.AssetID = req.AssetID
+	}
+	if req.WorkOrderType != "" {
+		workOrder.WorkOrderType = rModels.WorkOrderType(req.WorkOrderType)
+	}
+	if req.Priority != "" {
+		workOrder.Priority = rModels.PriorityLevel(req.Priority)
+	}
+	if req.Title != "" {
+		workOrder.Title = req.Title
+	}
+	if req.Description != "" {
+		workOrder.Description = req.Description
+	}
+	if !req.ScheduledDate.IsZero() {
+		workOrder.ScheduledDate = req.ScheduledDate
+	}
+	if req.EstimatedHours != nil {
+		workOrder.EstimatedHours = *req.EstimatedHours
+	}
+	if req.AssignedTeamID != nil {
+		workOrder.AssignedTeamID = req.AssignedTeamID
+	}
+	if req.CompletionNotes != nil {
+		workOrder.CompletionNotes = req.CompletionNotes
+	}
+	if req.QualityCheck != nil {
+		workOrder.QualityCheck = req.QualityCheck
+	}
+
+	if err := s.repository.UpdateWorkOrder(workOrder); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetWorkOrderByID(id)
+}
+
+// getWorkOrder comprueba que la orden existe antes de operar sobre sus detalles
+func (s *service) getWorkOrder(id uint) (*rModels.MrMaintWorkOrder, error) {
+	workOrder, err := s.repository.GetWorkOrderByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return workOrder, nil
+}
+
+// getEditableWorkOrder comprueba además que la orden no esté verificada, cerrada o cancelada
+func (s *service) getEditableWorkOrder(id uint) (*rModels.MrMaintWorkOrder, error) {
+	workOrder, err := s.getWorkOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	if workOrder.Status.IsFinal() {
+		return nil, fmt.Errorf("%w: work order %d is %s and can no longer be modified", ErrInvalidTransition, id, workOrder.Status)
+	}
+	return workOrder, nil
+}
+
+// notFound traduce el error de registro inexistente de GORM al error de dominio
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
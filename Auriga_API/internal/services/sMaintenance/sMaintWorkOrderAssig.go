@@ -0,0 +1,104 @@
+package sMaintenance
+
+import (
+	"fmt"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+func (s *service) GetWorkOrderAssignments(workOrderID uint) ([]rModels.MrMaintWorkOrderAssignment, error) {
+	if _, err := s.getWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+	return s.repository.GetAssignmentsByWorkOrder(workOrderID)
+}
+
+func (s *service) CreateWorkOrderAssignment(workOrderID uint, req CreateWorkOrderAssignmentRequest) (*rModels.MrMaintWorkOrderAssignment, error) {
+	if _, err := s.getEditableWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+
+	// Un empleado solo puede estar asignado una vez a la misma orden
+	assignments, err := s.repository.GetAssignmentsByWorkOrder(workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assignments {
+		if a.EmployeeID == req.EmployeeID {
+			return nil, fmt.Errorf("%w: employee %d is already assigned to work order %d", ErrInvalidRequest, req.EmployeeID, workOrderID)
+		}
+	}
+
+	assignment := &rModels.MrMaintWorkOrderAssignment{
+		WorkOrderID: workOrderID,
+		EmployeeID:  req.EmployeeID,
+		Role:        req.Role,
+	}
+
+	if err := s.repository.CreateAssignment(assignment); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetAssignmentByID(assignment.ID)
+}
+
+func (s *service) UpdateWorkOrderAssignment(workOrderID uint, assignmentID uint, req UpdateWorkOrderAssignmentRequest) (*rModels.MrMaintWorkOrderAssignment, error) {
+	assignment, err := s.getAssignment(workOrderID, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Role != "" {
+		assignment.Role = req.Role
+	}
+	if req.StartTime != nil {
+		assignment.StartTime = req.StartTime
+	}
+	if req.EndTime != nil {
+		assignment.EndTime = req.EndTime
+	}
+	if assignment.StartTime != nil && assignment.EndTime != nil && assignment.EndTime.Before(*assignment.StartTime) {
+		return nil, fmt.Errorf("%w: end_time is before start_time", ErrInvalidRequest)
+	}
+	if req.HoursWorked != nil {
+		// Con fichajes, las horas son la suma de los tramos y se recalculan al cerrar cada uno
+		count, err := s.repository.CountTimeSegments(assignment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("%w: hours are tracked by time segments for assignment %d", ErrInvalidRequest, assignment.ID)
+		}
+		assignment.HoursWorked = *req.HoursWorked
+	}
+
+	if err := s.repository.UpdateAssignment(assignment); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetAssignmentByID(assignmentID)
+}
+
+func (s *service) DeleteWorkOrderAssignment(workOrderID uint, assignmentID uint) error {
+	assignment, err := s.getAssignment(workOrderID, assignmentID)
+	if err != nil {
+		return err
+	}
+	return s.repository.DeleteAssignment(assignment)
+}
+
+// getAssignment devuelve la asignación solo si pertenece a la orden indicada y esta sigue abierta
+func (s *service) getAssignment(workOrderID uint, assignmentID uint) (*rModels.MrMaintWorkOrderAssignment, error) {
+	if _, err := s.getEditableWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.repository.GetAssignmentByID(assignmentID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if assignment.WorkOrderID != workOrderID {
+		return nil, ErrNotFound
+	}
+	return assignment, nil
+}
@@ -0,0 +1,117 @@
+package sMaintenance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+func (s *service) GetWorkOrderSpareParts(workOrderID uint) ([]rModels.MrMaintWorkOrderSparePart, error) {
+	if _, err := s.getWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+	return s.repository.GetSparePartsByWorkOrder(workOrderID)
+}
+
+func (s *service) CreateWorkOrderSparePart(workOrderID uint, req CreateWorkOrderSparePartRequest) (*rModels.MrMaintWorkOrderSparePart, error) {
+	if _, err := s.getEditableWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+
+	// Línea manual sin stock; el material de almacén solo llega a la OT con un movimiento de salida
+	sparePart := &rModels.MrMaintWorkOrderSparePart{
+		WorkOrderID:      workOrderID,
+		ProductID:        req.ProductID,
+		SparePartAssetID: req.SparePartAssetID,
+		Quantity:         req.Quantity,
+		UnitCost:         req.UnitCost,
+		TotalCost:        req.Quantity * req.UnitCost,
+	}
+
+	if err := s.repository.CreateSparePart(sparePart); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetSparePartByID(sparePart.ID)
+}
+
+func (s *service) UpdateWorkOrderSparePart(workOrderID uint, sparePartID uint, req UpdateWorkOrderSparePartRequest) (*rModels.MrMaintWorkOrderSparePart, error) {
+	sparePart, err := s.getSparePart(workOrderID, sparePartID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkManualSparePart(sparePart); err != nil {
+		return nil, err
+	}
+	if sparePart.StockID != nil {
+		if err := s.checkSparePartStock(*sparePart.StockID, sparePart.ProductID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Quantity != nil {
+		sparePart.Quantity = *req.Quantity
+	}
+	if req.UnitCost != nil {
+		sparePart.UnitCost = *req.UnitCost
+	}
+	sparePart.TotalCost = sparePart.Quantity * sparePart.UnitCost
+
+	if err := s.repository.UpdateSparePart(sparePart); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetSparePartByID(sparePartID)
+}
+
+func (s *service) DeleteWorkOrderSparePart(workOrderID uint, sparePartID uint) error {
+	sparePart, err := s.getSparePart(workOrderID, sparePartID)
+	if err != nil {
+		return err
+	}
+	if err := checkManualSparePart(sparePart); err != nil {
+		return err
+	}
+	return s.repository.DeleteSparePart(sparePart)
+}
+
+// getSparePart devuelve el repuesto solo si pertenece a la orden indicada y esta sigue abierta
+func (s *service) getSparePart(workOrderID uint, sparePartID uint) (*rModels.MrMaintWorkOrderSparePart, error) {
+	if _, err := s.getEditableWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+
+	sparePart, err := s.repository.GetSparePartByID(sparePartID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if sparePart.WorkOrderID != workOrderID {
+		return nil, ErrNotFound
+	}
+	return sparePart, nil
+}
+
+// checkManualSparePart impide editar las líneas generadas por movimientos de almacén; se corrigen
+// con una devolución para que stock y OT sigan cuadrando
+func checkManualSparePart(sparePart *rModels.MrMaintWorkOrderSparePart) error {
+	if sparePart.AssetMovementID != nil {
+		return fmt.Errorf("%w: spare part %d comes from stock movement %d, use /inventory/movements/return", ErrInvalidRequest, sparePart.ID, *sparePart.AssetMovementID)
+	}
+	return nil
+}
+
+// checkSparePartStock impide imputar a la OT material de otro producto o de un lote caducado
+func (s *service) checkSparePartStock(stockID uint, productID uint) error {
+	stock, err := s.repository.GetSparePartStock(stockID)
+	if err != nil {
+		return notFound(err)
+	}
+	if stock.ProductID != productID {
+		return fmt.Errorf("%w: stock %d does not hold product %d", ErrInvalidRequest, stockID, productID)
+	}
+	if stock.ExpiryDate != nil && !stock.ExpiryDate.After(time.Now()) {
+		return fmt.Errorf("%w: batch %s expired on %s", ErrInvalidRequest, stock.BatchNumber, stock.ExpiryDate.Format("2006-01-02"))
+	}
+	return nil
+}
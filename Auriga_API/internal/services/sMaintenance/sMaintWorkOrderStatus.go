@@ -0,0 +1,166 @@
+package sMaintenance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+// workOrderTransitions define los cambios de estado permitidos para una orden de trabajo
+var workOrderTransitions = map[rModels.WorkOrderStatus][]rModels.WorkOrderStatus{
+	rModels.WorkOrderRequested:  {rModels.WorkOrderPlanned, rModels.WorkOrderCancelled},
+	rModels.WorkOrderPlanned:    {rModels.WorkOrderRequested, rModels.WorkOrderInProgress, rModels.WorkOrderCancelled},
+	rModels.WorkOrderInProgress: {rModels.WorkOrderOnHold, rModels.WorkOrderCompleted, rModels.WorkOrderCancelled},
+	rModels.WorkOrderOnHold:     {rModels.WorkOrderInProgress, rModels.WorkOrderCancelled},
+	rModels.WorkOrderCompleted:  {rModels.WorkOrderInProgress, rModels.WorkOrderVerified, rModels.WorkOrderClosed},
+	rModels.WorkOrderVerified:   {rModels.WorkOrderClosed},
+	rModels.WorkOrderClosed:     {},
+	rModels.WorkOrderCancelled:  {},
+}
+
+// taskTransitions define los cambios de estado permitidos para una tarea
+var taskTransitions = map[rModels.WorkOrderTaskStatus][]rModels.WorkOrderTaskStatus{
+	rModels.TaskPending:    {rModels.TaskInProgress, rModels.TaskCompleted},
+	rModels.TaskInProgress: {rModels.TaskPending, rModels.TaskCompleted},
+	rModels.TaskCompleted:  {rModels.TaskInProgress},
+}
+
+func canTransition(from, to rModels.WorkOrderStatus) bool {
+	for _, allowed := range workOrderTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func canTransitionTask(from, to rModels.WorkOrderTaskStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range taskTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) ChangeWorkOrderStatus(id uint, req ChangeWorkOrderStatusRequest, changedBy uint) (*rModels.MrMaintWorkOrder, error) {
+	workOrder, err := s.getWorkOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := workOrder.Status
+	to := rModels.WorkOrderStatus(req.Status)
+
+	if !canTransition(from, to) {
+		return nil, fmt.Errorf("%w: cannot change work order from %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if (to == rModels.WorkOrderOnHold || to == rModels.WorkOrderCancelled) && reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required to change the work order to %s", ErrInvalidRequest, to)
+	}
+
+	if req.CompletionNotes != nil {
+		workOrder.CompletionNotes = req.CompletionNotes
+	}
+	if req.QualityCheck != nil {
+		workOrder.QualityCheck = req.QualityCheck
+	}
+
+	if err := s.checkTransitionRequirements(workOrder, to); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch to {
+	case rModels.WorkOrderInProgress:
+		// La fecha de inicio se conserva tras pausas o reaperturas
+		if workOrder.StartDate == nil {
+			workOrder.StartDate = &now
+		}
+		workOrder.EndDate = nil
+	case rModels.WorkOrderCompleted, rModels.WorkOrderCancelled:
+		workOrder.EndDate = &now
+	}
+	workOrder.Status = to
+
+	history := &rModels.MrMaintWorkOrderStatusHistory{
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedBy:  changedBy,
+		ChangedAt:  now,
+		Reason:     reason,
+	}
+
+	var effects rMaintenance.TransitionEffects
+	switch to {
+	case rModels.WorkOrderOnHold:
+		if err := s.closeOpenSegments(&effects, workOrder.ID, false, now); err != nil {
+			return nil, err
+		}
+	case rModels.WorkOrderCompleted:
+		if err := s.closeOpenSegments(&effects, workOrder.ID, true, now); err != nil {
+			return nil, err
+		}
+		if err := s.advancePlan(&effects, workOrder, now, true); err != nil {
+			return nil, err
+		}
+	case rModels.WorkOrderCancelled:
+		if err := s.closeOpenSegments(&effects, workOrder.ID, true, now); err != nil {
+			return nil, err
+		}
+		if err := s.advancePlan(&effects, workOrder, now, false); err != nil {
+			return nil, err
+		}
+		effects.ReleaseReservations = true
+	case rModels.WorkOrderClosed:
+		effects.ReleaseReservations = true
+	}
+
+	if err := s.repository.TransitionWorkOrder(workOrder, from, history, effects); err != nil {
+		if errors.Is(err, rMaintenance.ErrStatusChanged) {
+			return nil, fmt.Errorf("%w: work order %d was modified by another user, reload and retry", ErrInvalidTransition, id)
+		}
+		return nil, err
+	}
+
+	return s.repository.GetWorkOrderByID(id)
+}
+
+// checkTransitionRequirements valida las condiciones de negocio para completar o cerrar la orden
+func (s *service) checkTransitionRequirements(workOrder *rModels.MrMaintWorkOrder, to rModels.WorkOrderStatus) error {
+	switch to {
+	case rModels.WorkOrderCompleted, rModels.WorkOrderVerified, rModels.WorkOrderClosed:
+		for _, task := range workOrder.Tasks {
+			if task.Status != rModels.TaskCompleted {
+				return fmt.Errorf("%w: task %d is still %s", ErrInvalidTransition, task.TaskNumber, task.Status)
+			}
+		}
+	}
+
+	if to == rModels.WorkOrderVerified || to == rModels.WorkOrderClosed {
+		if workOrder.CompletionNotes == nil || strings.TrimSpace(*workOrder.CompletionNotes) == "" {
+			return fmt.Errorf("%w: completion notes are required before closing", ErrInvalidTransition)
+		}
+		if workOrder.QualityCheck == nil || !*workOrder.QualityCheck {
+			return fmt.Errorf("%w: quality check must be passed before closing", ErrInvalidTransition)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetWorkOrderHistory(id uint) ([]rModels.MrMaintWorkOrderStatusHistory, error) {
+	if _, err := s.getWorkOrder(id); err != nil {
+		return nil, err
+	}
+	return s.repository.GetStatusHistory(id)
+}
@@ -0,0 +1,109 @@
+package sMaintenance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+)
+
+func (s *service) GetWorkOrderTasks(workOrderID uint) ([]rModels.MrMaintWorkOrderTask, error) {
+	if _, err := s.getWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+	return s.repository.GetTasksByWorkOrder(workOrderID)
+}
+
+func (s *service) CreateWorkOrderTask(workOrderID uint, req CreateWorkOrderTaskRequest) (*rModels.MrMaintWorkOrderTask, error) {
+	if _, err := s.getEditableWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+
+	// Si no se indica número, la tarea se añade al final de la lista
+	taskNumber := req.TaskNumber
+	if taskNumber == 0 {
+		tasks, err := s.repository.GetTasksByWorkOrder(workOrderID)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			if task.TaskNumber > taskNumber {
+				taskNumber = task.TaskNumber
+			}
+		}
+		taskNumber++
+	}
+
+	task := &rModels.MrMaintWorkOrderTask{
+		WorkOrderID: workOrderID,
+		TaskNumber:  taskNumber,
+		Description: req.Description,
+		Status:      rModels.TaskPending,
+		AssignedTo:  req.AssignedTo,
+	}
+
+	if err := s.repository.CreateTask(task); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetTaskByID(task.ID)
+}
+
+func (s *service) UpdateWorkOrderTask(workOrderID uint, taskID uint, req UpdateWorkOrderTaskRequest) (*rModels.MrMaintWorkOrderTask, error) {
+	task, err := s.getTask(workOrderID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TaskNumber != 0 {
+		task.TaskNumber = req.TaskNumber
+	}
+	if req.Description != "" {
+		task.Description = req.Description
+	}
+	if req.AssignedTo != nil {
+		task.AssignedTo = req.AssignedTo
+	}
+	if req.Status != "" {
+		status := rModels.WorkOrderTaskStatus(req.Status)
+		if !canTransitionTask(task.Status, status) {
+			return nil, fmt.Errorf("%w: cannot change task from %s to %s", ErrInvalidTransition, task.Status, status)
+		}
+		if status == rModels.TaskCompleted && task.Status != rModels.TaskCompleted {
+			now := time.Now()
+			task.CompletedAt = &now
+		} else if status != rModels.TaskCompleted {
+			task.CompletedAt = nil
+		}
+		task.Status = status
+	}
+
+	if err := s.repository.UpdateTask(task); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetTaskByID(taskID)
+}
+
+func (s *service) DeleteWorkOrderTask(workOrderID uint, taskID uint) error {
+	if _, err := s.getTask(workOrderID, taskID); err != nil {
+		return err
+	}
+	return s.repository.DeleteTask(taskID)
+}
+
+// getTask devuelve la tarea solo si pertenece a la orden indicada y esta sigue abierta
+func (s *service) getTask(workOrderID uint, taskID uint) (*rModels.MrMaintWorkOrderTask, error) {
+	if _, err := s.getEditableWorkOrder(workOrderID); err != nil {
+		return nil, err
+	}
+
+	task, err := s.repository.GetTaskByID(taskID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if task.WorkOrderID != workOrderID {
+		return nil, ErrNotFound
+	}
+	return task, nil
+}
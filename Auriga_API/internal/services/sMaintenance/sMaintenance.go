@@ -0,0 +1,231 @@
+package sMaintenance
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/remrafvil/Auriga_API/config"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rLabor"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rMaintenance"
+	"github.com/remrafvil/Auriga_API/internal/repositories/rModels"
+	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	GetWorkOrders(req WorkOrderFilterRequest) ([]rModels.MrMaintWorkOrder, error)
+	GetWorkOrderByID(id uint) (*rModels.MrMaintWorkOrder, error)
+	CreateWorkOrder(req CreateWorkOrderRequest, createdBy uint) (*rModels.MrMaintWorkOrder, error)
+	UpdateWorkOrder(id uint, req UpdateWorkOrderRequest) (*rModels.MrMaintWorkOrder, error)
+	ChangeWorkOrderStatus(id uint, req ChangeWorkOrderStatusRequest, changedBy uint) (*rModels.MrMaintWorkOrder, error)
+	GetWorkOrderHistory(id uint) ([]rModels.MrMaintWorkOrderStatusHistory, error)
+
+	GetWorkOrderTasks(workOrderID uint) ([]rModels.MrMaintWorkOrderTask, error)
+	CreateWorkOrderTask(workOrderID uint, req CreateWorkOrderTaskRequest) (*rModels.MrMaintWorkOrderTask, error)
+	UpdateWorkOrderTask(workOrderID uint, taskID uint, req UpdateWorkOrderTaskRequest) (*rModels.MrMaintWorkOrderTask, error)
+	DeleteWorkOrderTask(workOrderID uint, taskID uint) error
+
+	GetWorkOrderAssignments(workOrderID uint) ([]rModels.MrMaintWorkOrderAssignment, error)
+	CreateWorkOrderAssignment(workOrderID uint, req CreateWorkOrderAssignmentRequest) (*rModels.MrMaintWorkOrderAssignment, error)
+	UpdateWorkOrderAssignment(workOrderID uint, assignmentID uint, req UpdateWorkOrderAssignmentRequest) (*rModels.MrMaintWorkOrderAssignment, error)
+	DeleteWorkOrderAssignment(workOrderID uint, assignmentID uint) error
+
+	GetWorkOrderTime(workOrderID uint) ([]rModels.MrMaintTimeSegment, error)
+	StartWork(workOrderID uint, employeeID uint) (*rModels.MrMaintTimeSegment, error)
+	PauseWork(workOrderID uint, employeeID uint) (*rModels.MrMaintTimeSegment, error)
+	ResumeWork(workOrderID uint, employeeID uint) (*rModels.MrMaintTimeSegment, error)
+	StopWork(workOrderID uint, employeeID uint) (*rModels.MrMaintWorkOrderAssignment, error)
+
+	GetWorkOrderSpareParts(workOrderID uint) ([]rModels.MrMaintWorkOrderSparePart, error)
+	CreateWorkOrderSparePart(workOrderID uint, req CreateWorkOrderSparePartRequest) (*rModels.MrMaintWorkOrderSparePart, error)
+	UpdateWorkOrderSparePart(workOrderID uint, sparePartID uint, req UpdateWorkOrderSparePartRequest) (*rModels.MrMaintWorkOrderSparePart, error)
+	DeleteWorkOrderSparePart(workOrderID uint, sparePartID uint) error
+
+	GetWorkOrderPack(id uint) (*WorkOrderPack, error)
+
+	GetPlansUsage() ([]PlanUsage, error)
+	GetPlanUsage(id uint) (*PlanUsage, error)
+
+	GetAssetKpis(assetID uint, req AssetKpiRequest) (*AssetKpiReport, error)
+	GetAssetKpiRanking(assetID uint, req AssetKpiRankingRequest) (*AssetKpiRanking, error)
+	GetCostReport(assetID uint, req CostReportRequest) (*CostReport, error)
+
+	GetLaborRates() ([]rModels.MrMaintLaborRate, error)
+	CreateLaborRate(req LaborRateRequest) (*rModels.MrMaintLaborRate, error)
+	UpdateLaborRate(id uint, req LaborRateRequest) (*rModels.MrMaintLaborRate, error)
+	DeleteLaborRate(id uint) error
+
+	GetEventRules() ([]rModels.MrMaintEventRule, error)
+	CreateEventRule(req EventRuleRequest) (*rModels.MrMaintEventRule, error)
+	UpdateEventRule(id uint, req EventRuleRequest) (*rModels.MrMaintEventRule, error)
+	DeleteEventRule(id uint) error
+	ProcessStopEvent(eventID uint) (*rModels.MrMaintWorkOrder, error)
+
+	StartPreventiveScheduler(ctx context.Context)
+	StartConditionMonitor(ctx context.Context)
+	StartStopEventProcessor(ctx context.Context)
+}
+
+// Errores de dominio que los handlers traducen a códigos HTTP
+var (
+	ErrNotFound          = errors.New("record not found")
+	ErrInvalidRequest    = errors.New("invalid request")
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+// service implementación
+type service struct {
+	repository       rMaintenance.Repository
+	repositoryInflux riInfluxdb.Repository
+	repositoryLabor  rLabor.Repository
+	config           *config.Settings
+	logger           *zap.Logger
+}
+
+func New(repository rMaintenance.Repository, repositoryInflux riInfluxdb.Repository, repositoryLabor rLabor.Repository, config *config.Settings, logger *zap.Logger) Service {
+	return &service{
+		repository:       repository,
+		repositoryInflux: repositoryInflux,
+		repositoryLabor:  repositoryLabor,
+		config:           config,
+		logger:           logger,
+	}
+}
+
+// WORK ORDER DTOs
+type WorkOrderFilterRequest struct {
+	AssetID           uint      `query:"asset_id"`
+	MaintenancePlanID uint      `query:"maintenance_plan_id"`
+	Status            []string  `query:"status"`
+	Priority          []string  `query:"priority"`
+	WorkOrderType     string    `query:"work_order_type"`
+	From              time.Time `query:"from"`
+	To                time.Time `query:"to"`
+}
+
+type CreateWorkOrderRequest struct {
+	MaintenancePlanID *uint     `json:"maintenance_plan_id"`
+	AssetID           uint      `json:"asset_id" validate:"required,min=1"`
+	WorkOrderType     string    `json:"work_order_type" validate:"required,oneof=preventive corrective predictive inspection"`
+	Priority          string    `json:"priority" validate:"required,oneof=low medium high critical"`
+	Title             string    `json:"title" validate:"required,min=1,max=255"`
+	Description       string    `json:"description"`
+	ScheduledDate     time.Time `json:"scheduled_date" validate:"required,notzerotime"`
+	EstimatedHours    float64   `json:"estimated_hours" validate:"min=0"`
+	AssignedTeamID    *uint     `json:"assigned_team_id"`
+}
+
+type UpdateWorkOrderRequest struct {
+	AssetID         uint      `json:"asset_id" validate:"omitempty,min=1"`
+	WorkOrderType   string    `json:"work_order_type" validate:"omitempty,oneof=preventive corrective predictive inspection"`
+	Priority        string    `json:"priority" validate:"omitempty,oneof=low medium high critical"`
+	Title           string    `json:"title" validate:"omitempty,min=1,max=255"`
+	Description     string    `json:"description"`
+	ScheduledDate   time.Time `json:"scheduled_date"`
+	EstimatedHours  *float64  `json:"estimated_hours" validate:"omitempty,min=0"`
+	AssignedTeamID  *uint     `json:"assigned_team_id"`
+	CompletionNotes *string   `json:"completion_notes"`
+	QualityCheck    *bool     `json:"quality_check"`
+}
+
+// El estado solo cambia mediante transiciones validadas por la máquina de estados
+type ChangeWorkOrderStatusRequest struct {
+	Status          string  `json:"status" validate:"required,oneof=requested planned in_progress on_hold completed verified closed cancelled"`
+	Reason          string  `json:"reason"`
+	CompletionNotes *string `json:"completion_notes"`
+	QualityCheck    *bool   `json:"quality_check"`
+}
+
+// TASK DTOs
+type CreateWorkOrderTaskRequest struct {
+	TaskNumber  int    `json:"task_number" validate:"omitempty,min=1"`
+	Description string `json:"description" validate:"required,min=1"`
+	AssignedTo  *uint  `json:"assigned_to"`
+}
+
+type UpdateWorkOrderTaskRequest struct {
+	TaskNumber  int    `json:"task_number" validate:"omitempty,min=1"`
+	Description string `json:"description"`
+	Status      string `json:"status" validate:"omitempty,oneof=pending in_progress completed"`
+	AssignedTo  *uint  `json:"assigned_to"`
+}
+
+// ASSIGNMENT DTOs
+type CreateWorkOrderAssignmentRequest struct {
+	EmployeeID uint   `json:"employee_id" validate:"required,min=1"`
+	Role       string `json:"role" validate:"required,min=1,max=100"`
+}
+
+type UpdateWorkOrderAssignmentRequest struct {
+	Role        string     `json:"role" validate:"omitempty,min=1,max=100"`
+	StartTime   *time.Time `json:"start_time"`
+	EndTime     *time.Time `json:"end_time"`
+	HoursWorked *float64   `json:"hours_worked" validate:"omitempty,min=0"`
+}
+
+// TIME TRACKING DTOs
+// Sin employee_id se ficha al empleado autenticado; indicar otro empleado requiere un rol de supervisor
+type TimeTrackingRequest struct {
+	EmployeeID *uint `json:"employee_id" validate:"omitempty,min=1"`
+}
+
+type WorkOrderPackRequest struct {
+	Format string `query:"format" validate:"omitempty,oneof=pdf html"` // pdf por defecto
+}
+
+// SPARE PART DTOs
+// CreateWorkOrderSparePartRequest línea manual; el material de almacén se entrega con /inventory/movements/issue
+type CreateWorkOrderSparePartRequest struct {
+	ProductID        uint    `json:"product_id" validate:"required,min=1"`
+	SparePartAssetID *uint   `json:"spare_part_asset_id"`
+	Quantity         float64 `json:"quantity" validate:"required,gt=0"`
+	UnitCost         float64 `json:"unit_cost" validate:"min=0"`
+}
+
+type UpdateWorkOrderSparePartRequest struct {
+	Quantity *float64 `json:"quantity" validate:"omitempty,gt=0"`
+	UnitCost *float64 `json:"unit_cost" validate:"omitempty,min=0"`
+}
+
+// KPI DTOs
+type AssetKpiRequest struct {
+	From  time.Time `query:"from"` // Por defecto, los últimos 30 días
+	To    time.Time `query:"to"`
+	Depth int       `query:"depth" validate:"min=0,max=10"` // Niveles de descendientes a desglosar
+}
+
+type AssetKpiRankingRequest struct {
+	From  time.Time `query:"from"`
+	To    time.Time `query:"to"`
+	By    string    `query:"by" validate:"omitempty,oneof=downtime failures mttr mtbf availability"`
+	Level int       `query:"level" validate:"min=0"` // Longitud de hierarchical_level de los activos a comparar
+	Limit int       `query:"limit" validate:"min=0,max=1000"`
+}
+
+// COST DTOs
+type CostReportRequest struct {
+	From   time.Time `query:"from"` // Por defecto, los últimos 12 meses
+	To     time.Time `query:"to"`
+	Depth  int       `query:"depth" validate:"min=0,max=10"` // Niveles de descendientes a desglosar
+	Format string    `query:"format" validate:"omitempty,oneof=json csv"`
+}
+
+// Una tarifa es de empleado (employee_id) o de rol (role)
+type LaborRateRequest struct {
+	EmployeeID *uint   `json:"employee_id"`
+	Role       string  `json:"role" validate:"required_without=EmployeeID,max=100"`
+	HourlyRate float64 `json:"hourly_rate" validate:"min=0"`
+}
+
+// EVENT RULE DTOs
+// Sin event_type_id la regla aplica a todos los tipos de la categoría
+type EventRuleRequest struct {
+	Name            string `json:"name" validate:"required,min=1,max=255"`
+	EventCategoryID uint   `json:"event_category_id" validate:"required,min=1"`
+	EventTypeID     *uint  `json:"event_type_id" validate:"omitempty,min=1"`
+	Priority        string `json:"priority" validate:"required,oneof=low medium high critical"`
+	Title           string `json:"title" validate:"max=255"`
+	AssignedTeamID  *uint  `json:"assigned_team_id"`
+	Active          *bool  `json:"active"`
+}
@@ -1,13 +1,13 @@
 package sSap
 
 import (
-	"bytes"
-	"encoding/xml"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"time"
+
+	"github.com/remrafvil/Auriga_API/internal/repositories/rsSap"
 )
 
 type SAPSendResult struct {
@@ -18,23 +18,20 @@ type SAPSendResult struct {
 	StatusCode       int    `json:"statusCode,omitempty"`
 }
 
-type SAPConsumptionPayload struct {
-	XMLName          xml.Name `xml:"ns1:ZPP_IOT_CONSUMOS_ORDEN"`
-	Xmlns            string   `xml:"xmlns:ns1,attr"`
-	IV_WERKS         string   `xml:"IV_WERKS"`
-	IV_AUFNR         string   `xml:"IV_AUFNR"`
-	IV_ARBPL         string   `xml:"IV_ARBPL"`
-	IV_EXIDV         string   `xml:"IV_EXIDV"`
-	IV_ID_SILO       string   `xml:"IV_ID_SILO"`
-	IV_OPERARIO      string   `xml:"IV_OPERARIO"`
-	IV_TURNO         string   `xml:"IV_TURNO"`
-	IV_MATNR         string   `xml:"IV_MATNR"`
-	IV_TIMESTAMP_INI string   `xml:"IV_TIMESTAMP_INI"`
-	IV_TIMESTAMP_FIN string   `xml:"IV_TIMESTAMP_FIN"`
-}
-
-func (s *service) DosingConsumptionSendToSAP(factory string, prodline string, sapOrderCode string, startDate *time.Time, endDate *time.Time, workdayID string, turno string) ([]SAPSendResult, error) {
+// DosingConsumptionSendToSAP envía los consumos de la orden firmados por el usuario autenticado: su WorkdayID
+// va en IV_OPERARIO y su turno asignado en IV_TURNO. El turno indicado solo se usa si no tiene asignación.
+func (s *service) DosingConsumptionSendToSAP(ctx context.Context, factory string, prodline string, sapOrderCode string, startDate *time.Time, endDate *time.Time, turno string) ([]SAPSendResult, error) {
 	log.Println("🚀 ===== INICIANDO DosingConsumptionSendToSAP =====")
+
+	operator, err := s.serviceLabor.ResolveOperator(ctx, time.Now())
+	if err != nil {
+		log.Printf("❌ Operario no identificado Service DosingConsumptionSendToSAP: %v", err)
+		return nil, err
+	}
+	workdayID := operator.WorkdayID
+	if operator.Shift != "" {
+		turno = operator.Shift
+	}
 	log.Printf("📋 Parámetros: Factory=%s, ProdLine=%s, OrderCode=%s, WorkdayID=%s, Turno=%s", factory, prodline, sapOrderCode, workdayID, turno)
 
 	// Obtener consumos calculados
@@ -89,13 +86,6 @@ func (s *service) DosingConsumptionSendToSAP(factory string, prodline string, sa
 		lineAsset.SapCode = prodline
 	}
 
-	// URL de la API de SAP
-	sapURL := "https://l20163-iflmap.hcisbp.eu1.hana.ondemand.com/http/SAP_RFC_IOT_DHM"
-
-	// Credenciales Basic Auth
-	username := "s020695322"
-	password := "1Lanter[]"
-
 	// Enviar cada consumo a SAP
 	log.Printf("📤 Iniciando envío de %d consumos a SAP...", len(consumptions))
 	for i, consumption := range consumptions {
@@ -108,8 +98,7 @@ func (s *service) DosingConsumptionSendToSAP(factory string, prodline string, sa
 		}
 
 		// Construir el payload XML
-		payload := SAPConsumptionPayload{
-			Xmlns:            "urn:sap-com:document:sap:rfc:functions",
+		payload := rsSap.ZPP_IOT_CONSUMOS_ORDEN{
 			IV_WERKS:         factoryAsset.SapCode,
 			IV_AUFNR:         sapOrderCode,
 			IV_ARBPL:         lineAsset.SapCode,
@@ -122,68 +111,29 @@ func (s *service) DosingConsumptionSendToSAP(factory string, prodline string, sa
 			IV_TIMESTAMP_FIN: finishedAt.Format("2006-01-02T15:04:05.000Z"),
 		}
 
-		// Generar XML
-		log.Printf("🔧 Generando XML para consumo %s...", consumption.MrComponentSapCode)
-		xmlData, err := xml.MarshalIndent(payload, "", "    ")
-		if err != nil {
-			log.Printf("❌ Error generando XML para consumo %s: %v", consumption.MrComponentSapCode, err)
-			result.ErrorMessage = fmt.Sprintf("Error generando XML: %v", err)
-			results = append(results, result)
-			continue
-		}
-		log.Printf("✅ XML generado correctamente para consumo %s", consumption.MrComponentSapCode)
-
-		// Agregar header XML
-		xmlString := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + string(xmlData)
-
 		log.Printf("📤 [%d/%d] Enviando a SAP: Orden=%s, Componente=%s, Dosificador=%s", i+1, len(consumptions), sapOrderCode, consumption.MrComponentSapCode, consumption.DosingUnit)
-		log.Printf("📄 XML generado (primeros 200 chars): %s...", xmlString[:min(200, len(xmlString))])
-
-		// Crear request
-		log.Printf("🔧 Creando HTTP request para consumo %s...", consumption.MrComponentSapCode)
-		req, err := http.NewRequest("POST", sapURL, bytes.NewBufferString(xmlString))
-		if err != nil {
-			log.Printf("❌ Error creando request para consumo %s: %v", consumption.MrComponentSapCode, err)
-			result.ErrorMessage = fmt.Sprintf("Error creando request: %v", err)
-			results = append(results, result)
-			continue
-		}
-
-		// Headers
-		req.Header.Set("Content-Type", "application/xml")
-		req.SetBasicAuth(username, password)
-		log.Printf("🔐 Autenticación Basic Auth configurada para consumo %s", consumption.MrComponentSapCode)
-
-		// Enviar request
-		log.Printf("📡 Enviando POST request a SAP para consumo %s...", consumption.MrComponentSapCode)
-		client := &http.Client{
-			Timeout: 30 * time.Second,
-		}
-
-		resp, err := client.Do(req)
-		if err != nil {
-			log.Printf("❌ Error de conexión enviando a SAP para consumo %s: %v", consumption.MrComponentSapCode, err)
-			result.ErrorMessage = fmt.Sprintf("Error de conexión: %v", err)
-			results = append(results, result)
-			continue
-		}
-		defer resp.Body.Close()
-
+		resp, err := s.repositorySap.RsOrderConsumption(ctx, payload)
 		result.StatusCode = resp.StatusCode
-		log.Printf("📥 Respuesta recibida de SAP para consumo %s: Status=%d", consumption.MrComponentSapCode, resp.StatusCode)
 
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		var faultErr *rsSap.FaultError
+		var statusErr *rsSap.StatusError
+		switch {
+		case err == nil:
 			log.Printf("✅ [%d/%d] Consumo %s enviado exitosamente a SAP (Status: %d)", i+1, len(consumptions), consumption.MrComponentSapCode, resp.StatusCode)
 			result.Success = true
-		} else {
-			// Leer el cuerpo de la respuesta para obtener detalles del error
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			errorBody := string(bodyBytes)
+		case errors.As(err, &faultErr):
+			log.Printf("❌ [%d/%d] SAP rechazó el consumo %s: %v", i+1, len(consumptions), consumption.MrComponentSapCode, err)
+			result.ErrorMessage = fmt.Sprintf("Error SAP %s: %s", faultErr.Code, faultErr.Message)
+		case errors.As(err, &statusErr):
+			errorBody := statusErr.Body
 			if errorBody == "" {
 				errorBody = "Sin detalles del error"
 			}
-			log.Printf("❌ [%d/%d] Error enviando consumo %s a SAP (Status: %d): %s", i+1, len(consumptions), consumption.MrComponentSapCode, resp.StatusCode, errorBody)
-			result.ErrorMessage = fmt.Sprintf("Error HTTP %d: %s", resp.StatusCode, errorBody)
+			log.Printf("❌ [%d/%d] Error enviando consumo %s a SAP (Status: %d): %s", i+1, len(consumptions), consumption.MrComponentSapCode, statusErr.StatusCode, errorBody)
+			result.ErrorMessage = fmt.Sprintf("Error HTTP %d: %s", statusErr.StatusCode, errorBody)
+		default:
+			log.Printf("❌ Error de conexión enviando a SAP para consumo %s: %v", consumption.MrComponentSapCode, err)
+			result.ErrorMessage = fmt.Sprintf("Error de conexión: %v", err)
 		}
 
 		results = append(results, result)
@@ -219,11 +169,3 @@ func (s *service) DosingConsumptionSendToSAP(factory string, prodline string, sa
 	log.Println("🚀 ===== FIN OrderConsumptionSummaryToSAP =====")
 	return results, nil
 }
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
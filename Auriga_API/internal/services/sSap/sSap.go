@@ -1,12 +1,14 @@
 package sSap
 
 import (
+	"context"
 	"time"
 
 	"github.com/remrafvil/Auriga_API/internal/repositories/rAssets"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rLineOrders"
 	"github.com/remrafvil/Auriga_API/internal/repositories/riInfluxdb"
 	"github.com/remrafvil/Auriga_API/internal/repositories/rsSap"
+	"github.com/remrafvil/Auriga_API/internal/services/sLabor"
 )
 
 type Service interface {
@@ -21,7 +23,7 @@ type Service interface {
 	DosingConsumptionDel(factory string, prodline string, dosingSystem string, dosingUnit string, dosingComponent string, sapOrderCode string, sapComponentCode string) ([]msDosingComponent, error)
 	DosingConsumptionUpdate(factory string, prodline string, dosingSystem string, dosingUnit string, dosingComponent string, sapOrderCode string, sapComponentCode string) ([]msDosingComponent, error)
 	DosingConsumptionCalculate(factory string, prodline string, sapOrderCode string, startDate *time.Time, endDate *time.Time) ([]msDosingComponent, error)
-	DosingConsumptionSendToSAP(factory string, prodline string, sapOrderCode string, startDate *time.Time, endDate *time.Time, workdayID string, turno string) ([]SAPSendResult, error)
+	DosingConsumptionSendToSAP(ctx context.Context, factory string, prodline string, sapOrderCode string, startDate *time.Time, endDate *time.Time, turno string) ([]SAPSendResult, error)
 }
 
 type service struct {
@@ -29,14 +31,16 @@ type service struct {
 	repositoryOrd    rLineOrders.Repository
 	repositorySap    rsSap.Repository
 	repositoryInflux riInfluxdb.Repository
+	serviceLabor     sLabor.Service
 }
 
-func New(repositoryAss rAssets.Repository, repositoryOrd rLineOrders.Repository, repositorySap rsSap.Repository, repositoryInflux riInfluxdb.Repository) Service {
+func New(repositoryAss rAssets.Repository, repositoryOrd rLineOrders.Repository, repositorySap rsSap.Repository, repositoryInflux riInfluxdb.Repository, serviceLabor sLabor.Service) Service {
 	return &service{
 		repositoryAss:    repositoryAss,
 		repositoryOrd:    repositoryOrd,
 		repositorySap:    repositorySap,
 		repositoryInflux: repositoryInflux,
+		serviceLabor:     serviceLabor,
 	}
 }
 
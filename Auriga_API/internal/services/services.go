@@ -5,8 +5,11 @@ import (
 	"github.com/remrafvil/Auriga_API/internal/services/sAuth"
 	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
 	"github.com/remrafvil/Auriga_API/internal/services/sInfluxQuery"
+	"github.com/remrafvil/Auriga_API/internal/services/sInventory"
 	"github.com/remrafvil/Auriga_API/internal/services/sLabor"
 	"github.com/remrafvil/Auriga_API/internal/services/sLabor1"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
 	"github.com/remrafvil/Auriga_API/internal/services/sProducts"
 	"github.com/remrafvil/Auriga_API/internal/services/sSap"
 	"github.com/remrafvil/Auriga_API/internal/services/sUsers"
@@ -19,10 +22,13 @@ var Module = fx.Module("services", fx.Provide(
 	sAuth.New,
 	sProducts.New,
 	sSap.New,
+	sLive.New,
 	sEvents.New,
 	//sLandProperties.New,
 	sUsers.New,
 	sInfluxQuery.New,
 	sLabor1.New,
 	sLabor.New,
+	sMaintenance.New,
+	sInventory.New,
 ))
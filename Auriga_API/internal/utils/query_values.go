@@ -0,0 +1,16 @@
+package utils
+
+import "strings"
+
+// SplitValues admite tanto parámetros repetidos (?status=a&status=b) como listas separadas por comas
+func SplitValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
@@ -15,6 +15,10 @@ import (
 	"github.com/remrafvil/Auriga_API/internal/httpapi/middlewares"
 	"github.com/remrafvil/Auriga_API/internal/repositories"
 	"github.com/remrafvil/Auriga_API/internal/services"
+	"github.com/remrafvil/Auriga_API/internal/services/sEvents"
+	"github.com/remrafvil/Auriga_API/internal/services/sInventory"
+	"github.com/remrafvil/Auriga_API/internal/services/sLive"
+	"github.com/remrafvil/Auriga_API/internal/services/sMaintenance"
 	"github.com/remrafvil/Auriga_API/internal/utils"
 	"gorm.io/gorm"
 
@@ -30,6 +34,10 @@ type Params struct {
 	Config        *config.Settings
 	DB            *gorm.DB
 	InfluxManager *databases.InfluxClientManager // Cambiado de InfluxDB a InfluxManager
+	Maintenance   sMaintenance.Service
+	Inventory     sInventory.Service
+	Events        sEvents.Service
+	Live          sLive.Service
 	Echo          *echo.Echo
 	Handlers      []handlers.Handler `group:"handlers"`
 	Logger        *zap.Logger
@@ -106,6 +114,18 @@ func setLifeCycle(p Params) {
 			// Iniciar monitoreo de conexiones en background
 			go p.InfluxManager.StartConnectionMonitor(monitorCtx)
 
+			// Iniciar planificador de mantenimiento preventivo en background
+			go p.Maintenance.StartPreventiveScheduler(monitorCtx)
+			go p.Maintenance.StartConditionMonitor(monitorCtx)
+			go p.Maintenance.StartStopEventProcessor(monitorCtx)
+			go p.Inventory.StartReorderJob(monitorCtx)
+			go p.Events.StartRawEventIngestion(monitorCtx)
+			go p.Events.StartStopEventCloser(monitorCtx)
+			go p.Events.StartRawEventClassifier(monitorCtx)
+			go p.Events.StartSapOutboxDelivery(monitorCtx)
+			go p.Live.StartLinePoller(monitorCtx)
+			go p.Live.StartListener(monitorCtx)
+
 			// Configurar el validador desde utils
 			validator := utils.NewCustomValidator()
 			p.Echo.Validator = validator